@@ -1,14 +1,54 @@
 package keboola
 
+import "fmt"
+
 //AsStringArray converts an array of interfaces to an array of strings
 //Terraform stores array data within the ResourceData as []interface{}
+//nil entries (e.g. from unset optional values) are skipped, and any other values are formatted as strings
 func AsStringArray(source []interface{}) []string {
 	destination := make([]string, 0, len(source))
 	for _, q := range source {
-		if q != nil {
-			destination = append(destination, q.(string))
+		switch value := q.(type) {
+		case nil:
+			continue
+		case string:
+			destination = append(destination, value)
+		default:
+			destination = append(destination, fmt.Sprintf("%v", value))
 		}
 	}
 
 	return destination
 }
+
+//containsString checks whether an array of strings contains the given value
+func containsString(source []string, value string) bool {
+	for _, q := range source {
+		if q == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+//sameStringSet checks whether two arrays of strings hold the same values, regardless of their order
+func sameStringSet(first []string, second []string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+
+	for _, q := range first {
+		if !containsString(second, q) {
+			return false
+		}
+	}
+
+	for _, q := range second {
+		if !containsString(first, q) {
+			return false
+		}
+	}
+
+	return true
+}
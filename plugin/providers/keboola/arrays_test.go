@@ -0,0 +1,21 @@
+package keboola
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsStringArray(t *testing.T) {
+	assert.Equal(t, []string{}, AsStringArray([]interface{}{}), "An empty list should convert to an empty array")
+	assert.Equal(t, []string{}, AsStringArray(nil), "A nil list should convert to an empty array")
+	assert.Equal(t, []string{"first", "second"}, AsStringArray([]interface{}{nil, "first", nil, "second", nil}), "nil entries should be skipped")
+	assert.Equal(t, []string{"first", "42", "true"}, AsStringArray([]interface{}{"first", 42, true}), "Non-string entries should be formatted as strings")
+}
+
+func TestSameStringSet(t *testing.T) {
+	assert.True(t, sameStringSet([]string{"first", "second"}, []string{"second", "first"}), "Reordered values should be the same set")
+	assert.True(t, sameStringSet([]string{}, nil))
+	assert.False(t, sameStringSet([]string{"first", "second"}, []string{"first"}), "Removed values should not be the same set")
+	assert.False(t, sameStringSet([]string{"first", "first"}, []string{"first", "second"}))
+}
@@ -2,14 +2,150 @@ package keboola
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+const maxRetryDelay = 30 * time.Second
+
+//defaultHost is the Keboola Connection host of the US stack.
+const defaultHost = "connection.keboola.com"
+
+//maxLoggedBodyLength is how much of a request or response body is logged when debugging requests.
+const maxLoggedBodyLength = 2048
+
+//maxIdleConnsPerHost is how many idle connections are kept open to each Keboola API, which matches the
+//number of resources that Terraform applies in parallel by default.
+const maxIdleConnsPerHost = 10
+
+//defaultPageSize is the number of items requested per page when listing paginated endpoints.
+const defaultPageSize = 100
+
+//ProviderVersion is the version of the provider, which is set when building a release, using
+//-ldflags "-X github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola.ProviderVersion=<version>".
+var ProviderVersion = "dev"
+
 //KBCClient is used for communicating with the Keboola Connection API
 type KBCClient struct {
-	APIKey string
+	APIKey         string
+	Host           string
+	UserAgent      string
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	PageSize       int
+
+	//MaxMaintenanceWait is the longest a request waits in total for a project to come out of
+	//maintenance, as indicated by a 503 response with a Retry-After header.
+	MaxMaintenanceWait time.Duration
+
+	//RequestTimeout limits how long each request may take, while UploadTimeout limits uploads of
+	//table data, which can take much longer. No limit is applied when they are zero.
+	RequestTimeout time.Duration
+	UploadTimeout  time.Duration
+
+	//StopContext is cancelled when Terraform is interrupted, so that long running
+	//operations (e.g. waiting for jobs) can be abandoned.
+	StopContext context.Context
+
+	httpClient     *http.Client
+	httpClientOnce sync.Once
+}
+
+//newHTTPClient builds the HTTP client which is shared by every request to the Keboola APIs, so that
+//connections are reused between requests. Timeouts are applied to each request, rather than to the client.
+func newHTTPClient(token string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	if debugRequestLogging() {
+		return &http.Client{Transport: newLoggingTransport(transport, token)}
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+//sharedHTTPClient returns the HTTP client shared by every request, which is built when the provider is
+//configured, or on the first request of a client that was not.
+func (c *KBCClient) sharedHTTPClient() *http.Client {
+	c.httpClientOnce.Do(func() {
+		if c.httpClient == nil {
+			c.httpClient = newHTTPClient(c.APIKey)
+		}
+	})
+
+	return c.httpClient
+}
+
+//requestContext limits a single request to the given timeout, which also covers reading its response.
+//No limit is applied when the timeout is zero.
+func requestContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+//cancelOnClose releases the context of a request once its response has been read and closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+//userAgent identifies requests as being sent by the provider (and by which version of Terraform, when
+//it is known) to Keboola.
+func (c *KBCClient) userAgent() string {
+	if c.UserAgent == "" {
+		return fmt.Sprintf("terraform-provider-keboola/%s", ProviderVersion)
+	}
+
+	return c.UserAgent
+}
+
+//stopContext returns the context that is cancelled when Terraform is interrupted.
+func (c *KBCClient) stopContext() context.Context {
+	if c.StopContext == nil {
+		return context.Background()
+	}
+
+	return c.StopContext
+}
+
+//serviceURL builds the base URL of a Keboola service (e.g. syrup, import) on the same
+//stack as the configured Connection host, e.g. connection.eu-central-1.keboola.com
+//becomes https://syrup.eu-central-1.keboola.com/.
+func (c *KBCClient) serviceURL(service string) string {
+	host := c.Host
+	if host == "" {
+		host = defaultHost
+	}
+
+	if service != "connection" {
+		host = service + "." + strings.TrimPrefix(host, "connection.")
+	}
+
+	return "https://" + host + "/"
 }
 
 //CreateResourceResult holds the results from requesting creation of a Keboola resource.
@@ -21,6 +157,83 @@ func hasErrors(err error, response *http.Response) bool {
 	return err != nil || response.StatusCode < 200 || response.StatusCode > 299
 }
 
+//region Keboola API Contracts
+
+//apiErrorResponse is the body of an error response from the Keboola APIs. Depending on the API, the
+//code is either a string (e.g. storage.tables.notFound) or a number.
+type apiErrorResponse struct {
+	Error       string          `json:"error"`
+	Message     string          `json:"message"`
+	Code        json.RawMessage `json:"code"`
+	ExceptionID string          `json:"exceptionId"`
+}
+
+//endregion
+
+//APIError is returned for a request which a Keboola API responded to with an error. It holds the details
+//that Keboola reports about the error, so that they can be checked (e.g. with errors.As to tell a missing
+//object from a forbidden one), and so that the exception and request IDs can be given to Keboola support.
+type APIError struct {
+	API         string
+	StatusCode  int
+	Message     string
+	Code        string
+	ExceptionID string
+	RequestID   string
+	Method      string
+	URL         string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("Keboola %s error %d", e.API, e.StatusCode)
+
+	var details []string
+
+	if e.ExceptionID != "" {
+		details = append(details, "exceptionId: "+e.ExceptionID)
+	}
+
+	if e.RequestID != "" {
+		details = append(details, "requestId: "+e.RequestID)
+	}
+
+	if len(details) > 0 {
+		message = fmt.Sprintf("%s (%s)", message, strings.Join(details, ", "))
+	}
+
+	if e.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Message)
+	}
+
+	if e.Method != "" {
+		message = fmt.Sprintf("%s\n%s %s", message, e.Method, e.URL)
+	}
+
+	return message
+}
+
+//keboolaAPIName names the Keboola API served from a host, e.g. syrup.eu-central-1.keboola.com is the Syrup API.
+func keboolaAPIName(host string) string {
+	switch strings.SplitN(host, ".", 2)[0] {
+	case "connection":
+		return "Storage API"
+	case "syrup":
+		return "Syrup API"
+	case "import":
+		return "File Import API"
+	case "encryption":
+		return "Encryption API"
+	case "scheduler":
+		return "Scheduler API"
+	case "sandboxes":
+		return "Sandboxes API"
+	default:
+		return "API"
+	}
+}
+
+//extractError returns the error of a failed request, which is an *APIError for a request that Keboola
+//responded to with an error. The Storage API token is redacted from everything in the error.
 func extractError(err error, response *http.Response) error {
 	if err != nil {
 		return err
@@ -29,5 +242,354 @@ func extractError(err error, response *http.Response) error {
 	contentBuffer := new(bytes.Buffer)
 	contentBuffer.ReadFrom(response.Body)
 
-	return fmt.Errorf("%v %s\n%v", response.StatusCode, contentBuffer.String(), response.Request)
+	apiError := &APIError{
+		API:        "API",
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("X-KBC-RequestId"),
+	}
+
+	var errorResponse apiErrorResponse
+
+	if json.Unmarshal(contentBuffer.Bytes(), &errorResponse) == nil && (errorResponse.Error != "" || errorResponse.Message != "") {
+		apiError.Message = errorResponse.Error
+		if apiError.Message == "" {
+			apiError.Message = errorResponse.Message
+		}
+
+		apiError.Code = strings.Trim(string(errorResponse.Code), `"`)
+		apiError.ExceptionID = errorResponse.ExceptionID
+	} else {
+		apiError.Message = strings.TrimSpace(contentBuffer.String())
+	}
+
+	//only the method and URL of the request are included, as its headers contain the Storage API token
+	if response.Request != nil {
+		token := response.Request.Header.Get("X-StorageApi-Token")
+
+		apiError.API = keboolaAPIName(response.Request.URL.Host)
+		apiError.Method = response.Request.Method
+		apiError.URL = redactToken(response.Request.URL.String(), token)
+		apiError.Message = redactToken(apiError.Message, token)
+	}
+
+	return apiError
+}
+
+//sendRequest sends a request to one of the Keboola APIs, retrying with exponential backoff
+//when the request fails with a transient error (i.e. 5xx or 429 responses). While the project is
+//in maintenance, the request is instead retried when the API asks, until MaxMaintenanceWait has
+//passed, without counting towards MaxRetries.
+func (c *KBCClient) sendRequest(method string, requestURL string, body *bytes.Buffer, contentType string) (*http.Response, error) {
+	return c.sendRequestWithTimeout(method, requestURL, body, contentType, c.RequestTimeout)
+}
+
+//sendRequestWithTimeout sends a request as sendRequest does, limiting each attempt to the given timeout.
+func (c *KBCClient) sendRequestWithTimeout(method string, requestURL string, body *bytes.Buffer, contentType string, timeout time.Duration) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		payload = body.Bytes()
+	}
+
+	httpClient := c.sharedHTTPClient()
+
+	//the URL is redacted wherever it is logged or returned in an error, as some endpoints accept the token as a parameter
+	loggedURL := redactToken(requestURL, c.APIKey)
+
+	attempt := 1
+	maintenanceDeadline := time.Now().Add(c.MaxMaintenanceWait)
+
+	for {
+		var requestBody io.Reader
+		if body != nil {
+			requestBody = bytes.NewReader(payload)
+		}
+
+		ctx, cancel := requestContext(timeout)
+
+		req, err := http.NewRequestWithContext(ctx, method, requestURL, requestBody)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		req.Header.Set("X-StorageApi-Token", c.APIKey)
+		req.Header.Set("User-Agent", c.userAgent())
+
+		if contentType != "" {
+			req.Header.Add("content-type", contentType)
+		}
+
+		response, err := httpClient.Do(req)
+
+		if err != nil {
+			cancel()
+		} else {
+			response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+		}
+
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = loggedURL
+		}
+
+		if err == nil && isMaintenance(response) {
+			//maintenance can last longer than maxRetryDelay, so the wait is only limited by max_maintenance_wait
+			delay, _ := retryAfter(response)
+			if delay < c.RetryBaseDelay {
+				delay = c.RetryBaseDelay
+			}
+
+			if time.Now().Add(delay).After(maintenanceDeadline) {
+				return response, fmt.Errorf("Keboola project is in maintenance, and was still unavailable after waiting %s for %s %s (the wait can be increased with max_maintenance_wait)", c.MaxMaintenanceWait, method, loggedURL)
+			}
+
+			log.Printf("[INFO] Keboola project is in maintenance, waiting %s before retrying %s %s", delay, method, loggedURL)
+			response.Body.Close()
+
+			if err := sleepWithContext(c.stopContext(), delay); err != nil {
+				return nil, fmt.Errorf("Cancelled while waiting for Keboola project to come out of maintenance: %v", err)
+			}
+
+			continue
+		}
+
+		if attempt > c.MaxRetries || !isRetryable(method, err, response) {
+			return response, describeTimeout(method, loggedURL, timeout, err)
+		}
+
+		delay := c.retryDelay(attempt, response)
+
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed (%v), retrying in %s (attempt %d of %d)", method, loggedURL, err, delay, attempt, c.MaxRetries)
+		} else {
+			log.Printf("[DEBUG] %s %s failed (status code: %v), retrying in %s (attempt %d of %d)", method, loggedURL, response.StatusCode, delay, attempt, c.MaxRetries)
+			response.Body.Close()
+		}
+
+		if err := sleepWithContext(c.stopContext(), delay); err != nil {
+			return nil, fmt.Errorf("Cancelled while waiting to retry %s %s: %v", method, loggedURL, err)
+		}
+
+		attempt++
+	}
+}
+
+//describeTimeout replaces the error of a request which timed out with one naming the request and
+//the timeout it exceeded. Other errors are returned as they are.
+func describeTimeout(method string, requestURL string, timeout time.Duration, err error) error {
+	if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
+		return fmt.Errorf("%s %s timed out after %s", method, requestURL, timeout)
+	}
+
+	return err
+}
+
+//isMaintenance determines whether a request was refused because the project is in maintenance, in
+//which case the API responds with a 503 and how long to wait before retrying.
+func isMaintenance(response *http.Response) bool {
+	return response.StatusCode == http.StatusServiceUnavailable && response.Header.Get("Retry-After") != ""
+}
+
+//isRetryable determines whether a failed request can safely be sent again. GET and DELETE
+//requests are idempotent, so are retried on any server error, while other requests are only
+//retried when they cannot have been processed, i.e. the connection could not be made, or the
+//API refused them, as rate limited (429) or in maintenance (503 with Retry-After). A 503 without
+//Retry-After may come from a proxy after the request was processed, so is not retried.
+func isRetryable(method string, err error, response *http.Response) bool {
+	idempotent := method == "GET" || method == "DELETE"
+
+	if err != nil {
+		return idempotent || isConnectionError(err)
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests || isMaintenance(response) {
+		return true
+	}
+
+	return idempotent && response.StatusCode >= 500
+}
+
+//isConnectionError checks whether a request failed because no connection could be made to the API,
+//so the request was never sent.
+func isConnectionError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	opErr, ok := err.(*net.OpError)
+
+	return ok && opErr.Op == "dial"
+}
+
+//retryAfter reads how long the API asked to wait before retrying a request from its Retry-After header,
+//which is either a number of seconds or a time.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	retryAfter := response.Header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if retryAt, err := http.ParseTime(retryAfter); err == nil {
+		return time.Until(retryAt), true
+	}
+
+	return 0, false
+}
+
+//retryDelay works out how long to wait before retrying a request, honouring the
+//Retry-After header when the API provides one, up to maxRetryDelay.
+func (c *KBCClient) retryDelay(attempt int, response *http.Response) time.Duration {
+	delay, found := retryAfter(response)
+
+	if !found {
+		delay = time.Duration(float64(c.RetryBaseDelay) * math.Pow(2, float64(attempt-1)))
+	}
+
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
+
+//debugRequestLogging determines whether requests and responses are logged, which is enabled by
+//setting KBC_DEBUG, or by running Terraform with TF_LOG=DEBUG (or TRACE).
+func debugRequestLogging() bool {
+	if os.Getenv("KBC_DEBUG") != "" {
+		return true
+	}
+
+	logLevel := strings.ToUpper(os.Getenv("TF_LOG"))
+
+	return logLevel == "DEBUG" || logLevel == "TRACE"
+}
+
+//redactedToken replaces the Storage API token wherever it would otherwise be logged or returned in an error.
+const redactedToken = "***"
+
+//redactToken replaces every occurrence of the Storage API token in some text, e.g. a URL, log line or error.
+func redactToken(text string, token string) string {
+	if token == "" {
+		return text
+	}
+
+	return strings.Replace(text, token, redactedToken, -1)
+}
+
+//loggingTransport logs each request sent to the Keboola APIs, and the response to it, with the Storage
+//API token redacted from everything that is logged (i.e. the headers, URL and bodies), along with any
+//other secrets in the bodies.
+type loggingTransport struct {
+	transport http.RoundTripper
+	token     string
+}
+
+func newLoggingTransport(transport http.RoundTripper, token string) *loggingTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &loggingTransport{transport: transport, token: token}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.logRequest(req)
+
+	response, err := t.transport.RoundTrip(req)
+
+	t.logResponse(req, response, err)
+
+	return response, err
+}
+
+//logRequest logs the method, URL, headers and (truncated) body of a request, reading the body from a
+//copy of it so that it can still be sent.
+func (t *loggingTransport) logRequest(req *http.Request) {
+	headers := make([]string, 0, len(req.Header))
+
+	for name, values := range req.Header {
+		value := strings.Join(values, ",")
+		if http.CanonicalHeaderKey(name) == "X-Storageapi-Token" {
+			value = redactedToken
+		}
+
+		headers = append(headers, fmt.Sprintf("%s: %s", name, value))
+	}
+
+	var payload []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			payload, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	log.Print(redactToken(fmt.Sprintf("[DEBUG] Keboola API request: %s %s\n%s\n%s", req.Method, req.URL, strings.Join(headers, "\n"), loggedBody(req, req.Header.Get("Content-Type"), payload)), t.token))
+}
+
+//logResponse logs the status code and (truncated) body of a response, leaving the body to be read
+//again by the caller.
+func (t *loggingTransport) logResponse(req *http.Request, response *http.Response, err error) {
+	if err != nil {
+		log.Print(redactToken(fmt.Sprintf("[DEBUG] Keboola API response: %s %s failed: %v", req.Method, req.URL, err), t.token))
+		return
+	}
+
+	body, readErr := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if readErr != nil {
+		log.Print(redactToken(fmt.Sprintf("[DEBUG] Keboola API response: %s %s (status code: %v), unable to read body: %v", req.Method, req.URL, response.StatusCode, readErr), t.token))
+		return
+	}
+
+	log.Print(redactToken(fmt.Sprintf("[DEBUG] Keboola API response: %s %s (status code: %v)\n%s", req.Method, req.URL, response.StatusCode, loggedBody(req, response.Header.Get("Content-Type"), body)), t.token))
+}
+
+//notLoggedBody replaces the bodies of requests to (and responses from) the Encryption API, which are the
+//secrets being encrypted.
+const notLoggedBody = "(body not logged, as it holds a secret)"
+
+//secretFieldPattern matches the values of JSON fields which hold secrets, i.e. tokens (e.g. of a newly
+//created access token), and the fields prefixed with # which Keboola encrypts.
+var secretFieldPattern = regexp.MustCompile(`("(?:token|#[^"]*)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+//redactSecretFields replaces the values of any fields holding secrets within a JSON body.
+func redactSecretFields(body string) string {
+	return secretFieldPattern.ReplaceAllString(body, `${1}"`+redactedToken+`"`)
+}
+
+//loggedBody returns the (truncated) body of a request or response as it is logged, with any secrets in it
+//redacted. Form values (e.g. a configuration) are redacted individually, as they are JSON themselves.
+func loggedBody(req *http.Request, contentType string, body []byte) string {
+	if keboolaAPIName(req.URL.Host) == "Encryption API" {
+		return notLoggedBody
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for _, values := range form {
+				for i := range values {
+					values[i] = redactSecretFields(values[i])
+				}
+			}
+
+			return truncateLoggedBody([]byte(form.Encode()))
+		}
+	}
+
+	return truncateLoggedBody([]byte(redactSecretFields(string(body))))
+}
+
+func truncateLoggedBody(body []byte) string {
+	if len(body) > maxLoggedBodyLength {
+		return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxLoggedBodyLength], len(body)-maxLoggedBodyLength)
+	}
+
+	return string(body)
 }
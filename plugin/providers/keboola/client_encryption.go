@@ -0,0 +1,94 @@
+package keboola
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//encryptedValuePrefix is the prefix of every value encrypted by the Keboola Encryption API.
+const encryptedValuePrefix = "KBC::"
+
+//region Keboola API Contracts
+
+//TokenVerification is the subset of the Storage API token verification
+//response needed to identify the project a token belongs to.
+type TokenVerification struct {
+	Owner struct {
+		ID json.Number `json:"id"`
+	} `json:"owner"`
+}
+
+//endregion
+
+//encryptionURL is the base URL of the Keboola Encryption API on the configured stack.
+func (c *KBCClient) encryptionURL() string {
+	return c.serviceURL("encryption")
+}
+
+//PostToEncryption posts a plain text value to the Keboola Encryption API.
+func (c *KBCClient) PostToEncryption(endpoint string, payload *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("POST", c.encryptionURL()+endpoint, payload, "text/plain")
+}
+
+//EncryptValue encrypts a value using the Keboola Encryption API, so that it can only be decrypted
+//by the given component within the current project. Values which have already been encrypted are
+//returned unchanged, so that encrypting an unchanged secret again does not cause a diff.
+func (c *KBCClient) EncryptValue(componentID string, value string) (string, error) {
+	if isEncryptedValue(value) {
+		return value, nil
+	}
+
+	projectID, err := c.projectID()
+
+	if err != nil {
+		return "", err
+	}
+
+	encryptQuery := url.Values{}
+	encryptQuery.Add("componentId", componentID)
+	encryptQuery.Add("projectId", projectID)
+
+	encryptResponse, err := c.PostToEncryption(fmt.Sprintf("encrypt?%s", encryptQuery.Encode()), bytes.NewBufferString(value))
+
+	if hasErrors(err, encryptResponse) {
+		return "", extractError(err, encryptResponse)
+	}
+
+	encryptedValue, err := ioutil.ReadAll(encryptResponse.Body)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(encryptedValue)), nil
+}
+
+//isEncryptedValue returns whether the value has already been encrypted by the Keboola Encryption API.
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+//projectID returns the ID of the project which the configured Storage API token belongs to.
+func (c *KBCClient) projectID() (string, error) {
+	verifyResponse, err := c.GetFromStorage("storage/tokens/verify")
+
+	if hasErrors(err, verifyResponse) {
+		return "", extractError(err, verifyResponse)
+	}
+
+	var tokenVerification TokenVerification
+
+	decoder := json.NewDecoder(verifyResponse.Body)
+	err = decoder.Decode(&tokenVerification)
+
+	if err != nil {
+		return "", err
+	}
+
+	return tokenVerification.Owner.ID.String(), nil
+}
@@ -0,0 +1,131 @@
+package keboola
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptValue(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "connection.example.com":
+			assert.Equal(t, "/v2/storage/tokens/verify", r.URL.Path)
+			w.Write([]byte(`{ "id": "1234", "owner": { "id": 567, "name": "Test Project" } }`))
+		case "encryption.example.com":
+			assert.Equal(t, "/encrypt", r.URL.Path)
+			assert.Equal(t, "keboola.wr-db-snowflake", r.URL.Query().Get("componentId"))
+			assert.Equal(t, "567", r.URL.Query().Get("projectId"))
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, "secret", string(body))
+
+			w.Write([]byte("KBC::ProjectSecure::encrypted-secret"))
+		default:
+			t.Errorf("Unexpected request to %s%s", r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	encryptedValue, err := client.EncryptValue("keboola.wr-db-snowflake", "secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "KBC::ProjectSecure::encrypted-secret", encryptedValue)
+}
+
+func TestEncryptValueKeepsEncryptedValues(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to %s%s", r.Host, r.URL.Path)
+	})
+	defer server.Close()
+
+	for _, value := range []string{"KBC::ProjectSecure::already-encrypted", "KBC::ComponentSecure::already-encrypted"} {
+		encryptedValue, err := client.EncryptValue("keboola.wr-db-snowflake", value)
+
+		assert.NoError(t, err)
+		assert.Equal(t, value, encryptedValue, "An encrypted value should not be encrypted again")
+	}
+}
+
+func TestEncryptSnowflakeCredentialsReplacesPassword(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "connection.example.com" {
+			w.Write([]byte(`{ "owner": { "id": 567 } }`))
+		} else {
+			w.Write([]byte("KBC::ProjectSecure::encrypted-secret"))
+		}
+	})
+	defer server.Close()
+
+	source := map[string]interface{}{
+		"hostname": "example.snowflakecomputing.com",
+		"username": "writer",
+		"password": "secret",
+	}
+
+	credentials, err := encryptSnowflakeCredentials(source, "keboola.wr-db-snowflake", client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "KBC::ProjectSecure::encrypted-secret", credentials["hashed_password"])
+	assert.NotContains(t, credentials, "password", "The plain text password should not be kept")
+	assert.Equal(t, "secret", source["password"], "The source credentials should not be modified")
+
+	configuration := mapSnowflakeCredentialsToConfiguration(credentials)
+	assert.Equal(t, "KBC::ProjectSecure::encrypted-secret", configuration.EncryptedPassword)
+	assert.Empty(t, configuration.Password)
+}
+
+func TestSnowflakeCredentialsUnchanged(t *testing.T) {
+	client := &KBCClient{APIKey: "token"}
+	previous := map[string]interface{}{
+		"hostname": "example.snowflakecomputing.com",
+		"username": "writer",
+		"password": "KBC::ProjectSecure::encrypted-secret",
+	}
+	secretHashes := map[string]interface{}{
+		"snowflake_db_parameters_password": client.secretHash("1234", snowflakePasswordKey, "KBC::ProjectSecure::encrypted-secret", "secret"),
+	}
+
+	configured := map[string]interface{}{
+		"hostname": "example.snowflakecomputing.com",
+		"username": "writer",
+		"password": "secret",
+	}
+
+	assert.True(t, snowflakeCredentialsUnchanged(client, "1234", previous, configured, secretHashes))
+
+	configured["password"] = "changed"
+	assert.False(t, snowflakeCredentialsUnchanged(client, "1234", previous, configured, secretHashes), "A changed password should be encrypted again")
+
+	configured["password"] = "secret"
+	configured["username"] = "reader"
+	assert.False(t, snowflakeCredentialsUnchanged(client, "1234", previous, configured, secretHashes), "Changes to the other credentials should be kept")
+}
+
+func TestSnowflakeExtractorReadKeepsCiphertextOfPassword(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "1234", "name": "snowflake", "configuration": { "parameters": { "db": { "host": "example.snowflakecomputing.com", "user": "writer", "#password": "KBC::ProjectSecure::changed" } } } }`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaSnowflakeExtractor().Data(&terraform.InstanceState{
+		ID: "1234",
+		Attributes: map[string]string{
+			"name":                             "snowflake",
+			"snowflake_db_parameters.%":        "3",
+			"snowflake_db_parameters.hostname": "example.snowflakecomputing.com",
+			"snowflake_db_parameters.username": "writer",
+			"snowflake_db_parameters.password": "KBC::ProjectSecure::existing",
+		},
+	})
+
+	err := resourceKeboolaSnowflakeExtractorRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "KBC::ProjectSecure::changed", d.Get(snowflakePasswordKey), "The ciphertext of the password should be kept in its place")
+	assert.Equal(t, "", d.Get("snowflake_db_parameters.hashed_password"))
+}
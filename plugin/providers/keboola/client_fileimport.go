@@ -5,17 +5,13 @@ import (
 	"net/http"
 )
 
-const fileImportURL = "https://import.keboola.com/"
+//fileImportURL is the base URL of the Keboola File Import API on the configured stack.
+func (c *KBCClient) fileImportURL() string {
+	return c.serviceURL("import")
+}
 
-//PostToFileImport posts a new object to the Keboola File Import API.
+//PostToFileImport posts a new object to the Keboola File Import API. Uploads are limited by UploadTimeout
+//rather than RequestTimeout, as uploading the data of a large table can take a long time.
 func (c *KBCClient) PostToFileImport(endpoint string, formdata *bytes.Buffer) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", fileImportURL+endpoint, formdata)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("X-StorageApi-Token", c.APIKey)
-	req.Header.Add("content-type", "multipart/form-data; boundary=----terraform-provider-keboola----")
-	return client.Do(req)
+	return c.sendRequestWithTimeout("POST", c.fileImportURL()+endpoint, formdata, "multipart/form-data; boundary=----terraform-provider-keboola----", c.UploadTimeout)
 }
@@ -0,0 +1,26 @@
+package keboola
+
+import (
+	"bytes"
+	"net/http"
+)
+
+//sandboxesURL is the base URL of the Keboola Sandboxes API on the configured stack.
+func (c *KBCClient) sandboxesURL() string {
+	return c.serviceURL("sandboxes")
+}
+
+//GetFromSandboxes requests an object from the Keboola Sandboxes API.
+func (c *KBCClient) GetFromSandboxes(endpoint string) (*http.Response, error) {
+	return c.sendRequest("GET", c.sandboxesURL()+endpoint, nil, "")
+}
+
+//PostToSandboxes posts a new object to the Keboola Sandboxes API.
+func (c *KBCClient) PostToSandboxes(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("POST", c.sandboxesURL()+endpoint, jsonpayload, "application/json")
+}
+
+//DeleteFromSandboxes removes an existing object from the Keboola Sandboxes API.
+func (c *KBCClient) DeleteFromSandboxes(endpoint string) (*http.Response, error) {
+	return c.sendRequest("DELETE", c.sandboxesURL()+endpoint, nil, "")
+}
@@ -0,0 +1,26 @@
+package keboola
+
+import (
+	"bytes"
+	"net/http"
+)
+
+//schedulerURL is the base URL of the Keboola Scheduler API on the configured stack.
+func (c *KBCClient) schedulerURL() string {
+	return c.serviceURL("scheduler")
+}
+
+//GetFromScheduler requests an object from the Keboola Scheduler API.
+func (c *KBCClient) GetFromScheduler(endpoint string) (*http.Response, error) {
+	return c.sendRequest("GET", c.schedulerURL()+endpoint, nil, "")
+}
+
+//PostToScheduler posts a new object to the Keboola Scheduler API.
+func (c *KBCClient) PostToScheduler(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("POST", c.schedulerURL()+endpoint, jsonpayload, "application/json")
+}
+
+//DeleteFromScheduler removes an existing object from the Keboola Scheduler API.
+func (c *KBCClient) DeleteFromScheduler(endpoint string) (*http.Response, error) {
+	return c.sendRequest("DELETE", c.schedulerURL()+endpoint, nil, "")
+}
@@ -2,57 +2,138 @@ package keboola
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 )
 
-const storageURL = "https://connection.keboola.com/v2/"
+//storageURL is the base URL of the Keboola Storage API on the configured stack.
+func (c *KBCClient) storageURL() string {
+	return c.serviceURL("connection") + "v2/"
+}
 
 //GetFromStorage requests an object from the Keboola Storage API.
 func (c *KBCClient) GetFromStorage(endpoint string) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", storageURL+endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("X-StorageApi-Token", c.APIKey)
-	return client.Do(req)
+	return c.sendRequest("GET", c.storageURL()+endpoint, nil, "")
 }
 
 //PostToStorage posts a new object to the Keboola Storage API.
 func (c *KBCClient) PostToStorage(endpoint string, formdata *bytes.Buffer) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", storageURL+endpoint, formdata)
-	if err != nil {
-		return nil, err
-	}
+	return c.sendRequest("POST", c.storageURL()+endpoint, formdata, "application/x-www-form-urlencoded")
+}
 
-	req.Header.Set("X-StorageApi-Token", c.APIKey)
-	req.Header.Add("content-type", "application/x-www-form-urlencoded")
-	return client.Do(req)
+//PostJSONToStorage posts a new object to the Keboola Storage API as JSON, for endpoints
+//that do not accept form data.
+func (c *KBCClient) PostJSONToStorage(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("POST", c.storageURL()+endpoint, jsonpayload, "application/json")
 }
 
 //PutToStorage puts an existing object to the Keboola Storage API for update.
 func (c *KBCClient) PutToStorage(endpoint string, formData *bytes.Buffer) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("PUT", storageURL+endpoint, formData)
-	if err != nil {
-		return nil, err
-	}
+	return c.sendRequest("PUT", c.storageURL()+endpoint, formData, "application/x-www-form-urlencoded")
+}
 
-	req.Header.Set("X-StorageApi-Token", c.APIKey)
-	req.Header.Add("content-type", "application/x-www-form-urlencoded")
-	return client.Do(req)
+//PutJSONToStorage puts an existing object to the Keboola Storage API for update as JSON, for endpoints
+//that do not accept form data.
+func (c *KBCClient) PutJSONToStorage(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("PUT", c.storageURL()+endpoint, jsonpayload, "application/json")
+}
+
+//PatchToStorage partially updates an existing object in the Keboola Storage API.
+func (c *KBCClient) PatchToStorage(endpoint string, formData *bytes.Buffer) (*http.Response, error) {
+	return c.sendRequest("PATCH", c.storageURL()+endpoint, formData, "application/x-www-form-urlencoded")
 }
 
 //DeleteFromStorage removes an existing object from the Keboola Storage API.
 func (c *KBCClient) DeleteFromStorage(endpoint string) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("DELETE", storageURL+endpoint, nil)
+	return c.sendRequest("DELETE", c.storageURL()+endpoint, nil, "")
+}
+
+//verifyToken checks that the Storage API token is accepted by the configured stack. Tokens are specific
+//to the stack of their project, so a token of a project on another stack is rejected as unauthorised.
+func (c *KBCClient) verifyToken() error {
+	verifyResponse, err := c.GetFromStorage("storage/tokens/verify")
+
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("Unable to connect to Keboola at %s, check that host is the Connection host of a Keboola stack: %v", c.serviceURL("connection"), err)
+	}
+
+	if verifyResponse.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("Storage API token does not belong to this stack (%s), check that host is set to the stack of the token's project, and that the token has not expired or been revoked", c.serviceURL("connection"))
+	}
+
+	if hasErrors(err, verifyResponse) {
+		return extractError(err, verifyResponse)
+	}
+
+	return nil
+}
+
+//GetAllFromStorage requests every item of a list endpoint of the Keboola Storage API, following
+//the offset/limit pagination of the endpoint page by page, so that large lists are not truncated.
+//It should only be used for endpoints which support offset. Endpoints which do not paginate return
+//every item in the first page, or the same items again for each page, either of which ends the listing.
+func (c *KBCClient) GetAllFromStorage(endpoint string) ([]json.RawMessage, error) {
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	var items []json.RawMessage
+	listed := make(map[string]bool)
+
+	for offset := 0; ; offset += pageSize {
+		getResponse, err := c.GetFromStorage(fmt.Sprintf("%s%slimit=%d&offset=%d", endpoint, separator, pageSize, offset))
+
+		if hasErrors(err, getResponse) {
+			return nil, extractError(err, getResponse)
+		}
+
+		var page []json.RawMessage
+
+		decoder := json.NewDecoder(getResponse.Body)
+		err = decoder.Decode(&page)
+		getResponse.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		newItems := 0
+
+		for _, item := range page {
+			key := listedItemKey(item)
+
+			if !listed[key] {
+				listed[key] = true
+				items = append(items, item)
+				newItems++
+			}
+		}
+
+		//a short page is the last one, a page larger than requested means the limit was ignored,
+		//and a page without any new items means the offset was ignored
+		if len(page) != pageSize || newItems == 0 {
+			return items, nil
+		}
+	}
+}
+
+//listedItemKey identifies an item of a list by its ID, or by its content when it has no ID.
+func listedItemKey(item json.RawMessage) string {
+	var identified struct {
+		ID json.RawMessage `json:"id"`
+	}
+
+	if err := json.Unmarshal(item, &identified); err == nil && len(identified.ID) > 0 {
+		return string(identified.ID)
 	}
 
-	req.Header.Set("X-StorageApi-Token", c.APIKey)
-	return client.Do(req)
+	return string(item)
 }
@@ -0,0 +1,570 @@
+package keboola
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRequestRetriesTransientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 3, RetryBaseDelay: time.Millisecond}
+	response, err := client.sendRequest("GET", server.URL, nil, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode, "Request should succeed once the transient errors have passed")
+	assert.Equal(t, 3, requests, "Request should have been retried until it succeeded")
+}
+
+func TestSendRequestDoesNotRetryNonIdempotentServerErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 3, RetryBaseDelay: time.Millisecond}
+	response, err := client.sendRequest("POST", server.URL, buffer.Empty(), "application/x-www-form-urlencoded")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.Equal(t, 1, requests, "POST requests should not be retried on a 500, as they may already have been processed")
+}
+
+func TestSendRequestResendsBodyWhenRetryingThrottledPost(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) < 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 3, RetryBaseDelay: time.Millisecond}
+	response, err := client.sendRequest("POST", server.URL, bytes.NewBufferString("name=orders"), "application/x-www-form-urlencoded")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{"name=orders", "name=orders"}, bodies, "The whole body should be sent again when retrying")
+}
+
+func TestSendRequestStopsRetryingWhenCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	stopContext, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 3, RetryBaseDelay: time.Hour, StopContext: stopContext}
+	_, err := client.sendRequest("GET", server.URL, nil, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Cancelled while waiting to retry GET")
+	assert.Equal(t, 1, requests, "Request should not be retried once Terraform has been interrupted")
+}
+
+func TestIsRetryable(t *testing.T) {
+	connectionError := &url.Error{Op: "Post", URL: "https://connection.keboola.com/", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	readError := &url.Error{Op: "Post", URL: "https://connection.keboola.com/", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}
+
+	assert.True(t, isRetryable("POST", connectionError, nil), "A request which could not connect was never sent, so can be retried")
+	assert.False(t, isRetryable("POST", readError, nil), "A request which failed after being sent may already have been processed")
+	assert.True(t, isRetryable("GET", readError, nil))
+	assert.True(t, isRetryable("DELETE", readError, nil))
+
+	assert.True(t, isRetryable("POST", nil, &http.Response{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, isRetryable("POST", nil, &http.Response{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isRetryable("POST", nil, &http.Response{StatusCode: http.StatusServiceUnavailable}), "A 503 without Retry-After may come from a request which was processed")
+	assert.True(t, isRetryable("POST", nil, &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"30"}}}))
+	assert.True(t, isRetryable("GET", nil, &http.Response{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, isRetryable("GET", nil, &http.Response{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isRetryable("GET", nil, &http.Response{StatusCode: http.StatusNotFound}))
+}
+
+func TestSendRequestWaitsForMaintenance(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 0, RetryBaseDelay: time.Millisecond, MaxMaintenanceWait: time.Minute}
+	response, err := client.sendRequest("POST", server.URL, buffer.Empty(), "application/x-www-form-urlencoded")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode, "Request should succeed once maintenance has finished")
+	assert.Equal(t, 3, requests, "Waiting for maintenance should not count towards the maximum number of retries")
+}
+
+func TestSendRequestFailsWhenMaintenanceOutlastsWait(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", MaxRetries: 3, RetryBaseDelay: time.Millisecond, MaxMaintenanceWait: 10 * time.Minute}
+	_, err := client.sendRequest("GET", server.URL, nil, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Keboola project is in maintenance")
+	assert.Equal(t, 1, requests, "Request should not wait when maintenance will outlast the maximum wait")
+}
+
+func TestSendRequestTimeoutNamesRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", RequestTimeout: 20 * time.Millisecond}
+	_, err := client.sendRequest("GET", server.URL+"/v2/storage/buckets", nil, "")
+
+	assert.EqualError(t, err, "GET "+server.URL+"/v2/storage/buckets timed out after 20ms")
+}
+
+func TestPostToFileImportUsesUploadTimeout(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{ "id": 777 }`))
+	})
+	defer server.Close()
+
+	client.RequestTimeout = 20 * time.Millisecond
+	client.UploadTimeout = time.Minute
+
+	response, err := client.PostToFileImport("upload-file", buffer.Empty())
+
+	assert.NoError(t, err, "Uploads should only be limited by the upload timeout")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestSendRequestReusesConnections(t *testing.T) {
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 1 }`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections++
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", RequestTimeout: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		response, err := client.sendRequest("GET", server.URL, nil, "")
+
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+
+		assert.NoError(t, err, "The body should be readable within the timeout of the request")
+		assert.Equal(t, `{ "id": 1 }`, string(body))
+	}
+
+	assert.Equal(t, 1, connections, "Requests should share a single client, and so a single connection")
+}
+
+func TestSendRequestIdentifiesProvider(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+	}))
+	defer server.Close()
+
+	client := &KBCClient{APIKey: "test", UserAgent: providerUserAgent("0.11.14")}
+	_, err := client.sendRequest("GET", server.URL, nil, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "terraform-provider-keboola/"+ProviderVersion+" Terraform/0.11.14", headers.Get("User-Agent"))
+	assert.Equal(t, []string{"test"}, headers["X-Storageapi-Token"], "The token should be sent once")
+
+	client.UserAgent = ""
+	client.sendRequest("GET", server.URL, nil, "")
+
+	assert.Equal(t, "terraform-provider-keboola/"+ProviderVersion, headers.Get("User-Agent"))
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &KBCClient{RetryBaseDelay: time.Second}
+
+	assert.Equal(t, time.Second, client.retryDelay(1, nil))
+	assert.Equal(t, 4*time.Second, client.retryDelay(3, nil))
+	assert.Equal(t, maxRetryDelay, client.retryDelay(10, nil), "Retry delay should be capped")
+
+	response := &http.Response{Header: http.Header{}}
+	response.Header.Set("Retry-After", "7")
+
+	assert.Equal(t, 7*time.Second, client.retryDelay(1, response), "Retry-After header should be honoured")
+
+	response.Header.Set("Retry-After", "3600")
+
+	assert.Equal(t, maxRetryDelay, client.retryDelay(1, response), "Retry-After header should be capped")
+}
+
+func TestServiceURLsFollowConfiguredHost(t *testing.T) {
+	client := &KBCClient{}
+
+	assert.Equal(t, "https://connection.keboola.com/v2/", client.storageURL(), "Storage API should default to the US stack")
+	assert.Equal(t, "https://syrup.keboola.com/", client.syrupURL(), "Syrup API should default to the US stack")
+	assert.Equal(t, "https://import.keboola.com/", client.fileImportURL(), "File Import API should default to the US stack")
+
+	client.Host = "connection.north-europe.azure.keboola.com"
+
+	assert.Equal(t, "https://connection.north-europe.azure.keboola.com/v2/", client.storageURL())
+	assert.Equal(t, "https://syrup.north-europe.azure.keboola.com/", client.syrupURL())
+	assert.Equal(t, "https://import.north-europe.azure.keboola.com/", client.fileImportURL())
+}
+
+func TestVerifyToken(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/tokens/verify", r.URL.Path)
+		w.Write([]byte(`{ "owner": { "id": 567 } }`))
+	})
+	defer server.Close()
+
+	assert.NoError(t, client.verifyToken())
+}
+
+func TestVerifyTokenRejectsTokenOfAnotherStack(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{ "error": "Invalid access token", "code": "storage.tokenInvalid" }`))
+	})
+	defer server.Close()
+
+	err := client.verifyToken()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Storage API token does not belong to this stack")
+}
+
+func TestUpdatesToStorage(t *testing.T) {
+	var method, contentType, body string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/buckets/in.c-bucket", r.URL.Path)
+		assert.Equal(t, "test", r.Header.Get("X-StorageApi-Token"))
+
+		requestBody, _ := ioutil.ReadAll(r.Body)
+
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		body = string(requestBody)
+	})
+	defer server.Close()
+
+	_, err := client.PutToStorage("storage/buckets/in.c-bucket", buffer.FromForm(url.Values{"description": {"orders"}}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", method)
+	assert.Equal(t, "application/x-www-form-urlencoded", contentType)
+	assert.Equal(t, "description=orders", body)
+
+	_, err = client.PutJSONToStorage("storage/buckets/in.c-bucket", bytes.NewBufferString(`{"description":"orders"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", method)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, `{"description":"orders"}`, body)
+
+	_, err = client.PatchToStorage("storage/buckets/in.c-bucket", buffer.FromForm(url.Values{"displayName": {"orders"}}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PATCH", method)
+	assert.Equal(t, "application/x-www-form-urlencoded", contentType)
+	assert.Equal(t, "displayName=orders", body)
+}
+
+func TestGetAllFromStorageFollowsPagination(t *testing.T) {
+	var requestedPages []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/events", r.URL.Path)
+		assert.Equal(t, "storage", r.URL.Query().Get("component"), "Existing query parameters should be kept")
+		requestedPages = append(requestedPages, r.URL.Query().Get("offset"))
+
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`[ { "id": 1 }, { "id": 2 } ]`))
+		case "2":
+			w.Write([]byte(`[ { "id": 3 } ]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+	defer server.Close()
+
+	client.PageSize = 2
+
+	items, err := client.GetAllFromStorage("storage/events?component=storage")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "2"}, requestedPages, "Pages should be requested until a short page is returned")
+	assert.Len(t, items, 3)
+	assert.JSONEq(t, `{ "id": 3 }`, string(items[2]))
+}
+
+func TestGetAllFromStorageStopsWhenLimitIsIgnored(t *testing.T) {
+	requests := 0
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[ { "id": 1 }, { "id": 2 }, { "id": 3 } ]`))
+	})
+	defer server.Close()
+
+	client.PageSize = 2
+
+	items, err := client.GetAllFromStorage("storage/buckets")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests, "An endpoint which does not paginate should only be requested once")
+	assert.Len(t, items, 3)
+}
+
+func TestGetAllFromStorageStopsWhenOffsetIsIgnored(t *testing.T) {
+	requests := 0
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[ { "id": 1 }, { "id": 2 } ]`))
+	})
+	defer server.Close()
+
+	client.PageSize = 2
+
+	items, err := client.GetAllFromStorage("storage/buckets")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests, "Listing should stop once a page has no new items")
+	assert.Len(t, items, 2, "Repeated items should not be listed again")
+}
+
+func TestSendRequestLogsRequestsWhenDebugging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "error": "Invalid bucket name" }`))
+	}))
+	defer server.Close()
+
+	var logOutput bytes.Buffer
+	log.SetOutput(&logOutput)
+	defer log.SetOutput(os.Stderr)
+
+	os.Setenv("KBC_DEBUG", "1")
+	defer os.Unsetenv("KBC_DEBUG")
+
+	client := &KBCClient{APIKey: "secret-token"}
+	response, err := client.sendRequest("POST", server.URL+"/v2/storage/buckets", buffer.FromForm(map[string][]string{"name": {"bucket"}}), "application/x-www-form-urlencoded")
+
+	assert.NoError(t, err)
+
+	body, _ := ioutil.ReadAll(response.Body)
+	assert.Equal(t, `{ "error": "Invalid bucket name" }`, string(body), "The response body should still be readable after being logged")
+
+	logged := logOutput.String()
+	assert.Contains(t, logged, "POST "+server.URL+"/v2/storage/buckets")
+	assert.Contains(t, logged, "name=bucket")
+	assert.Contains(t, logged, "status code: 400")
+	assert.Contains(t, logged, "Invalid bucket name")
+	assert.NotContains(t, logged, "secret-token", "The Storage API token should be redacted")
+}
+
+func TestSendRequestRedactsTokenEverywhere(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{ "error": "Token secret-token is not allowed to read this file" }`))
+	}))
+	defer server.Close()
+
+	var logOutput bytes.Buffer
+	log.SetOutput(&logOutput)
+	defer log.SetOutput(os.Stderr)
+
+	os.Setenv("KBC_DEBUG", "1")
+	defer os.Unsetenv("KBC_DEBUG")
+
+	client := &KBCClient{APIKey: "secret-token"}
+	response, err := client.sendRequest("GET", server.URL+"/v2/storage/files/1?token=secret-token", nil, "")
+
+	assert.NoError(t, err)
+
+	err = extractError(err, response)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GET "+server.URL+"/v2/storage/files/1?token=***")
+	assert.NotContains(t, err.Error(), "secret-token", "The Storage API token should not be included in errors")
+
+	logged := logOutput.String()
+	assert.Contains(t, logged, "X-Storageapi-Token: ***")
+	assert.Contains(t, logged, "/v2/storage/files/1?token=***")
+	assert.NotContains(t, logged, "secret-token", "The Storage API token should be redacted from the URL and bodies as well as the headers")
+}
+
+func TestSendRequestDoesNotLogSecretsBeingEncrypted(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("KBC::ProjectSecure::encrypted"))
+	})
+	defer server.Close()
+
+	var logOutput bytes.Buffer
+	log.SetOutput(&logOutput)
+	defer log.SetOutput(os.Stderr)
+
+	client.httpClient.Transport = newLoggingTransport(client.httpClient.Transport, client.APIKey)
+
+	response, err := client.PostToEncryption("encrypt?componentId=keboola.ex-db-mysql", bytes.NewBufferString("plain-password"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	logged := logOutput.String()
+	assert.Contains(t, logged, "POST https://encryption.example.com/encrypt?componentId=keboola.ex-db-mysql")
+	assert.Contains(t, logged, notLoggedBody)
+	assert.NotContains(t, logged, "plain-password", "The secret being encrypted should not be logged")
+	assert.NotContains(t, logged, "KBC::ProjectSecure::encrypted")
+}
+
+func TestSendRequestRedactsSecretsFromLoggedBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{ "id": "123", "token": "123-new-access-token" }`))
+	}))
+	defer server.Close()
+
+	var logOutput bytes.Buffer
+	log.SetOutput(&logOutput)
+	defer log.SetOutput(os.Stderr)
+
+	os.Setenv("KBC_DEBUG", "1")
+	defer os.Unsetenv("KBC_DEBUG")
+
+	configuration := map[string][]string{"configuration": {`{ "parameters": { "db": { "host": "db.example.com", "#password": "plain-password" } } }`}}
+
+	client := &KBCClient{APIKey: "secret-token"}
+	response, err := client.sendRequest("POST", server.URL+"/v2/storage/tokens", buffer.FromForm(configuration), "application/x-www-form-urlencoded")
+
+	assert.NoError(t, err)
+
+	body, _ := ioutil.ReadAll(response.Body)
+	assert.Contains(t, string(body), "123-new-access-token", "Only the logged body should be redacted")
+
+	logged := logOutput.String()
+	assert.Contains(t, logged, "db.example.com")
+	assert.NotContains(t, logged, "plain-password", "Secrets within a form should be redacted")
+	assert.NotContains(t, logged, "123-new-access-token", "A newly created token should be redacted")
+}
+
+func TestRedactSecretFields(t *testing.T) {
+	assert.Equal(t, `{ "token": "***", "#password": "***", "user": "keboola" }`, redactSecretFields(`{ "token": "abc", "#password": "p\"ss", "user": "keboola" }`))
+}
+
+func TestSendRequestRedactsTokenFromConnectionErrors(t *testing.T) {
+	client := &KBCClient{APIKey: "secret-token"}
+	_, err := client.sendRequest("POST", "http://127.0.0.1:1/v2/storage/files?token=secret-token", nil, "")
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestExtractErrorReturnsAPIError(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-KBC-RequestId", "xyz")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "error": "Invalid delimiter", "code": "storage.tables.validation.invalidDelimiter", "status": "error", "exceptionId": "abc-123" }`))
+	})
+	defer server.Close()
+
+	response, err := client.PostToStorage("storage/buckets/in.c-bucket/tables-async", bytes.NewBufferString("delimiter=;;"))
+
+	assert.True(t, hasErrors(err, response))
+
+	err = extractError(err, response)
+
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "An error response should be returned as an APIError")
+	assert.Equal(t, http.StatusBadRequest, apiError.StatusCode)
+	assert.Equal(t, "storage.tables.validation.invalidDelimiter", apiError.Code)
+	assert.Equal(t, "abc-123", apiError.ExceptionID)
+	assert.Equal(t, "xyz", apiError.RequestID)
+	assert.EqualError(t, err, "Keboola Storage API error 400 (exceptionId: abc-123, requestId: xyz): Invalid delimiter\nPOST https://connection.example.com/v2/storage/buckets/in.c-bucket/tables-async")
+}
+
+func TestExtractErrorKeepsBodyWhichIsNotAnAPIError(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>\n"))
+	})
+	defer server.Close()
+
+	client.MaxRetries = 0
+
+	response, err := client.PostToSyrup("docker/keboola.ex-db-mysql/run", bytes.NewBufferString("{}"))
+	err = extractError(err, response)
+
+	assert.EqualError(t, err, "Keboola Syrup API error 502: <html>Bad Gateway</html>\nPOST https://syrup.example.com/docker/keboola.ex-db-mysql/run")
+
+	wrapped := fmt.Errorf("Unable to run job: %w", err)
+
+	var apiError *APIError
+	assert.True(t, errors.As(wrapped, &apiError), "An APIError should still be found once wrapped")
+	assert.Equal(t, http.StatusBadGateway, apiError.StatusCode)
+	assert.Equal(t, "", apiError.ExceptionID)
+}
+
+func TestTruncateLoggedBody(t *testing.T) {
+	assert.Equal(t, "short", truncateLoggedBody([]byte("short")))
+
+	truncated := truncateLoggedBody([]byte(strings.Repeat("a", maxLoggedBodyLength+10)))
+	assert.True(t, strings.HasSuffix(truncated, "... (10 bytes truncated)"))
+}
@@ -0,0 +1,145 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceKeboolaStorageBucket() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKeboolaStorageBucketRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"stage", "name"},
+			},
+			"stage": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"bucket_id"},
+				ValidateFunc:  validateStorageBucketStage,
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"bucket_id"},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backend": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_read_only": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"data_size_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"sharing": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"target_project_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"target_users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKeboolaStorageBucketRead(d *schema.ResourceData, meta interface{}) error {
+	bucketID, err := dataSourceStorageBucketID(d)
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading Storage Bucket %s from Keboola.", bucketID)
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", bucketID))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			return fmt.Errorf("Storage Bucket %s not found", bucketID)
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	bucketJSON, err := ioutil.ReadAll(getResponse.Body)
+
+	if err != nil {
+		return err
+	}
+
+	var storageBucket StorageBucket
+	var bucketSharing StorageBucketSharing
+
+	if err := json.Unmarshal(bucketJSON, &storageBucket); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(bucketJSON, &bucketSharing); err != nil {
+		return err
+	}
+
+	targetProjectIDs, targetUsers := mapBucketSharingTargets(bucketSharing, nil)
+
+	d.SetId(bucketID)
+	d.Set("bucket_id", bucketID)
+	d.Set("stage", storageBucket.Stage)
+	d.Set("name", strings.TrimPrefix(storageBucket.Name, "c-"))
+	d.Set("description", storageBucket.Description)
+	d.Set("display_name", storageBucket.DisplayName)
+	d.Set("backend", storageBucket.Backend)
+	d.Set("is_read_only", storageBucket.IsReadOnly)
+	d.Set("data_size_bytes", storageBucket.DataSizeBytes)
+	d.Set("sharing", bucketSharing.Sharing)
+	d.Set("target_project_ids", targetProjectIDs)
+	d.Set("target_users", targetUsers)
+
+	return nil
+}
+
+//dataSourceStorageBucketID returns the ID of the bucket to look up, either as given
+//by bucket_id, or built from stage and name.
+func dataSourceStorageBucketID(d *schema.ResourceData) (string, error) {
+	if bucketID := d.Get("bucket_id").(string); bucketID != "" {
+		return bucketID, nil
+	}
+
+	stage := d.Get("stage").(string)
+	name := d.Get("name").(string)
+
+	if stage == "" || name == "" {
+		return "", fmt.Errorf("either bucket_id, or both stage and name, must be set to look up a Storage Bucket")
+	}
+
+	return fmt.Sprintf("%s.c-%s", stage, strings.TrimPrefix(name, "c-")), nil
+}
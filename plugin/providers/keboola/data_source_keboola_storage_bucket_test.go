@@ -0,0 +1,107 @@
+package keboola
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageBucketDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageBucketDataSourceBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.keboola_storage_bucket.test_bucket", "id", "keboola_storage_bucket.test_bucket", "id"),
+					resource.TestCheckResourceAttr("data.keboola_storage_bucket.test_bucket", "description", "test description"),
+					resource.TestCheckResourceAttr("data.keboola_storage_bucket.test_bucket", "backend", "snowflake"),
+					resource.TestCheckResourceAttr("data.keboola_storage_bucket.test_bucket", "is_read_only", "false"),
+					resource.TestCheckResourceAttr("data.keboola_storage_bucket.by_bucket_id", "name", "test_bucket_name"),
+					resource.TestCheckResourceAttr("data.keboola_storage_bucket.by_bucket_id", "stage", "out"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageBucketDataSourceReadsByStageAndName(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/buckets/in.c-shop", r.URL.Path)
+		w.Write([]byte(`{
+			"id": "in.c-shop",
+			"name": "c-shop",
+			"stage": "in",
+			"description": "Orders from the web shop",
+			"backend": "snowflake",
+			"isReadOnly": true,
+			"dataSizeBytes": 1024,
+			"sharing": "specific-projects",
+			"sharingParameters": { "projects": [ { "id": 123 } ], "users": [] }
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"stage": "in",
+		"name":  "shop",
+	})
+
+	err := dataSourceKeboolaStorageBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-shop", d.Id())
+	assert.Equal(t, "in.c-shop", d.Get("bucket_id"))
+	assert.Equal(t, "shop", d.Get("name"))
+	assert.Equal(t, "Orders from the web shop", d.Get("description"))
+	assert.Equal(t, true, d.Get("is_read_only"))
+	assert.Equal(t, 1024, d.Get("data_size_bytes"))
+	assert.Equal(t, "specific-projects", d.Get("sharing"))
+	assert.Equal(t, []interface{}{"123"}, d.Get("target_project_ids"))
+}
+
+func TestStorageBucketDataSourceReportsMissingBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"bucket_id": "in.c-missing",
+	})
+
+	err := dataSourceKeboolaStorageBucketRead(d, client)
+
+	assert.EqualError(t, err, "Storage Bucket in.c-missing not found")
+}
+
+func TestStorageBucketDataSourceRequiresBucketIdentifier(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"stage": "in",
+	})
+
+	err := dataSourceKeboolaStorageBucketRead(d, nil)
+
+	assert.Error(t, err, "A stage without a name should not be enough to look up a bucket")
+}
+
+const testStorageBucketDataSourceBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	data "keboola_storage_bucket" "test_bucket" {
+		stage = "${keboola_storage_bucket.test_bucket.stage}"
+		name = "${keboola_storage_bucket.test_bucket.name}"
+	}
+
+	data "keboola_storage_bucket" "by_bucket_id" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+	}`
@@ -0,0 +1,133 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceKeboolaStorageBuckets() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKeboolaStorageBucketsRead,
+
+		Schema: map[string]*schema.Schema{
+			"stage": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStorageBucketStage,
+			},
+			//the prefix is matched against the name of the bucket without its c- prefix
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"buckets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"stage": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"backend": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_linked": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKeboolaStorageBucketsRead(d *schema.ResourceData, meta interface{}) error {
+	stage := d.Get("stage").(string)
+	namePrefix := d.Get("name_prefix").(string)
+
+	log.Println("[INFO] Reading Storage Buckets from Keboola.")
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage("storage/buckets")
+
+	if hasErrors(err, getResponse) {
+		return extractError(err, getResponse)
+	}
+
+	bucketsJSON, err := ioutil.ReadAll(getResponse.Body)
+
+	if err != nil {
+		return err
+	}
+
+	var storageBuckets []StorageBucket
+	var linkedBuckets []LinkedBucket
+
+	if err := json.Unmarshal(bucketsJSON, &storageBuckets); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(bucketsJSON, &linkedBuckets); err != nil {
+		return err
+	}
+
+	linked := make(map[string]bool, len(linkedBuckets))
+	for _, linkedBucket := range linkedBuckets {
+		linked[linkedBucket.ID] = linkedBucket.SourceBucket != nil
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", stage, namePrefix))
+	d.Set("buckets", mapStorageBucketsToSchema(storageBuckets, linked, stage, namePrefix))
+
+	return nil
+}
+
+//mapStorageBucketsToSchema filters the buckets down to those in the stage (when given) whose name
+//starts with the prefix, sorted by ID so that the order does not change between plans.
+func mapStorageBucketsToSchema(storageBuckets []StorageBucket, linked map[string]bool, stage string, namePrefix string) []map[string]interface{} {
+	sort.Slice(storageBuckets, func(i, j int) bool {
+		return storageBuckets[i].ID < storageBuckets[j].ID
+	})
+
+	buckets := make([]map[string]interface{}, 0, len(storageBuckets))
+
+	for _, storageBucket := range storageBuckets {
+		name := strings.TrimPrefix(storageBucket.Name, "c-")
+
+		if (stage != "" && storageBucket.Stage != stage) || !strings.HasPrefix(name, namePrefix) {
+			continue
+		}
+
+		buckets = append(buckets, map[string]interface{}{
+			"id":          storageBucket.ID,
+			"name":        name,
+			"stage":       storageBucket.Stage,
+			"description": storageBucket.Description,
+			"backend":     storageBucket.Backend,
+			"is_linked":   linked[storageBucket.ID],
+		})
+	}
+
+	return buckets
+}
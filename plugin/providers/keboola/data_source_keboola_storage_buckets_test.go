@@ -0,0 +1,102 @@
+package keboola
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageBucketsDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageBucketsDataSourceBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.keboola_storage_buckets.staging", "buckets.#", "2"),
+					resource.TestCheckResourceAttr("data.keboola_storage_buckets.staging", "buckets.0.name", "staging_customers"),
+					resource.TestCheckResourceAttr("data.keboola_storage_buckets.staging", "buckets.1.name", "staging_orders"),
+					resource.TestCheckResourceAttr("data.keboola_storage_buckets.staging", "buckets.1.stage", "out"),
+					resource.TestCheckResourceAttr("data.keboola_storage_buckets.staging", "buckets.1.is_linked", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageBucketsDataSourceFiltersAndSorts(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/buckets", r.URL.Path)
+		w.Write([]byte(`[
+			{ "id": "out.c-stg_orders", "name": "c-stg_orders", "stage": "out", "description": "Orders", "backend": "snowflake" },
+			{ "id": "in.c-stg_orders", "name": "c-stg_orders", "stage": "in", "backend": "snowflake" },
+			{ "id": "out.c-raw_orders", "name": "c-raw_orders", "stage": "out", "backend": "snowflake" },
+			{ "id": "out.c-stg_customers", "name": "c-stg_customers", "stage": "out", "backend": "snowflake",
+				"sourceBucket": { "id": "out.c-customers", "project": { "id": 123 } } }
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageBuckets().Schema, map[string]interface{}{
+		"stage":       "out",
+		"name_prefix": "stg_",
+	})
+
+	err := dataSourceKeboolaStorageBucketsRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Get("buckets.#"))
+	assert.Equal(t, "out.c-stg_customers", d.Get("buckets.0.id"))
+	assert.Equal(t, true, d.Get("buckets.0.is_linked"))
+	assert.Equal(t, "out.c-stg_orders", d.Get("buckets.1.id"))
+	assert.Equal(t, "stg_orders", d.Get("buckets.1.name"))
+	assert.Equal(t, "Orders", d.Get("buckets.1.description"))
+	assert.Equal(t, false, d.Get("buckets.1.is_linked"))
+}
+
+func TestStorageBucketsDataSourceWithoutFiltersListsAllBuckets(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{ "id": "out.c-orders", "name": "c-orders", "stage": "out" },
+			{ "id": "in.c-orders", "name": "c-orders", "stage": "in" }
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageBuckets().Schema, map[string]interface{}{})
+
+	err := dataSourceKeboolaStorageBucketsRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Get("buckets.#"))
+	assert.Equal(t, "in.c-orders", d.Get("buckets.0.id"))
+}
+
+const testStorageBucketsDataSourceBasic = `
+	resource "keboola_storage_bucket" "staging_orders" {
+		name = "staging_orders"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket" "staging_customers" {
+		name = "staging_customers"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket" "staging_input" {
+		name = "staging_input"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	data "keboola_storage_buckets" "staging" {
+		stage = "out"
+		name_prefix = "staging_"
+		depends_on = [ "keboola_storage_bucket.staging_orders", "keboola_storage_bucket.staging_customers", "keboola_storage_bucket.staging_input" ]
+	}`
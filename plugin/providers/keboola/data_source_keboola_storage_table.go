@@ -0,0 +1,124 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceKeboolaStorageTable() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKeboolaStorageTableRead,
+
+		Schema: map[string]*schema.Schema{
+			"table_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"bucket_id", "name"},
+			},
+			"bucket_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"table_id"},
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"table_id"},
+			},
+			"columns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"primary_key": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rows_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"data_size_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_import_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceKeboolaStorageTableRead(d *schema.ResourceData, meta interface{}) error {
+	tableID, err := dataSourceStorageTableID(d)
+
+	if err != nil {
+		return err
+	}
+
+	bucketID, name, err := parseStorageTableID(tableID)
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading Storage Table %s from Keboola.", tableID)
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", url.PathEscape(tableID)))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			return fmt.Errorf("Storage Table %s not found", tableID)
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTable StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTable)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(tableID)
+	d.Set("table_id", tableID)
+	d.Set("bucket_id", bucketID)
+	d.Set("name", name)
+	d.Set("columns", storageTable.Columns)
+	d.Set("primary_key", storageTable.PrimaryKey)
+	d.Set("rows_count", storageTable.RowsCount)
+	d.Set("data_size_bytes", storageTable.DataSizeBytes)
+	d.Set("last_import_date", storageTable.LastImportDate.RFC3339())
+
+	return nil
+}
+
+//dataSourceStorageTableID returns the full ID of the table to look up, either as given
+//by table_id, or built from bucket_id and name.
+func dataSourceStorageTableID(d *schema.ResourceData) (string, error) {
+	if tableID := d.Get("table_id").(string); tableID != "" {
+		return tableID, nil
+	}
+
+	bucketID := d.Get("bucket_id").(string)
+	name := d.Get("name").(string)
+
+	if bucketID == "" || name == "" {
+		return "", fmt.Errorf("either table_id, or both bucket_id and name, must be set to look up a Storage Table")
+	}
+
+	return fmt.Sprintf("%s.%s", bucketID, name), nil
+}
@@ -0,0 +1,107 @@
+package keboola
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableDataSourceBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.keboola_storage_table.test_table", "id", "keboola_storage_table.test_table", "id"),
+					resource.TestCheckResourceAttr("data.keboola_storage_table.test_table", "columns.#", "3"),
+					resource.TestCheckResourceAttr("data.keboola_storage_table.test_table", "primary_key.0", "first"),
+					resource.TestCheckResourceAttr("data.keboola_storage_table.test_table", "rows_count", "0"),
+					resource.TestCheckResourceAttrPair("data.keboola_storage_table.by_table_id", "columns.#", "keboola_storage_table.test_table", "columns.#"),
+					resource.TestCheckResourceAttr("data.keboola_storage_table.by_table_id", "name", "test_table"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageTableDataSourceReadsByTableID(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/tables/in.c-bucket.table", r.URL.Path)
+		w.Write([]byte(`{
+			"id": "in.c-bucket.table",
+			"name": "table",
+			"columns": [ "id", "name" ],
+			"primaryKey": [ "id" ],
+			"rowsCount": 42
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"table_id": "in.c-bucket.table",
+	})
+
+	err := dataSourceKeboolaStorageTableRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.table", d.Id())
+	assert.Equal(t, "in.c-bucket", d.Get("bucket_id"))
+	assert.Equal(t, "table", d.Get("name"))
+	assert.Equal(t, []interface{}{"id", "name"}, d.Get("columns"))
+	assert.Equal(t, 42, d.Get("rows_count"))
+}
+
+func TestStorageTableDataSourceReportsMissingTable(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "missing_table",
+	})
+
+	err := dataSourceKeboolaStorageTableRead(d, client)
+
+	assert.EqualError(t, err, "Storage Table in.c-bucket.missing_table not found")
+}
+
+func TestStorageTableDataSourceRequiresTableIdentifier(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+	})
+
+	err := dataSourceKeboolaStorageTableRead(d, nil)
+
+	assert.Error(t, err, "A bucket_id without a name should not be enough to look up a table")
+}
+
+const testStorageTableDataSourceBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "first" ]
+		columns = [ "first", "second", "third" ]
+	}
+
+	data "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "${keboola_storage_table.test_table.name}"
+	}
+
+	data "keboola_storage_table" "by_table_id" {
+		table_id = "${keboola_storage_table.test_table.id}"
+	}`
@@ -0,0 +1,117 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceKeboolaStorageTables() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKeboolaStorageTablesRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"columns": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"primary_key": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"rows_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKeboolaStorageTablesRead(d *schema.ResourceData, meta interface{}) error {
+	bucketID := d.Get("bucket_id").(string)
+	namePrefix := d.Get("name_prefix").(string)
+
+	log.Printf("[INFO] Reading Storage Tables in bucket %s from Keboola.", bucketID)
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s/tables?include=columns", url.PathEscape(bucketID)))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			return fmt.Errorf("Storage Bucket %s not found", bucketID)
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTables []StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTables)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", bucketID, namePrefix))
+	d.Set("tables", mapStorageTablesToSchema(storageTables, namePrefix))
+
+	return nil
+}
+
+//mapStorageTablesToSchema filters the tables down to those whose name starts with the prefix, sorted
+//by ID so that the order does not change between plans.
+func mapStorageTablesToSchema(storageTables []StorageTable, namePrefix string) []map[string]interface{} {
+	sort.Slice(storageTables, func(i, j int) bool {
+		return storageTables[i].ID < storageTables[j].ID
+	})
+
+	tables := make([]map[string]interface{}, 0, len(storageTables))
+
+	for _, storageTable := range storageTables {
+		if !strings.HasPrefix(storageTable.Name, namePrefix) {
+			continue
+		}
+
+		tables = append(tables, map[string]interface{}{
+			"id":          storageTable.ID,
+			"name":        storageTable.Name,
+			"columns":     storageTable.Columns,
+			"primary_key": storageTable.PrimaryKey,
+			"rows_count":  storageTable.RowsCount,
+		})
+	}
+
+	return tables
+}
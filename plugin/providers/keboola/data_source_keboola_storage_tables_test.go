@@ -0,0 +1,103 @@
+package keboola
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTablesDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTablesDataSourceBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.keboola_storage_tables.staging", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.keboola_storage_tables.staging", "tables.0.name", "staging_customers"),
+					resource.TestCheckResourceAttr("data.keboola_storage_tables.staging", "tables.1.name", "staging_orders"),
+					resource.TestCheckResourceAttr("data.keboola_storage_tables.staging", "tables.1.primary_key.0", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageTablesDataSourceFiltersAndSorts(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/buckets/in.c-bucket/tables", r.URL.Path)
+		assert.Equal(t, "columns", r.URL.Query().Get("include"))
+		w.Write([]byte(`[
+			{ "id": "in.c-bucket.stg_orders", "name": "stg_orders", "columns": [ "id", "total" ], "primaryKey": [ "id" ], "rowsCount": 10 },
+			{ "id": "in.c-bucket.raw_orders", "name": "raw_orders", "columns": [ "id" ], "primaryKey": [], "rowsCount": 5 },
+			{ "id": "in.c-bucket.stg_customers", "name": "stg_customers", "columns": [ "id", "name" ], "primaryKey": [], "rowsCount": 3 }
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageTables().Schema, map[string]interface{}{
+		"bucket_id":   "in.c-bucket",
+		"name_prefix": "stg_",
+	})
+
+	err := dataSourceKeboolaStorageTablesRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Get("tables.#"))
+	assert.Equal(t, "in.c-bucket.stg_customers", d.Get("tables.0.id"))
+	assert.Equal(t, "in.c-bucket.stg_orders", d.Get("tables.1.id"))
+	assert.Equal(t, []interface{}{"id"}, d.Get("tables.1.primary_key"))
+	assert.Equal(t, 10, d.Get("tables.1.rows_count"))
+}
+
+func TestStorageTablesDataSourceReportsMissingBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceKeboolaStorageTables().Schema, map[string]interface{}{
+		"bucket_id": "in.c-missing",
+	})
+
+	err := dataSourceKeboolaStorageTablesRead(d, client)
+
+	assert.EqualError(t, err, "Storage Bucket in.c-missing not found")
+}
+
+const testStorageTablesDataSourceBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "orders" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "staging_orders"
+		primary_key = [ "id" ]
+		columns = [ "id", "total" ]
+	}
+
+	resource "keboola_storage_table" "customers" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "staging_customers"
+		columns = [ "id", "name" ]
+	}
+
+	resource "keboola_storage_table" "raw" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "raw_events"
+		columns = [ "id" ]
+	}
+
+	data "keboola_storage_tables" "staging" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name_prefix = "staging_"
+		depends_on = [ "keboola_storage_table.orders", "keboola_storage_table.customers", "keboola_storage_table.raw" ]
+	}`
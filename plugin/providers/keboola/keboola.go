@@ -62,7 +62,7 @@ type KBCTime struct {
 func (kt *KBCTime) UnmarshalJSON(b []byte) (err error) {
 	s := strings.Trim(string(b), "\"")
 
-	if s == "null" {
+	if s == "null" || s == "" {
 		kt.Time = time.Time{}
 		return
 	}
@@ -71,3 +71,13 @@ func (kt *KBCTime) UnmarshalJSON(b []byte) (err error) {
 
 	return
 }
+
+//RFC3339 formats a KBCTime in UTC as RFC3339 (the format of Terraform's timestamp functions), or
+//as an empty string when there is no time (e.g. a table which has never been imported in to).
+func (kt KBCTime) RFC3339() string {
+	if kt.IsZero() {
+		return ""
+	}
+
+	return kt.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,452 @@
+package keboola
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//CodeTransformationInputTable is a mapping from a Storage table to the file that the script of a
+//code transformation (e.g. a Python transformation) reads it from.
+type CodeTransformationInputTable struct {
+	Source        string   `json:"source"`
+	Destination   string   `json:"destination"`
+	Columns       []string `json:"columns,omitempty"`
+	WhereColumn   string   `json:"where_column,omitempty"`
+	WhereOperator string   `json:"where_operator,omitempty"`
+	WhereValues   []string `json:"where_values,omitempty"`
+	ChangedSince  string   `json:"changed_since,omitempty"`
+}
+
+//CodeTransformationOutputTable is a mapping from a file written by the script of a code
+//transformation to a Storage table.
+type CodeTransformationOutputTable struct {
+	Source              string   `json:"source"`
+	Destination         string   `json:"destination"`
+	Incremental         bool     `json:"incremental"`
+	PrimaryKey          []string `json:"primary_key,omitempty"`
+	DeleteWhereColumn   string   `json:"delete_where_column,omitempty"`
+	DeleteWhereOperator string   `json:"delete_where_operator,omitempty"`
+	DeleteWhereValues   []string `json:"delete_where_values,omitempty"`
+}
+
+//CodeTransformationCode is a named piece of the script of a code transformation, kept as a list of lines.
+type CodeTransformationCode struct {
+	Name   string   `json:"name"`
+	Script []string `json:"script"`
+}
+
+//CodeTransformationBlock is a named group of codes, which are run in order.
+type CodeTransformationBlock struct {
+	Name  string                   `json:"name"`
+	Codes []CodeTransformationCode `json:"codes"`
+}
+
+//CodeTransformationRuntime selects the backend (e.g. the size of the container) which a code
+//transformation is run on.
+type CodeTransformationRuntime struct {
+	Backend struct {
+		Type string `json:"type"`
+	} `json:"backend"`
+}
+
+//CodeTransformationConfiguration is the configuration of a code transformation, holding its input
+//and output mappings, the blocks of its script, the packages it installs and its runtime.
+type CodeTransformationConfiguration struct {
+	Storage struct {
+		Input struct {
+			Tables []CodeTransformationInputTable `json:"tables"`
+		} `json:"input"`
+		Output struct {
+			Tables []CodeTransformationOutputTable `json:"tables"`
+		} `json:"output"`
+	} `json:"storage"`
+	Parameters struct {
+		Blocks   []CodeTransformationBlock `json:"blocks"`
+		Packages []string                  `json:"packages"`
+	} `json:"parameters"`
+	Runtime *CodeTransformationRuntime `json:"runtime,omitempty"`
+}
+
+//CodeTransformation is the data model for transformations which run a script (e.g. Python or R),
+//rather than SQL queries, within the Keboola Storage API.
+type CodeTransformation struct {
+	ID            string                          `json:"id,omitempty"`
+	Name          string                          `json:"name"`
+	Description   string                          `json:"description"`
+	Configuration CodeTransformationConfiguration `json:"configuration"`
+}
+
+//endregion
+
+var codeTransformationInputSchema = schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"destination": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"columns": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"where_column": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"where_operator": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"where_values": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"changed_since": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	},
+}
+
+//codeTransformationComponent is a component which runs a script as a transformation (e.g. Python or R).
+//When the language ignores some differences in the formatting of a script (e.g. in whitespace), normaliseScript
+//removes them, so that scripts which only differ in their formatting are not planned as a change.
+type codeTransformationComponent struct {
+	id              string
+	normaliseScript func(string) string
+}
+
+//codeTransformationScriptBlockName and codeTransformationScriptCodeName name the single block, and the single
+//code within it, which the script of a code transformation is saved as.
+const codeTransformationScriptBlockName = "Terraform"
+const codeTransformationScriptCodeName = "Script"
+
+//readCodeTransformationScript returns the script of a code transformation, either from the script file or inline.
+func readCodeTransformationScript(script string, scriptFile string) (string, error) {
+	if scriptFile == "" {
+		return script, nil
+	}
+
+	contents, err := ioutil.ReadFile(scriptFile)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
+
+//hashScript calculates a SHA-256 hash of a (normalised) script, so that changes to a script file (rather
+//than its path) can be detected between plans.
+func (c codeTransformationComponent) hashScript(script string) string {
+	if c.normaliseScript != nil {
+		script = c.normaliseScript(script)
+	}
+
+	hash := sha256.Sum256([]byte(script))
+
+	return hex.EncodeToString(hash[:])
+}
+
+//customizeDiff plans an update whenever the script has changed since it was last saved, including when
+//only the contents of the script file have changed.
+func (c codeTransformationComponent) customizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("script") || !d.NewValueKnown("script_file") {
+		return nil
+	}
+
+	scriptFile := d.Get("script_file").(string)
+
+	if d.Get("script").(string) == "" && scriptFile == "" {
+		return fmt.Errorf("one of script or script_file must be set")
+	}
+
+	script, err := readCodeTransformationScript(d.Get("script").(string), scriptFile)
+
+	if err != nil {
+		return err
+	}
+
+	if scriptHash := c.hashScript(script); scriptHash != d.Get("script_hash").(string) {
+		return d.SetNew("script_hash", scriptHash)
+	}
+
+	return nil
+}
+
+//mapCodeTransformationConfiguration builds the configuration of a code transformation from the resource.
+func mapCodeTransformationConfiguration(d *schema.ResourceData) (CodeTransformationConfiguration, error) {
+	var configuration CodeTransformationConfiguration
+
+	script, err := readCodeTransformationScript(d.Get("script").(string), d.Get("script_file").(string))
+
+	if err != nil {
+		return configuration, err
+	}
+
+	configuration.Parameters.Blocks = []CodeTransformationBlock{
+		{
+			Name: codeTransformationScriptBlockName,
+			Codes: []CodeTransformationCode{
+				{
+					Name:   codeTransformationScriptCodeName,
+					Script: []string{script},
+				},
+			},
+		},
+	}
+
+	configuration.Parameters.Packages = AsStringArray(d.Get("packages").([]interface{}))
+	configuration.Storage.Input.Tables = mapCodeTransformationInputToModel(d.Get("input").([]interface{}))
+	configuration.Storage.Output.Tables = mapCodeTransformationOutputToModel(d.Get("output").([]interface{}))
+
+	if backendSize := d.Get("backend_size").(string); backendSize != "" {
+		configuration.Runtime = &CodeTransformationRuntime{}
+		configuration.Runtime.Backend.Type = backendSize
+	}
+
+	return configuration, nil
+}
+
+//codeTransformationScript joins the scripts of all of the codes in all of the blocks of a code transformation,
+//as a transformation which was changed outside of Terraform may have more than the single block it is saved as.
+func codeTransformationScript(blocks []CodeTransformationBlock) string {
+	var scripts []string
+
+	for _, block := range blocks {
+		for _, code := range block.Codes {
+			scripts = append(scripts, code.Script...)
+		}
+	}
+
+	return strings.Join(scripts, "\n\n")
+}
+
+func mapCodeTransformationInputToModel(inputs []interface{}) []CodeTransformationInputTable {
+	mappedInputs := make([]CodeTransformationInputTable, 0, len(inputs))
+
+	for _, inputConfig := range inputs {
+		config := inputConfig.(map[string]interface{})
+
+		mappedInputs = append(mappedInputs, CodeTransformationInputTable{
+			Source:        config["source"].(string),
+			Destination:   config["destination"].(string),
+			Columns:       AsStringArray(config["columns"].([]interface{})),
+			WhereColumn:   config["where_column"].(string),
+			WhereOperator: config["where_operator"].(string),
+			WhereValues:   AsStringArray(config["where_values"].([]interface{})),
+			ChangedSince:  config["changed_since"].(string),
+		})
+	}
+
+	return mappedInputs
+}
+
+func mapCodeTransformationInputToSchema(inputs []CodeTransformationInputTable) []map[string]interface{} {
+	mappedInputs := make([]map[string]interface{}, 0, len(inputs))
+
+	for _, input := range inputs {
+		mappedInputs = append(mappedInputs, map[string]interface{}{
+			"source":         input.Source,
+			"destination":    input.Destination,
+			"columns":        input.Columns,
+			"where_column":   input.WhereColumn,
+			"where_operator": input.WhereOperator,
+			"where_values":   input.WhereValues,
+			"changed_since":  input.ChangedSince,
+		})
+	}
+
+	return mappedInputs
+}
+
+func mapCodeTransformationOutputToModel(outputs []interface{}) []CodeTransformationOutputTable {
+	mappedOutputs := make([]CodeTransformationOutputTable, 0, len(outputs))
+
+	for _, outputConfig := range outputs {
+		config := outputConfig.(map[string]interface{})
+
+		mappedOutputs = append(mappedOutputs, CodeTransformationOutputTable{
+			Source:              config["source"].(string),
+			Destination:         config["destination"].(string),
+			Incremental:         config["incremental"].(bool),
+			PrimaryKey:          AsStringArray(config["primary_key"].([]interface{})),
+			DeleteWhereColumn:   config["delete_where_column"].(string),
+			DeleteWhereOperator: config["delete_where_operator"].(string),
+			DeleteWhereValues:   AsStringArray(config["delete_where_values"].([]interface{})),
+		})
+	}
+
+	return mappedOutputs
+}
+
+func mapCodeTransformationOutputToSchema(outputs []CodeTransformationOutputTable) []map[string]interface{} {
+	mappedOutputs := make([]map[string]interface{}, 0, len(outputs))
+
+	for _, output := range outputs {
+		mappedOutputs = append(mappedOutputs, map[string]interface{}{
+			"source":                output.Source,
+			"destination":           output.Destination,
+			"incremental":           output.Incremental,
+			"primary_key":           output.PrimaryKey,
+			"delete_where_column":   output.DeleteWhereColumn,
+			"delete_where_operator": output.DeleteWhereOperator,
+			"delete_where_values":   output.DeleteWhereValues,
+		})
+	}
+
+	return mappedOutputs
+}
+
+//create creates the configuration of a code transformation.
+func (c codeTransformationComponent) create(d *schema.ResourceData, client *KBCClient) error {
+	configuration, err := mapCodeTransformationConfiguration(d)
+
+	if err != nil {
+		return err
+	}
+
+	configurationJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	createTransformationForm := url.Values{}
+	createTransformationForm.Add("name", d.Get("name").(string))
+	createTransformationForm.Add("description", d.Get("description").(string))
+	createTransformationForm.Add("configuration", string(configurationJSON))
+
+	createTransformationBuffer := buffer.FromForm(createTransformationForm)
+
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/components/%s/configs", c.id), createTransformationBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	return nil
+}
+
+//read reads the configuration of a code transformation. The script is only read back when it is set
+//inline, otherwise only its hash is compared with that of the script file.
+func (c codeTransformationComponent) read(d *schema.ResourceData, client *KBCClient) error {
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", c.id, d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var transformation CodeTransformation
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&transformation)
+
+	if err != nil {
+		return err
+	}
+
+	configuration := transformation.Configuration
+	script := codeTransformationScript(configuration.Parameters.Blocks)
+
+	d.Set("name", transformation.Name)
+	d.Set("description", transformation.Description)
+	d.Set("packages", configuration.Parameters.Packages)
+	d.Set("input", mapCodeTransformationInputToSchema(configuration.Storage.Input.Tables))
+	d.Set("output", mapCodeTransformationOutputToSchema(configuration.Storage.Output.Tables))
+	d.Set("script_hash", c.hashScript(script))
+
+	if d.Get("script_file").(string) == "" {
+		d.Set("script", script)
+	}
+
+	if configuration.Runtime != nil {
+		d.Set("backend_size", configuration.Runtime.Backend.Type)
+	} else {
+		d.Set("backend_size", "")
+	}
+
+	return nil
+}
+
+//update saves the whole configuration of a code transformation.
+func (c codeTransformationComponent) update(d *schema.ResourceData, client *KBCClient, changeDescription string) error {
+	configuration, err := mapCodeTransformationConfiguration(d)
+
+	if err != nil {
+		return err
+	}
+
+	configurationJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	updateTransformationForm := url.Values{}
+	updateTransformationForm.Add("name", d.Get("name").(string))
+	updateTransformationForm.Add("description", d.Get("description").(string))
+	updateTransformationForm.Add("configuration", string(configurationJSON))
+	updateTransformationForm.Add("changeDescription", changeDescription)
+
+	updateTransformationBuffer := buffer.FromForm(updateTransformationForm)
+
+	updateResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/%s/configs/%s", c.id, d.Id()), updateTransformationBuffer)
+
+	if hasErrors(err, updateResponse) {
+		return extractError(err, updateResponse)
+	}
+
+	return nil
+}
+
+func (c codeTransformationComponent) delete(d *schema.ResourceData, client *KBCClient) error {
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", c.id, d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -1,19 +1,336 @@
 package keboola
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const initialJobPollInterval = 250 * time.Millisecond
+const maxJobPollInterval = 15 * time.Second
+
+//maxJobNotFoundAttempts is how many times a job may be reported as not found before giving up,
+//as a newly created job is not always immediately visible.
+const maxJobNotFoundAttempts = 3
+
 //StorageJobStatus contains the job status and results for Storage API based jobs.
 type StorageJobStatus struct {
 	ID      int    `json:"id"`
 	URL     string `json:"url"`
 	Status  string `json:"status"`
 	Results struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
+		ID   StorageJobResultID `json:"id"`
+		Name string             `json:"name"`
 	} `json:"results"`
+	Error struct {
+		Message     string `json:"message"`
+		ExceptionID string `json:"exceptionId"`
+	} `json:"error"`
+}
+
+//StorageJobError is returned when a Storage API job finishes without succeeding.
+type StorageJobError struct {
+	JobID       int
+	Status      string
+	Message     string
+	ExceptionID string
+}
+
+func (e *StorageJobError) Error() string {
+	message := fmt.Sprintf("Storage job %v failed", e.JobID)
+
+	if e.Status != "error" {
+		message = fmt.Sprintf("Storage job %v finished with status %q", e.JobID, e.Status)
+	}
+
+	if e.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Message)
+	}
+
+	if e.ExceptionID != "" {
+		message = fmt.Sprintf("%s (exception ID: %s)", message, e.ExceptionID)
+	}
+
+	return message
+}
+
+//isTerminalJobStatus checks whether a job with the given status has finished (successfully or not).
+func isTerminalJobStatus(status string) bool {
+	switch status {
+	case "success", "error", "cancelled", "terminated":
+		return true
+	default:
+		return false
+	}
+}
+
+//StorageJobResultID is the ID of the object created by a Storage API job. Depending on the
+//job this is either a string (e.g. a table ID) or a number (e.g. a snapshot ID).
+type StorageJobResultID string
+
+//UnmarshalJSON accepts both string and numeric IDs.
+func (id *StorageJobResultID) UnmarshalJSON(data []byte) error {
+	var value json.Number
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		var text string
+
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+
+		*id = StorageJobResultID(text)
+		return nil
+	}
+
+	*id = StorageJobResultID(value.String())
+	return nil
 }
 
 //SyrupJobStatus contains the job status and results for Syrup API based jobs.
 type SyrupJobStatus struct {
-	ID     int    `json:"id"`
-	URL    string `json:"url"`
-	Status string `json:"status"`
+	ID         int                    `json:"id"`
+	URL        string                 `json:"url"`
+	Status     string                 `json:"status"`
+	IsFinished bool                   `json:"isFinished"`
+	Result     map[string]interface{} `json:"result"`
+}
+
+//errJobPollTimeout is returned by pollUntilDone when the timeout passes before polling is done.
+var errJobPollTimeout = errors.New("timed out")
+
+//jobPoller polls asynchronous Keboola jobs with exponential backoff. Time and randomness are
+//provided as functions so that the backoff schedule can be tested without waiting.
+type jobPoller struct {
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+	random func() float64
+}
+
+var defaultJobPoller = jobPoller{
+	now:    time.Now,
+	sleep:  sleepWithContext,
+	random: rand.Float64,
+}
+
+//sleepWithContext sleeps for the given duration, returning early with an error if the
+//context is cancelled first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//nextJobPollInterval doubles the interval between job status requests, up to
+//maxJobPollInterval, so that long running jobs are not polled excessively.
+func nextJobPollInterval(current time.Duration) time.Duration {
+	next := current * 2
+	if next > maxJobPollInterval {
+		return maxJobPollInterval
+	}
+
+	return next
+}
+
+//withJitter randomises an interval to between half and all of its length, so that
+//resources created in parallel do not poll in lockstep.
+func (p jobPoller) withJitter(interval time.Duration) time.Duration {
+	return interval/2 + time.Duration(p.random()*float64(interval/2))
+}
+
+//pollUntilDone calls check until it reports that it is done (or fails), backing off exponentially
+//between calls. If the timeout passes first, errJobPollTimeout is returned, and if the context is
+//cancelled (e.g. Terraform was interrupted) the context's error is returned.
+func (p jobPoller) pollUntilDone(ctx context.Context, timeout time.Duration, check func() (bool, error)) error {
+	deadline := p.now().Add(timeout)
+	pollInterval := initialJobPollInterval
+
+	for {
+		done, err := check()
+
+		if err != nil || done {
+			return err
+		}
+
+		if p.now().After(deadline) {
+			return errJobPollTimeout
+		}
+
+		if err := p.sleep(ctx, p.withJitter(pollInterval)); err != nil {
+			return err
+		}
+
+		pollInterval = nextJobPollInterval(pollInterval)
+	}
+}
+
+//WaitForStorageJob polls a Storage API job until it has finished, or until the timeout has passed or the
+//context is cancelled. A job which finishes without succeeding is returned as a StorageJobError.
+func (c *KBCClient) WaitForStorageJob(ctx context.Context, jobID int, timeout time.Duration) (*StorageJobStatus, error) {
+	jobStatus := "waiting"
+	notFoundAttempts := 0
+
+	var jobStatusResult StorageJobStatus
+
+	err := defaultJobPoller.pollUntilDone(ctx, timeout, func() (bool, error) {
+		jobStatusResponse, err := c.GetFromStorage(fmt.Sprintf("storage/jobs/%v", jobID))
+
+		if err == nil && jobStatusResponse.StatusCode == http.StatusNotFound && notFoundAttempts < maxJobNotFoundAttempts {
+			notFoundAttempts++
+			log.Printf("[DEBUG] Storage job %v not found, retrying (attempt %d of %d)", jobID, notFoundAttempts, maxJobNotFoundAttempts)
+			jobStatusResponse.Body.Close()
+			return false, nil
+		}
+
+		if hasErrors(err, jobStatusResponse) {
+			return false, extractError(err, jobStatusResponse)
+		}
+
+		jobStatusDecoder := json.NewDecoder(jobStatusResponse.Body)
+		err = jobStatusDecoder.Decode(&jobStatusResult)
+
+		if err != nil {
+			return false, err
+		}
+
+		jobStatus = jobStatusResult.Status
+
+		return isTerminalJobStatus(jobStatus), nil
+	})
+
+	if err == errJobPollTimeout || err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("Timed out after %s waiting for Storage job %v to complete (last status: %s)", timeout, jobID, jobStatus)
+	}
+
+	if err == context.Canceled {
+		return nil, fmt.Errorf("Cancelled while waiting for Storage job %v to complete (last status: %s)", jobID, jobStatus)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if jobStatus != "success" {
+		return nil, &StorageJobError{
+			JobID:       jobID,
+			Status:      jobStatus,
+			Message:     jobStatusResult.Error.Message,
+			ExceptionID: jobStatusResult.Error.ExceptionID,
+		}
+	}
+
+	return &jobStatusResult, nil
+}
+
+//getStorageJob gets the current status of a Storage API job, without waiting for it to finish.
+func getStorageJob(client *KBCClient, jobID int) (*StorageJobStatus, error) {
+	jobStatusResponse, err := client.GetFromStorage(fmt.Sprintf("storage/jobs/%v", jobID))
+
+	if hasErrors(err, jobStatusResponse) {
+		return nil, extractError(err, jobStatusResponse)
+	}
+
+	var jobStatusResult StorageJobStatus
+
+	jobStatusDecoder := json.NewDecoder(jobStatusResponse.Body)
+	err = jobStatusDecoder.Decode(&jobStatusResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobStatusResult, nil
+}
+
+//WaitForSyrupJob polls a Syrup job (identified by the URL returned when it was started) until it has
+//finished, or until the timeout has passed or the context is cancelled. A job has finished once it
+//reaches a terminal status, or once Syrup flags it as finished. Jobs which finish with a warning
+//succeed, while any other status fails with the message of the job's result.
+func (c *KBCClient) WaitForSyrupJob(ctx context.Context, jobURL string, timeout time.Duration) (*SyrupJobStatus, error) {
+	parsedJobURL, err := url.Parse(jobURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	jobStatus := "waiting"
+
+	var jobStatusResult SyrupJobStatus
+
+	err = defaultJobPoller.pollUntilDone(ctx, timeout, func() (bool, error) {
+		jobStatusResponse, err := c.GetFromSyrup(strings.TrimLeft(parsedJobURL.Path, "/"))
+
+		if hasErrors(err, jobStatusResponse) {
+			return false, extractError(err, jobStatusResponse)
+		}
+
+		jobStatusDecoder := json.NewDecoder(jobStatusResponse.Body)
+		err = jobStatusDecoder.Decode(&jobStatusResult)
+
+		if err != nil {
+			return false, err
+		}
+
+		jobStatus = jobStatusResult.Status
+
+		//unlike Storage jobs, Syrup jobs can also finish with a warning
+		return jobStatusResult.IsFinished || jobStatus == "warning" || isTerminalJobStatus(jobStatus), nil
+	})
+
+	if err == errJobPollTimeout || err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("Timed out after %s waiting for Syrup job %s to complete (last status: %s)", timeout, jobURL, jobStatus)
+	}
+
+	if err == context.Canceled {
+		return nil, fmt.Errorf("Cancelled while waiting for Syrup job %s to complete (last status: %s)", jobURL, jobStatus)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if jobStatus != "success" && jobStatus != "warning" {
+		if message, _ := jobStatusResult.Result["message"].(string); message != "" {
+			return nil, fmt.Errorf("Syrup job %v finished with status %q: %s", jobStatusResult.ID, jobStatus, message)
+		}
+
+		return nil, fmt.Errorf("Syrup job %v finished with status %q", jobStatusResult.ID, jobStatus)
+	}
+
+	return &jobStatusResult, nil
+}
+
+//waitForAcceptedStorageJob waits for the job started by a Storage API request, if the request
+//was accepted to be processed asynchronously (202 Accepted). Other responses are assumed to
+//have been processed synchronously.
+func waitForAcceptedStorageJob(client *KBCClient, response *http.Response, timeout time.Duration) error {
+	if response.StatusCode != http.StatusAccepted {
+		return nil
+	}
+
+	var job StorageJobStatus
+
+	decoder := json.NewDecoder(response.Body)
+	err := decoder.Decode(&job)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = client.WaitForStorageJob(client.stopContext(), job.ID, timeout)
+
+	return err
 }
@@ -0,0 +1,314 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobPollClock struct {
+	current time.Time
+	sleeps  []time.Duration
+}
+
+func (c *fakeJobPollClock) poller(random float64) jobPoller {
+	return jobPoller{
+		now: func() time.Time { return c.current },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			c.sleeps = append(c.sleeps, d)
+			c.current = c.current.Add(d)
+
+			return ctx.Err()
+		},
+		random: func() float64 { return random },
+	}
+}
+
+func TestPollUntilDoneBacksOffExponentially(t *testing.T) {
+	clock := &fakeJobPollClock{current: time.Now()}
+
+	checks := 0
+	err := clock.poller(1).pollUntilDone(context.Background(), time.Hour, func() (bool, error) {
+		checks++
+		return checks == 10, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		15 * time.Second,
+		15 * time.Second,
+		15 * time.Second,
+	}, clock.sleeps, "Poll interval should double up to the maximum, and not sleep once done")
+}
+
+func TestPollUntilDoneAddsJitter(t *testing.T) {
+	clock := &fakeJobPollClock{current: time.Now()}
+
+	checks := 0
+	clock.poller(0).pollUntilDone(context.Background(), time.Hour, func() (bool, error) {
+		checks++
+		return checks == 3, nil
+	})
+
+	assert.Equal(t, []time.Duration{125 * time.Millisecond, 250 * time.Millisecond}, clock.sleeps, "Jitter should shorten the interval by up to half")
+}
+
+func TestPollUntilDoneTimesOut(t *testing.T) {
+	clock := &fakeJobPollClock{current: time.Now()}
+
+	err := clock.poller(1).pollUntilDone(context.Background(), time.Minute, func() (bool, error) {
+		return false, nil
+	})
+
+	assert.Equal(t, errJobPollTimeout, err)
+	assert.True(t, len(clock.sleeps) < 10, "Polling should stop once the timeout has passed")
+}
+
+func TestPollUntilDoneStopsWhenCancelled(t *testing.T) {
+	clock := &fakeJobPollClock{current: time.Now()}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	checks := 0
+	err := clock.poller(1).pollUntilDone(ctx, time.Hour, func() (bool, error) {
+		checks++
+		if checks == 2 {
+			cancel()
+		}
+
+		return false, nil
+	})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 2, checks, "Polling should stop as soon as the context is cancelled")
+}
+
+func newTestStorageServer(handler http.HandlerFunc) (*httptest.Server, *KBCClient) {
+	server := httptest.NewTLSServer(handler)
+	serverURL, _ := url.Parse(server.URL)
+
+	client := &KBCClient{
+		APIKey:     "test",
+		Host:       serverURL.Host,
+		httpClient: server.Client(),
+	}
+
+	return server, client
+}
+
+func TestWaitForStorageJobReturnsJobError(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/jobs/12345", r.URL.Path)
+		w.Write([]byte(`{
+			"id": 12345,
+			"status": "error",
+			"error": {
+				"message": "Invalid delimiter",
+				"exceptionId": "exception-abc123"
+			}
+		}`))
+	})
+	defer server.Close()
+
+	jobStatus, err := client.WaitForStorageJob(context.Background(), 12345, time.Minute)
+
+	assert.Nil(t, jobStatus, "No job result should be returned for a failed job")
+	assert.EqualError(t, err, "Storage job 12345 failed: Invalid delimiter (exception ID: exception-abc123)")
+
+	jobError, ok := err.(*StorageJobError)
+	assert.True(t, ok, "Failed jobs should be reported as a StorageJobError")
+	assert.Equal(t, "exception-abc123", jobError.ExceptionID)
+}
+
+func TestIsTerminalJobStatus(t *testing.T) {
+	statuses := []struct {
+		status   string
+		terminal bool
+	}{
+		{"waiting", false},
+		{"processing", false},
+		{"success", true},
+		{"warning", false},
+		{"error", true},
+		{"cancelled", true},
+		{"terminated", true},
+	}
+
+	for _, s := range statuses {
+		assert.Equal(t, s.terminal, isTerminalJobStatus(s.status), "Unexpected result for status %q", s.status)
+	}
+}
+
+func TestWaitForStorageJobReportsTerminalStatuses(t *testing.T) {
+	statuses := []struct {
+		status        string
+		expectedError string
+	}{
+		{"success", ""},
+		{"error", "Storage job 12345 failed"},
+		{"cancelled", `Storage job 12345 finished with status "cancelled"`},
+		{"terminated", `Storage job 12345 finished with status "terminated"`},
+	}
+
+	for _, s := range statuses {
+		server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(fmt.Sprintf(`{ "id": 12345, "status": %q }`, s.status)))
+		})
+
+		jobStatus, err := client.WaitForStorageJob(context.Background(), 12345, time.Minute)
+		server.Close()
+
+		if s.expectedError == "" {
+			assert.NoError(t, err, "Status %q should succeed", s.status)
+			assert.NotNil(t, jobStatus)
+		} else {
+			assert.EqualError(t, err, s.expectedError, "Status %q should fail", s.status)
+		}
+	}
+}
+
+func TestWaitForStorageJobRetriesNotFound(t *testing.T) {
+	requests := 0
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{ "id": 12345, "status": "success" }`))
+	})
+	defer server.Close()
+
+	_, err := client.WaitForStorageJob(context.Background(), 12345, time.Minute)
+
+	assert.NoError(t, err, "A job should be found after being briefly reported as not found")
+	assert.Equal(t, 3, requests)
+}
+
+func TestWaitForStorageJobTimesOut(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 12345, "status": "processing" }`))
+	})
+	defer server.Close()
+
+	jobStatus, err := client.WaitForStorageJob(context.Background(), 12345, time.Millisecond)
+
+	assert.Nil(t, jobStatus)
+	assert.EqualError(t, err, "Timed out after 1ms waiting for Storage job 12345 to complete (last status: processing)")
+}
+
+func TestWaitForStorageJobStopsWhenCancelled(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	jobStatus, err := client.WaitForStorageJob(ctx, 12345, time.Hour)
+
+	assert.Nil(t, jobStatus)
+	assert.EqualError(t, err, "Cancelled while waiting for Storage job 12345 to complete (last status: waiting)")
+}
+
+//newTestKeboolaServer serves requests for every Keboola service host (e.g. syrup.example.com) from
+//a single test server, so that clients can be pointed at a realistic connection host.
+func newTestKeboolaServer(handler http.HandlerFunc) (*httptest.Server, *KBCClient) {
+	server := httptest.NewTLSServer(handler)
+
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	client := &KBCClient{
+		APIKey:     "test",
+		Host:       "connection.example.com",
+		httpClient: &http.Client{Transport: transport},
+	}
+
+	return server, client
+}
+
+func TestWaitForSyrupJobReturnsResult(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "syrup.example.com", r.Host)
+		assert.Equal(t, "/queue/job/98765", r.URL.Path)
+		w.Write([]byte(`{ "id": 98765, "status": "warning", "result": { "pid": "abc123def" } }`))
+	})
+	defer server.Close()
+
+	jobStatus, err := client.WaitForSyrupJob(context.Background(), "https://syrup.example.com/queue/job/98765", time.Minute)
+
+	assert.NoError(t, err, "A job finishing with a warning should not fail")
+	assert.Equal(t, "abc123def", jobStatus.Result["pid"])
+}
+
+func TestWaitForSyrupJobReturnsJobError(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 98765, "status": "error", "result": { "message": "Invalid GoodData token" } }`))
+	})
+	defer server.Close()
+
+	_, err := client.WaitForSyrupJob(context.Background(), "https://syrup.example.com/queue/job/98765", time.Minute)
+
+	assert.EqualError(t, err, `Syrup job 98765 finished with status "error": Invalid GoodData token`)
+}
+
+func TestWaitForSyrupJobWaitsUntilFinished(t *testing.T) {
+	requests := 0
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Write([]byte(`{ "id": 98765, "status": "processing", "isFinished": false }`))
+			return
+		}
+
+		w.Write([]byte(`{ "id": 98765, "status": "terminated", "isFinished": true, "result": {} }`))
+	})
+	defer server.Close()
+
+	_, err := client.WaitForSyrupJob(context.Background(), "https://syrup.example.com/queue/job/98765", time.Minute)
+
+	assert.EqualError(t, err, `Syrup job 98765 finished with status "terminated"`, "A job without a result message should still report its status")
+	assert.Equal(t, 2, requests, "The job should be polled until it has finished")
+}
+
+func TestWaitForSyrupJobStopsOnceFlaggedAsFinished(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 98765, "status": "cancelling", "isFinished": true, "result": { "message": "Cancelled by user" } }`))
+	})
+	defer server.Close()
+
+	_, err := client.WaitForSyrupJob(context.Background(), "https://syrup.example.com/queue/job/98765", time.Minute)
+
+	assert.EqualError(t, err, `Syrup job 98765 finished with status "cancelling": Cancelled by user`)
+}
+
+func TestWaitForSyrupJobStopsWhenCancelled(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": 98765, "status": "waiting" }`))
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.WaitForSyrupJob(ctx, "https://syrup.example.com/queue/job/98765", time.Hour)
+
+	assert.EqualError(t, err, "Cancelled while waiting for Syrup job https://syrup.example.com/queue/job/98765 to complete (last status: waiting)")
+}
@@ -0,0 +1,150 @@
+package keboola
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//secretAttribute is an attribute holding a secret (e.g. a password), which is encrypted by Keboola. Only the
+//ciphertext Keboola holds for the secret is kept in state, rather than anything computed from the secret itself.
+//A required secret must be set whenever the block holding it (if any) is.
+type secretAttribute struct {
+	key      string
+	required bool
+}
+
+//secretHashesSchema holds a hash of each of the secrets of a resource, by the key of its attribute, so that a
+//change to a secret can be told apart from the ciphertext which is kept in its place.
+var secretHashesSchema = schema.Schema{
+	Type:     schema.TypeMap,
+	Computed: true,
+	Elem: &schema.Schema{
+		Type: schema.TypeString,
+	},
+}
+
+//secretHashKey is the key of the hash of a secret within secret_hashes, which is the key of its attribute with
+//dots replaced, as Terraform expands the keys of maps at dots.
+func secretHashKey(key string) string {
+	return strings.Replace(key, ".", "_", -1)
+}
+
+//secretHash hashes a secret along with the ciphertext Keboola holds for it, using an HMAC keyed by the Storage
+//API token, so that the hash cannot be used to recover the secret without the token. The resource and attribute
+//are included, so that the same secret does not have the same hash anywhere else.
+func (c *KBCClient) secretHash(resourceID string, key string, ciphertext string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(c.APIKey))
+	mac.Write([]byte(strings.Join([]string{resourceID, key, ciphertext, secret}, "\x00")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//secretUnchanged checks whether a configured secret is the one which was last encrypted as the ciphertext in state.
+//A ciphertext which has since been changed outside of Terraform (e.g. in the Keboola UI) no longer matches.
+func (c *KBCClient) secretUnchanged(resourceID string, key string, ciphertext string, secret string, secretHashes map[string]interface{}) bool {
+	if secret == "" || isEncryptedValue(secret) {
+		return false
+	}
+
+	return secretHashes[secretHashKey(key)] == c.secretHash(resourceID, key, ciphertext, secret)
+}
+
+//secretBlockSet checks whether the block holding a secret (if any) is set, e.g. the ssh_tunnel block of
+//db_parameters.0.ssh_tunnel.0.private_key.
+func secretBlockSet(d *schema.ResourceDiff, key string) bool {
+	separator := strings.LastIndex(key, ".0.")
+
+	if separator < 0 {
+		return true
+	}
+
+	blocks, _ := d.Get(key[:separator]).([]interface{})
+
+	return len(blocks) > 0
+}
+
+//customizeSecretsDiff removes the diff between a secret and the ciphertext kept in its place in state, unless
+//the secret has changed since it was encrypted, and checks that required secrets are set on new resources.
+func customizeSecretsDiff(d *schema.ResourceDiff, meta interface{}, secrets []secretAttribute) error {
+	client := meta.(*KBCClient)
+	secretHashes, _ := d.Get("secret_hashes").(map[string]interface{})
+
+	for _, secret := range secrets {
+		if !d.NewValueKnown(secret.key) {
+			continue
+		}
+
+		if d.Id() == "" {
+			if secret.required && d.Get(secret.key).(string) == "" && secretBlockSet(d, secret.key) {
+				return fmt.Errorf("%s must be set", secret.key)
+			}
+
+			continue
+		}
+
+		if !d.HasChange(secret.key) {
+			continue
+		}
+
+		ciphertext, configured := d.GetChange(secret.key)
+
+		if client.secretUnchanged(d.Id(), secret.key, ciphertext.(string), configured.(string), secretHashes) {
+			if err := d.Clear(secret.key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+//configuredSecrets gets the secrets of a resource as they are configured (i.e. in plain text), before they are
+//replaced in state by the ciphertext read back from Keboola. Secrets which are already encrypted are skipped.
+func configuredSecrets(d *schema.ResourceData, secrets []secretAttribute) map[string]string {
+	configured := make(map[string]string)
+
+	for _, secret := range secrets {
+		if value := d.Get(secret.key).(string); value != "" && !isEncryptedValue(value) {
+			configured[secret.key] = value
+		}
+	}
+
+	return configured
+}
+
+//setSecretHashes hashes the secrets which were sent to Keboola, once the ciphertext Keboola holds for them has
+//been read back in to state.
+func setSecretHashes(d *schema.ResourceData, client *KBCClient, configured map[string]string) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	secretHashes, _ := d.Get("secret_hashes").(map[string]interface{})
+
+	if secretHashes == nil {
+		secretHashes = make(map[string]interface{})
+	}
+
+	for key, secret := range configured {
+		secretHashes[secretHashKey(key)] = client.secretHash(d.Id(), key, d.Get(key).(string), secret)
+	}
+
+	return d.Set("secret_hashes", secretHashes)
+}
+
+//readAfterSendingSecrets reads a resource once its secrets have been sent to Keboola, which replaces them in state
+//with the ciphertext Keboola holds for them, then hashes the secrets which were sent against that ciphertext.
+func readAfterSendingSecrets(d *schema.ResourceData, meta interface{}, secrets []secretAttribute, read schema.ReadFunc) error {
+	configured := configuredSecrets(d, secrets)
+
+	if err := read(d, meta); err != nil {
+		return err
+	}
+
+	return setSecretHashes(d, meta.(*KBCClient), configured)
+}
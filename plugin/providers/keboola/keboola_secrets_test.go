@@ -0,0 +1,33 @@
+package keboola
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretHash(t *testing.T) {
+	client := &KBCClient{APIKey: "token"}
+	hash := client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret")
+
+	assert.False(t, strings.Contains(hash, "secret"))
+	assert.Equal(t, hash, client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret"))
+	assert.NotEqual(t, hash, client.secretHash("5678", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret"), "The same secret should be hashed differently for another resource")
+	assert.NotEqual(t, hash, client.secretHash("1234", "db_parameters.0.ssh_tunnel.0.private_key", "KBC::ProjectSecure::abc", "secret"), "The same secret should be hashed differently for another attribute")
+	assert.NotEqual(t, hash, client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::def", "secret"), "The same secret should be hashed differently against another ciphertext")
+	assert.NotEqual(t, hash, (&KBCClient{APIKey: "other-token"}).secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret"), "The hash should depend on the token")
+}
+
+func TestSecretUnchanged(t *testing.T) {
+	client := &KBCClient{APIKey: "token"}
+	secretHashes := map[string]interface{}{
+		"db_parameters_0_password": client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret"),
+	}
+
+	assert.True(t, client.secretUnchanged("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret", secretHashes))
+	assert.False(t, client.secretUnchanged("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "changed", secretHashes), "A changed secret should be sent again")
+	assert.False(t, client.secretUnchanged("1234", "db_parameters.0.password", "KBC::ProjectSecure::def", "secret", secretHashes), "A ciphertext changed outside of Terraform should be replaced")
+	assert.False(t, client.secretUnchanged("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "KBC::ProjectSecure::xyz", secretHashes), "An encrypted secret is compared as it is")
+	assert.False(t, client.secretUnchanged("1234", "db_parameters.0.password", "KBC::ProjectSecure::abc", "secret", nil))
+}
@@ -0,0 +1,23 @@
+package keboola
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKBCTimeRFC3339(t *testing.T) {
+	var dates struct {
+		Imported    KBCTime `json:"imported"`
+		NotImported KBCTime `json:"notImported"`
+		Empty       KBCTime `json:"empty"`
+	}
+
+	err := json.Unmarshal([]byte(`{ "imported": "2019-07-20T10:00:05+0200", "notImported": null, "empty": "" }`), &dates)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-07-20T08:00:05Z", dates.Imported.RFC3339(), "Times should be normalised to RFC3339 in UTC")
+	assert.Equal(t, "", dates.NotImported.RFC3339())
+	assert.Equal(t, "", dates.Empty.RFC3339())
+}
@@ -1,8 +1,11 @@
 package keboola
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -10,20 +13,80 @@ import (
 
 // Provider returns a terraform.ResourceProvider for the Keboola provider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_key": {
 				Type:        schema.TypeString,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("STORAGE_API_KEY", nil),
 			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"KBC_HOST", "KBC_URL"}, defaultHost),
+			},
+			"max_retries": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3,
+			},
+			"retry_base_delay": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1s",
+				ValidateFunc: validateDuration,
+			},
+			"max_maintenance_wait": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "10m",
+				ValidateFunc: validateDuration,
+			},
+			"request_timeout_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  120,
+			},
+			"upload_timeout_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1800,
+			},
+			"skip_token_verification": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"page_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPageSize,
+				ValidateFunc: validatePositiveInt,
+			},
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"keboola_storage_bucket":  dataSourceKeboolaStorageBucket(),
+			"keboola_storage_buckets": dataSourceKeboolaStorageBuckets(),
+			"keboola_storage_table":   dataSourceKeboolaStorageTable(),
+			"keboola_storage_tables":  dataSourceKeboolaStorageTables(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"keboola_storage_table":               resourceKeboolaStorageTable(),
+			"keboola_storage_table_alias":         resourceKeboolaStorageTableAlias(),
+			"keboola_storage_table_column":        resourceKeboolaStorageTableColumn(),
+			"keboola_storage_table_snapshot":      resourceKeboolaStorageTableSnapshot(),
+			"keboola_storage_table_metadata":      resourceKeboolaStorageTableMetadata(),
+			"keboola_storage_table_load":          resourceKeboolaStorageTableLoad(),
 			"keboola_storage_bucket":              resourceKeboolaStorageBucket(),
+			"keboola_storage_bucket_sharing":      resourceKeboolaStorageBucketSharing(),
+			"keboola_storage_bucket_metadata":     resourceKeboolaStorageBucketMetadata(),
+			"keboola_linked_bucket":               resourceKeboolaLinkedBucket(),
 			"keboola_transformation":              resourceKeboolaTransformation(),
 			"keboola_transformation_bucket":       resourceKeboolaTransformationBucket(),
+			"keboola_python_transformation":       resourceKeboolaPythonTransformation(),
+			"keboola_r_transformation":            resourceKeboolaRTransformation(),
 			"keboola_gooddata_writer":             resourceKeboolaGoodDataWriter(),
 			"keboola_gooddata_writer_v3":          resourceKeboolaGoodDataWriterV3(),
 			"keboola_gooddata_writer_table":       resourceKeboolaGoodDataTable(),
@@ -33,24 +96,99 @@ func Provider() terraform.ResourceProvider {
 			"keboola_snowflake_writer_tables":     resourceKeboolaSnowflakeWriterTables(),
 			"keboola_postgresql_writer":           resourceKeboolaPostgreSQLWriter(),
 			"keboola_postgresql_writer_tables":    resourceKeboolaPostgreSQLWriterTables(),
+			"keboola_redshift_writer":             resourceKeboolaRedshiftWriter(),
 			"keboola_access_token":                resourceKeboolaAccessToken(),
 			"keboola_orchestration":               resourceKeboolaOrchestration(),
 			"keboola_orchestration_tasks":         resourceKeboolaOrchestrationTasks(),
+			"keboola_scheduler":                   resourceKeboolaScheduler(),
+			"keboola_workspace":                   resourceKeboolaWorkspace(),
 			"keboola_csvimport_extractor":         resourceKeboolaCSVImportExtractor(),
 			"keboola_snowflake_extractor":         resourceKeboolaSnowflakeExtractor(),
 			"keboola_snowflake_extractor_tables":  resourceKeboolaSnowflakeExtractorTables(),
+			"keboola_mysql_extractor":             resourceKeboolaMySQLExtractor(),
+			"keboola_s3_extractor":                resourceKeboolaS3Extractor(),
+			"keboola_gcs_extractor":               resourceKeboolaGCSExtractor(),
 			"keboola_ftp_extractor":               resourceKeboolaFTPExtractor(),
 			"keboola_ftp_extractor_file":          resourceKeboolaFTPExtractorFile(),
+			"keboola_generic_extractor":           resourceKeboolaGenericExtractor(),
+			"keboola_component_configuration":     resourceKeboolaComponentConfiguration(),
+			"keboola_configuration_row":           resourceKeboolaConfigurationRow(),
+			"keboola_shared_code":                 resourceKeboolaSharedCode(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, provider.StopContext(), provider.TerraformVersion)
 	}
+
+	return provider
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(d *schema.ResourceData, stopContext context.Context, terraformVersion string) (interface{}, error) {
 	log.Println("[INFO] Initializing Keboola REST client")
+
+	retryBaseDelay, err := time.ParseDuration(d.Get("retry_base_delay").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	maxMaintenanceWait, err := time.ParseDuration(d.Get("max_maintenance_wait").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := normaliseHost(d.Get("host").(string))
+	if err != nil {
+		return nil, err
+	}
+
 	client := &KBCClient{
-		APIKey: strings.TrimSpace(d.Get("api_key").(string)),
+		APIKey:             strings.TrimSpace(d.Get("api_key").(string)),
+		Host:               host,
+		UserAgent:          providerUserAgent(terraformVersion),
+		MaxRetries:         d.Get("max_retries").(int),
+		RetryBaseDelay:     retryBaseDelay,
+		MaxMaintenanceWait: maxMaintenanceWait,
+		RequestTimeout:     time.Duration(d.Get("request_timeout_seconds").(int)) * time.Second,
+		UploadTimeout:      time.Duration(d.Get("upload_timeout_seconds").(int)) * time.Second,
+		PageSize:           d.Get("page_size").(int),
+		StopContext:        stopContext,
+	}
+
+	client.httpClient = newHTTPClient(client.APIKey)
+
+	//the token is verified up front, as a token for the wrong stack otherwise fails every request
+	if !d.Get("skip_token_verification").(bool) {
+		if err := client.verifyToken(); err != nil {
+			return nil, err
+		}
 	}
+
 	return client, nil
 }
+
+//providerUserAgent builds the User-Agent sent with every request, naming the versions of the provider
+//and of Terraform (which older versions of Terraform do not report).
+func providerUserAgent(terraformVersion string) string {
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+
+	return fmt.Sprintf("terraform-provider-keboola/%s Terraform/%s", ProviderVersion, terraformVersion)
+}
+
+//normaliseHost strips any scheme and trailing slash from a configured host, so that
+//both connection.keboola.com and https://connection.keboola.com/ are accepted. The URLs of
+//the other Keboola services are derived from the host, so it must be the connection host.
+func normaliseHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimSuffix(host, "/")
+
+	if !strings.HasPrefix(host, "connection.") {
+		return "", fmt.Errorf("host must be the connection host of a Keboola stack (e.g. connection.eu-central-1.keboola.com), got %q", host)
+	}
+
+	return host, nil
+}
@@ -1,8 +1,10 @@
 package keboola
 
 import (
+	"context"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -26,10 +28,26 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderPageSizeMustBePositive(t *testing.T) {
+	validatePageSize := Provider().(*schema.Provider).Schema["page_size"].ValidateFunc
+
+	_, errors := validatePageSize(100, "page_size")
+	if len(errors) > 0 {
+		t.Fatalf("err: %v", errors)
+	}
+
+	for _, pageSize := range []int{0, -1} {
+		if _, errors := validatePageSize(pageSize, "page_size"); len(errors) == 0 {
+			t.Fatalf("page_size of %d should not be valid", pageSize)
+		}
+	}
+}
+
 func TestProvider_ApiKey(t *testing.T) {
 	provider := Provider().(*schema.Provider)
 	c, _ := config.NewRawConfig(map[string]interface{}{
-		"api_key": "abcdefg\n\n\n",
+		"api_key":                 "abcdefg\n\n\n",
+		"skip_token_verification": true,
 	})
 
 	provider.Configure(terraform.NewResourceConfig(c))
@@ -53,6 +71,43 @@ func TestProvider_ApiKey(t *testing.T) {
 	}
 }
 
+func TestProvider_HostFromKBCURL(t *testing.T) {
+	defer os.Setenv("KBC_HOST", os.Getenv("KBC_HOST"))
+	defer os.Setenv("KBC_URL", os.Getenv("KBC_URL"))
+
+	os.Unsetenv("KBC_HOST")
+	os.Setenv("KBC_URL", "https://connection.north-europe.azure.keboola.com/")
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"api_key":                 "abcdefg",
+		"skip_token_verification": true,
+	})
+
+	meta, err := providerConfigure(d, context.Background(), "0.11.14")
+
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if host := meta.(*KBCClient).Host; host != "connection.north-europe.azure.keboola.com" {
+		t.Fatalf("err: host should be read from KBC_URL, got %q", host)
+	}
+}
+
+func TestProvider_RejectsHostOtherThanConnection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"api_key":                 "abcdefg",
+		"host":                    "https://syrup.keboola.com/",
+		"skip_token_verification": true,
+	})
+
+	_, err := providerConfigure(d, context.Background(), "0.11.14")
+
+	if err == nil || !strings.Contains(err.Error(), "connection host") {
+		t.Fatalf("err: host without the connection. prefix should be rejected, got %v", err)
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
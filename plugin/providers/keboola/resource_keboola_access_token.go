@@ -27,6 +27,13 @@ type AccessToken struct {
 	BucketPermissions     map[string]interface{} `json:"bucketPermissions"`
 }
 
+//CreateAccessTokenResult holds the results from creating an Access Token. The token
+//itself is only ever returned when it is created.
+type CreateAccessTokenResult struct {
+	ID    json.Number `json:"id"`
+	Token string      `json:"token"`
+}
+
 //endregion
 
 func resourceKeboolaAccessToken() *schema.Resource {
@@ -80,6 +87,11 @@ func resourceKeboolaAccessToken() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validateAccessTokenBucketPermissions,
 			},
+			"token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 		},
 	}
 }
@@ -111,7 +123,7 @@ func resourceKeboolaAccessTokenCreate(d *schema.ResourceData, meta interface{})
 		return extractError(err, createAccessTokenResponse)
 	}
 
-	var createAccessTokenResult CreateResourceResult
+	var createAccessTokenResult CreateAccessTokenResult
 
 	decoder := json.NewDecoder(createAccessTokenResponse.Body)
 	err = decoder.Decode(&createAccessTokenResult)
@@ -121,6 +133,7 @@ func resourceKeboolaAccessTokenCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	d.SetId(string(createAccessTokenResult.ID))
+	d.Set("token", createAccessTokenResult.Token)
 
 	log.Println(fmt.Sprintf("[INFO] Access Token created in Keboola (ID: %s).", string(createAccessTokenResult.ID)) )
 
@@ -24,6 +24,7 @@ func TestAccAccessToken_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("keboola_access_token.test_token", "can_manage_tokens", "false"),
 					resource.TestCheckResourceAttr("keboola_access_token.test_token", "can_read_all_file_uploads", "false"),
 					resource.TestCheckResourceAttr("keboola_access_token.test_token", "expires_in", "10800"),
+					resource.TestCheckResourceAttrSet("keboola_access_token.test_token", "token"),
 				),
 			},
 		},
@@ -0,0 +1,189 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//ComponentConfiguration is the data model for the configuration of any component within
+//the Keboola Storage API, with the configuration itself kept as raw JSON.
+type ComponentConfiguration struct {
+	ID            string          `json:"id,omitempty"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Configuration json.RawMessage `json:"configuration"`
+}
+
+//endregion
+
+func resourceKeboolaComponentConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaComponentConfigurationCreate,
+		Read:   resourceKeboolaComponentConfigurationRead,
+		Update: resourceKeboolaComponentConfigurationUpdate,
+		Delete: resourceKeboolaComponentConfigurationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKeboolaComponentConfigurationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"component_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"configuration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				ValidateFunc:     validateJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+		},
+	}
+}
+
+func resourceKeboolaComponentConfigurationCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Component Configuration in Keboola.")
+
+	createConfigurationForm := url.Values{}
+	createConfigurationForm.Add("name", d.Get("name").(string))
+	createConfigurationForm.Add("description", d.Get("description").(string))
+	createConfigurationForm.Add("configuration", d.Get("configuration").(string))
+
+	createConfigurationBuffer := buffer.FromForm(createConfigurationForm)
+
+	client := meta.(*KBCClient)
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/components/%s/configs", d.Get("component_id").(string)), createConfigurationBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	return resourceKeboolaComponentConfigurationRead(d, meta)
+}
+
+//resourceKeboolaComponentConfigurationImport imports a configuration by the ID of its component and
+//its own ID, as configuration IDs are only unique for a component (e.g. keboola.ex-db-mysql/123456).
+func resourceKeboolaComponentConfigurationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Component Configuration must be imported as <component_id>/<configuration_id>, got %q", d.Id())
+	}
+
+	d.Set("component_id", parts[0])
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKeboolaComponentConfigurationRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Component Configuration from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", d.Get("component_id").(string), d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var componentConfiguration ComponentConfiguration
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&componentConfiguration)
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", componentConfiguration.Name)
+	d.Set("description", componentConfiguration.Description)
+	d.Set("configuration", mapConfigurationJSONToSchema(componentConfiguration.Configuration))
+
+	return nil
+}
+
+//mapConfigurationJSONToSchema returns the configuration as returned by Keboola, except for an empty
+//configuration, which is returned as an empty array rather than an empty object.
+func mapConfigurationJSONToSchema(configurationJSON json.RawMessage) string {
+	configuration := string(configurationJSON)
+
+	if stripWhitespace(configuration) == "[]" || configuration == "" || configuration == "null" {
+		return "{}"
+	}
+
+	return configuration
+}
+
+func resourceKeboolaComponentConfigurationUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Component Configuration in Keboola: %s", d.Id())
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", d.Get("configuration").(string))
+	updateConfigurationForm.Add("changeDescription", "Update configuration via Terraform")
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	client := meta.(*KBCClient)
+	updateResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/%s/configs/%s", d.Get("component_id").(string), d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateResponse) {
+		return extractError(err, updateResponse)
+	}
+
+	return resourceKeboolaComponentConfigurationRead(d, meta)
+}
+
+func resourceKeboolaComponentConfigurationDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Component Configuration in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", d.Get("component_id").(string), d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
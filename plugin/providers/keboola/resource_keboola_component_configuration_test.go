@@ -0,0 +1,105 @@
+package keboola
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccComponentConfiguration_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComponentConfigurationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testComponentConfigurationBasic, `{ "parameters": { "db": { "host": "mysql.example.com", "port": 3306 } } }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_component_configuration.test_configuration", "component_id", "keboola.ex-db-mysql"),
+					resource.TestCheckResourceAttr("keboola_component_configuration.test_configuration", "name", "test_configuration"),
+				),
+			},
+			{
+				Config:   fmt.Sprintf(testComponentConfigurationBasic, `{"parameters":{"db":{"port":3306,"host":"mysql.example.com"}}}`),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "keboola_component_configuration.test_configuration",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return fmt.Sprintf("keboola.ex-db-mysql/%s", s.RootModule().Resources["keboola_component_configuration.test_configuration"].Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+func TestComponentConfigurationReadsEmptyConfigurationAsObject(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/components/keboola.ex-db-mysql/configs/1234", r.URL.Path)
+		w.Write([]byte(`{ "id": "1234", "name": "empty", "configuration": [] }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaComponentConfiguration().Schema, map[string]interface{}{
+		"component_id": "keboola.ex-db-mysql",
+	})
+	d.SetId("1234")
+
+	err := resourceKeboolaComponentConfigurationRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", d.Get("name"))
+	assert.Equal(t, "{}", d.Get("configuration"))
+}
+
+func TestComponentConfigurationImportSplitsComponentID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKeboolaComponentConfiguration().Schema, map[string]interface{}{})
+	d.SetId("keboola.ex-db-mysql/1234")
+
+	result, err := resourceKeboolaComponentConfigurationImport(d, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", result[0].Id())
+	assert.Equal(t, "keboola.ex-db-mysql", result[0].Get("component_id"))
+
+	d.SetId("1234")
+
+	_, err = resourceKeboolaComponentConfigurationImport(d, nil)
+
+	assert.Error(t, err, "A configuration ID alone should not be enough to import a configuration")
+}
+
+func testAccCheckComponentConfigurationDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_component_configuration" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", rs.Primary.Attributes["component_id"], rs.Primary.ID))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Component configuration still exists")
+		}
+	}
+
+	return nil
+}
+
+const testComponentConfigurationBasic = `
+	resource "keboola_component_configuration" "test_configuration" {
+		component_id = "keboola.ex-db-mysql"
+		name = "test_configuration"
+		description = "test description"
+		configuration = <<EOF
+%s
+EOF
+	}`
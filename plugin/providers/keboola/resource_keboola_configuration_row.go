@@ -0,0 +1,314 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//ConfigurationRow is the data model for a row of a component configuration within
+//the Keboola Storage API, with the configuration of the row kept as raw JSON.
+type ConfigurationRow struct {
+	ID            string          `json:"id,omitempty"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Configuration json.RawMessage `json:"configuration"`
+	IsDisabled    KBCBoolean      `json:"isDisabled"`
+}
+
+//ConfigurationWithRows is a component configuration along with its rows, which are run in the
+//order of rowsSortOrder, or in the order they were created when no order has been set.
+type ConfigurationWithRows struct {
+	ID            string             `json:"id"`
+	Rows          []ConfigurationRow `json:"rows"`
+	RowsSortOrder []string           `json:"rowsSortOrder"`
+}
+
+//endregion
+
+func resourceKeboolaConfigurationRow() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaConfigurationRowCreate,
+		Read:   resourceKeboolaConfigurationRowRead,
+		Update: resourceKeboolaConfigurationRowUpdate,
+		Delete: resourceKeboolaConfigurationRowDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKeboolaConfigurationRowImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"component_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"configuration_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"configuration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				ValidateFunc:     validateJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"is_disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			//the zero-based position of the row amongst the rows of the configuration, which is left as it is when not set
+			"position": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func configurationRowsEndpoint(d *schema.ResourceData) string {
+	return fmt.Sprintf("storage/components/%s/configs/%s/rows", d.Get("component_id").(string), d.Get("configuration_id").(string))
+}
+
+func resourceKeboolaConfigurationRowCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Configuration Row in Keboola.")
+
+	createRowForm := url.Values{}
+	createRowForm.Add("name", d.Get("name").(string))
+	createRowForm.Add("description", d.Get("description").(string))
+	createRowForm.Add("configuration", d.Get("configuration").(string))
+	createRowForm.Add("isDisabled", strconv.FormatBool(d.Get("is_disabled").(bool)))
+
+	createRowBuffer := buffer.FromForm(createRowForm)
+
+	client := meta.(*KBCClient)
+	createResponse, err := client.PostToStorage(configurationRowsEndpoint(d), createRowBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createdRow ConfigurationRow
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createdRow)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(createdRow.ID)
+
+	if position, ok := d.GetOkExists("position"); ok {
+		if err := moveConfigurationRow(d, client, position.(int)); err != nil {
+			return err
+		}
+	}
+
+	return resourceKeboolaConfigurationRowRead(d, meta)
+}
+
+//resourceKeboolaConfigurationRowImport imports a row by the IDs of its component and configuration,
+//and its own ID (e.g. keboola.ex-db-mysql/123456/7890).
+func resourceKeboolaConfigurationRowImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("Configuration Row must be imported as <component_id>/<configuration_id>/<row_id>, got %q", d.Id())
+	}
+
+	d.Set("component_id", parts[0])
+	d.Set("configuration_id", parts[1])
+	d.SetId(parts[2])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func getConfigurationWithRows(d *schema.ResourceData, client *KBCClient) (*ConfigurationWithRows, int, error) {
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", d.Get("component_id").(string), d.Get("configuration_id").(string)))
+
+	if hasErrors(err, getResponse) {
+		if err == nil {
+			return nil, getResponse.StatusCode, extractError(err, getResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var configuration ConfigurationWithRows
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&configuration)
+
+	if err != nil {
+		return nil, getResponse.StatusCode, err
+	}
+
+	return &configuration, getResponse.StatusCode, nil
+}
+
+//configurationRowOrder returns the IDs of the rows of the configuration in the order they are run.
+func configurationRowOrder(configuration *ConfigurationWithRows) []string {
+	if len(configuration.RowsSortOrder) > 0 {
+		return configuration.RowsSortOrder
+	}
+
+	rowIDs := make([]string, 0, len(configuration.Rows))
+	for _, row := range configuration.Rows {
+		rowIDs = append(rowIDs, row.ID)
+	}
+
+	return rowIDs
+}
+
+//moveConfigurationRow moves the row to the position amongst the rows of the configuration, or to
+//the end when the position is past the last row.
+func moveConfigurationRow(d *schema.ResourceData, client *KBCClient, position int) error {
+	configuration, _, err := getConfigurationWithRows(d, client)
+
+	if err != nil {
+		return err
+	}
+
+	rowIDs := make([]string, 0, len(configuration.Rows))
+	for _, rowID := range configurationRowOrder(configuration) {
+		if rowID != d.Id() {
+			rowIDs = append(rowIDs, rowID)
+		}
+	}
+
+	if position > len(rowIDs) {
+		position = len(rowIDs)
+	}
+
+	rowIDs = append(rowIDs[:position], append([]string{d.Id()}, rowIDs[position:]...)...)
+
+	sortRowsForm := url.Values{}
+	sortRowsForm.Add("changeDescription", "Reorder configuration rows via Terraform")
+
+	for _, rowID := range rowIDs {
+		sortRowsForm.Add("rowsSortOrder[]", rowID)
+	}
+
+	sortRowsBuffer := buffer.FromForm(sortRowsForm)
+
+	sortResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/%s/configs/%s", d.Get("component_id").(string), d.Get("configuration_id").(string)), sortRowsBuffer)
+
+	if hasErrors(err, sortResponse) {
+		return fmt.Errorf("Unable to move Configuration Row %s: %v", d.Id(), extractError(err, sortResponse))
+	}
+
+	return nil
+}
+
+func resourceKeboolaConfigurationRowRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Configuration Row from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	//the rows are read through the parent configuration, which also holds the order of the rows
+	client := meta.(*KBCClient)
+	configuration, statusCode, err := getConfigurationWithRows(d, client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var configurationRow *ConfigurationRow
+
+	for index := range configuration.Rows {
+		if configuration.Rows[index].ID == d.Id() {
+			configurationRow = &configuration.Rows[index]
+		}
+	}
+
+	if configurationRow == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", configurationRow.Name)
+	d.Set("description", configurationRow.Description)
+	d.Set("configuration", mapConfigurationJSONToSchema(configurationRow.Configuration))
+	d.Set("is_disabled", bool(configurationRow.IsDisabled))
+
+	for position, rowID := range configurationRowOrder(configuration) {
+		if rowID == d.Id() {
+			d.Set("position", position)
+		}
+	}
+
+	return nil
+}
+
+func resourceKeboolaConfigurationRowUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Configuration Row in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	if d.HasChange("name") || d.HasChange("description") || d.HasChange("configuration") || d.HasChange("is_disabled") {
+		updateRowForm := url.Values{}
+		updateRowForm.Add("name", d.Get("name").(string))
+		updateRowForm.Add("description", d.Get("description").(string))
+		updateRowForm.Add("configuration", d.Get("configuration").(string))
+		updateRowForm.Add("isDisabled", strconv.FormatBool(d.Get("is_disabled").(bool)))
+		updateRowForm.Add("changeDescription", "Update configuration row via Terraform")
+
+		updateRowBuffer := buffer.FromForm(updateRowForm)
+
+		updateResponse, err := client.PutToStorage(fmt.Sprintf("%s/%s", configurationRowsEndpoint(d), d.Id()), updateRowBuffer)
+
+		if hasErrors(err, updateResponse) {
+			return extractError(err, updateResponse)
+		}
+	}
+
+	if d.HasChange("position") {
+		if err := moveConfigurationRow(d, client, d.Get("position").(int)); err != nil {
+			return err
+		}
+	}
+
+	return resourceKeboolaConfigurationRowRead(d, meta)
+}
+
+func resourceKeboolaConfigurationRowDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Configuration Row in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("%s/%s", configurationRowsEndpoint(d), d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
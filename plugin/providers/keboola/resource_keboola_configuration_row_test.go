@@ -0,0 +1,156 @@
+package keboola
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccConfigurationRow_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConfigurationRowDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfigurationRowBasic, "false", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_configuration_row.orders", "position", "0"),
+					resource.TestCheckResourceAttr("keboola_configuration_row.customers", "position", "1"),
+					resource.TestCheckResourceAttr("keboola_configuration_row.customers", "is_disabled", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfigurationRowBasic, "true", 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_configuration_row.customers", "position", "0"),
+					resource.TestCheckResourceAttr("keboola_configuration_row.customers", "is_disabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestConfigurationRowCreateMovesRowToPosition(t *testing.T) {
+	var rowsSortOrder []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234/rows":
+			r.ParseForm()
+			assert.Equal(t, "false", r.PostForm.Get("isDisabled"))
+			w.Write([]byte(`{ "id": "3" }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234":
+			r.ParseForm()
+			rowsSortOrder = r.PostForm["rowsSortOrder[]"]
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234" && rowsSortOrder == nil:
+			w.Write([]byte(`{ "id": "1234", "rows": [ { "id": "1" }, { "id": "2" }, { "id": "3" } ], "rowsSortOrder": [] }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234":
+			w.Write([]byte(`{
+				"id": "1234",
+				"rows": [
+					{ "id": "1" },
+					{ "id": "2" },
+					{ "id": "3", "name": "orders", "configuration": { "parameters": { "table": "orders" } }, "isDisabled": false }
+				],
+				"rowsSortOrder": [ "1", "3", "2" ]
+			}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaConfigurationRow().Schema, map[string]interface{}{
+		"component_id":     "keboola.ex-db-mysql",
+		"configuration_id": "1234",
+		"name":             "orders",
+		"configuration":    `{ "parameters": { "table": "orders" } }`,
+		"position":         1,
+	})
+
+	err := resourceKeboolaConfigurationRowCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3", d.Id())
+	assert.Equal(t, []string{"1", "3", "2"}, rowsSortOrder)
+	assert.Equal(t, 1, d.Get("position"))
+	assert.Equal(t, `{ "parameters": { "table": "orders" } }`, d.Get("configuration"))
+}
+
+func TestConfigurationRowReadRemovesMissingRow(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "1234", "rows": [ { "id": "1" } ] }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaConfigurationRow().Schema, map[string]interface{}{
+		"component_id":     "keboola.ex-db-mysql",
+		"configuration_id": "1234",
+	})
+	d.SetId("3")
+
+	err := resourceKeboolaConfigurationRowRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A row deleted outside of Terraform should be removed from state")
+}
+
+func TestConfigurationRowImportSplitsIDs(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKeboolaConfigurationRow().Schema, map[string]interface{}{})
+	d.SetId("keboola.ex-db-mysql/1234/3")
+
+	result, err := resourceKeboolaConfigurationRowImport(d, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result[0].Id())
+	assert.Equal(t, "keboola.ex-db-mysql", result[0].Get("component_id"))
+	assert.Equal(t, "1234", result[0].Get("configuration_id"))
+}
+
+func testAccCheckConfigurationRowDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_configuration_row" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s/rows/%s", rs.Primary.Attributes["component_id"], rs.Primary.Attributes["configuration_id"], rs.Primary.ID))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Configuration row still exists")
+		}
+	}
+
+	return nil
+}
+
+const testConfigurationRowBasic = `
+	resource "keboola_component_configuration" "test_configuration" {
+		component_id = "keboola.ex-db-mysql"
+		name = "test_configuration"
+	}
+
+	resource "keboola_configuration_row" "orders" {
+		component_id = "${keboola_component_configuration.test_configuration.component_id}"
+		configuration_id = "${keboola_component_configuration.test_configuration.id}"
+		name = "orders"
+		configuration = "{ \"parameters\": { \"table\": \"orders\" } }"
+	}
+
+	resource "keboola_configuration_row" "customers" {
+		component_id = "${keboola_component_configuration.test_configuration.component_id}"
+		configuration_id = "${keboola_component_configuration.test_configuration.id}"
+		name = "customers"
+		configuration = "{ \"parameters\": { \"table\": \"customers\" } }"
+		is_disabled = %s
+		position = %d
+		depends_on = [ "keboola_configuration_row.orders" ]
+	}`
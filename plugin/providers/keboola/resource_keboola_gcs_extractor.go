@@ -0,0 +1,306 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//GCSExtractor is the data model for Google Cloud Storage Extractors within
+//the Keboola Storage API.
+type GCSExtractor struct {
+	ID            string                    `json:"id,omitempty"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Configuration GCSExtractorConfiguration `json:"configuration"`
+}
+
+type GCSExtractorConfiguration struct {
+	Storage    GCSExtractorStorage    `json:"storage"`
+	Parameters GCSExtractorParameters `json:"parameters"`
+}
+
+type GCSExtractorStorage struct {
+	Output struct {
+		Tables []ExtractorOutputTable `json:"tables"`
+	} `json:"output"`
+}
+
+type GCSExtractorParameters struct {
+	EncryptedServiceAccountKey string `json:"#serviceAccountKey,omitempty"`
+	Bucket                     string `json:"bucket"`
+	Key                        string `json:"key"`
+	NewFilesOnly               bool   `json:"newFilesOnly"`
+	SaveAs                     string `json:"saveAs"`
+}
+
+//endregion
+
+//gcsExtractorSecrets are the secrets of a GCS Extractor, which are kept in state as the ciphertext Keboola holds for them.
+var gcsExtractorSecrets = []secretAttribute{
+	{key: "service_account_key", required: true},
+}
+
+func resourceKeboolaGCSExtractor() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaGCSExtractorCreate,
+		Read:   resourceKeboolaGCSExtractorRead,
+		Update: resourceKeboolaGCSExtractorUpdate,
+		Delete: resourceKeboolaGCSExtractorDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceKeboolaGCSExtractorCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			//the JSON key file of the service account used to read the bucket
+			"service_account_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Sensitive:    true,
+				ValidateFunc: validateGCPServiceAccountKey,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			//all of the files whose name starts with the prefix are extracted, or every file when it is not set
+			"prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"new_files_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"output":        &extractorOutputSchema,
+			"secret_hashes": &secretHashesSchema,
+		},
+	}
+}
+
+//resourceKeboolaGCSExtractorCustomizeDiff only plans a change to the service account key when it differs from
+//the one which was encrypted as the ciphertext in state.
+func resourceKeboolaGCSExtractorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeSecretsDiff(d, meta, gcsExtractorSecrets)
+}
+
+func resourceKeboolaGCSExtractorCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating GCS Extractor in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	createExtractorForm := url.Values{}
+	createExtractorForm.Add("name", d.Get("name").(string))
+	createExtractorForm.Add("description", d.Get("description").(string))
+
+	createExtractorBuffer := buffer.FromForm(createExtractorForm)
+
+	createResponse, err := client.PostToStorage("storage/components/keboola.ex-google-cloud-storage-v2/configs", createExtractorBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	configuration, err := mapGCSExtractorConfiguration(d, client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateGCSExtractorConfiguration(d, configuration, "Created GCS Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, gcsExtractorSecrets, resourceKeboolaGCSExtractorRead)
+}
+
+//mapGCSExtractorConfiguration builds the extractor configuration from the resource. The service account
+//key is encrypted for the extractor before being sent, unless it has not changed, in which case it is
+//taken from the existing configuration instead, as only its ciphertext is kept in state.
+func mapGCSExtractorConfiguration(d *schema.ResourceData, client *KBCClient, existing *GCSExtractorConfiguration) (GCSExtractorConfiguration, error) {
+	outputTables, saveAs, err := mapExtractorOutputToModel(d.Get("output.0").(map[string]interface{}))
+
+	if err != nil {
+		return GCSExtractorConfiguration{}, err
+	}
+
+	parameters := GCSExtractorParameters{
+		Bucket:       d.Get("bucket").(string),
+		Key:          d.Get("prefix").(string) + "*",
+		NewFilesOnly: d.Get("new_files_only").(bool),
+		SaveAs:       saveAs,
+	}
+
+	if existing != nil && !d.HasChange("service_account_key") {
+		parameters.EncryptedServiceAccountKey = existing.Parameters.EncryptedServiceAccountKey
+	} else {
+		encryptedServiceAccountKey, err := client.EncryptValue("keboola.ex-google-cloud-storage-v2", d.Get("service_account_key").(string))
+
+		if err != nil {
+			return GCSExtractorConfiguration{}, fmt.Errorf("Unable to encrypt GCS service account key: %v", err)
+		}
+
+		parameters.EncryptedServiceAccountKey = encryptedServiceAccountKey
+	}
+
+	configuration := GCSExtractorConfiguration{
+		Parameters: parameters,
+	}
+
+	configuration.Storage.Output.Tables = outputTables
+
+	return configuration, nil
+}
+
+func updateGCSExtractorConfiguration(d *schema.ResourceData, configuration GCSExtractorConfiguration, changeDescription string, client *KBCClient) error {
+	gcsConfigJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", string(gcsConfigJSON))
+	updateConfigurationForm.Add("changeDescription", changeDescription)
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	updateConfigurationResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/keboola.ex-google-cloud-storage-v2/configs/%s", d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateConfigurationResponse) {
+		return extractError(err, updateConfigurationResponse)
+	}
+
+	return nil
+}
+
+func getGCSExtractor(id string, client *KBCClient) (*GCSExtractor, int, error) {
+	getExtractorResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.ex-google-cloud-storage-v2/configs/%s", id))
+
+	if hasErrors(err, getExtractorResponse) {
+		if err == nil {
+			return nil, getExtractorResponse.StatusCode, extractError(err, getExtractorResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var gcsExtractor GCSExtractor
+
+	decoder := json.NewDecoder(getExtractorResponse.Body)
+	err = decoder.Decode(&gcsExtractor)
+
+	if err != nil {
+		return nil, getExtractorResponse.StatusCode, err
+	}
+
+	return &gcsExtractor, getExtractorResponse.StatusCode, nil
+}
+
+func resourceKeboolaGCSExtractorRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading GCS Extractor from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	gcsExtractor, statusCode, err := getGCSExtractor(d.Id(), client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	parameters := gcsExtractor.Configuration.Parameters
+
+	d.Set("name", gcsExtractor.Name)
+	d.Set("description", gcsExtractor.Description)
+	d.Set("bucket", parameters.Bucket)
+	d.Set("prefix", strings.TrimSuffix(parameters.Key, "*"))
+	d.Set("new_files_only", parameters.NewFilesOnly)
+	d.Set("output", mapExtractorOutputToSchema(gcsExtractor.Configuration.Storage.Output.Tables))
+
+	//Keboola only returns the encrypted service account key, which is kept in state in place of the key itself.
+	d.Set("service_account_key", parameters.EncryptedServiceAccountKey)
+
+	return nil
+}
+
+func resourceKeboolaGCSExtractorUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating GCS Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	gcsExtractor, _, err := getGCSExtractor(d.Id(), client)
+
+	if err != nil {
+		return err
+	}
+
+	configuration, err := mapGCSExtractorConfiguration(d, client, &gcsExtractor.Configuration)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateGCSExtractorConfiguration(d, configuration, "Updated GCS Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, gcsExtractorSecrets, resourceKeboolaGCSExtractorRead)
+}
+
+func resourceKeboolaGCSExtractorDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting GCS Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/keboola.ex-google-cloud-storage-v2/configs/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,706 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+const (
+	genericExtractorComponentID = "ex-generic-v2"
+
+	genericExtractorAuthenticationBasic  = "basic"
+	genericExtractorAuthenticationAPIKey = "api_key"
+	genericExtractorAuthenticationOAuth  = "oauth"
+)
+
+//region Keboola API Contracts
+
+//GenericExtractor is the data model for Generic (HTTP) Extractors within
+//the Keboola Storage API.
+type GenericExtractor struct {
+	ID            string                        `json:"id,omitempty"`
+	Name          string                        `json:"name"`
+	Description   string                        `json:"description"`
+	Configuration GenericExtractorConfiguration `json:"configuration"`
+}
+
+type GenericExtractorConfiguration struct {
+	Parameters    GenericExtractorParameters     `json:"parameters"`
+	Authorization *GenericExtractorAuthorization `json:"authorization,omitempty"`
+}
+
+type GenericExtractorParameters struct {
+	API    GenericExtractorAPI    `json:"api"`
+	Config GenericExtractorConfig `json:"config"`
+}
+
+type GenericExtractorAPI struct {
+	BaseURL        string                          `json:"baseUrl"`
+	Authentication *GenericExtractorAuthentication `json:"authentication,omitempty"`
+	Pagination     *GenericExtractorPagination     `json:"pagination,omitempty"`
+	HTTP           *GenericExtractorHTTP           `json:"http,omitempty"`
+}
+
+//GenericExtractorAuthentication is the authentication method of the API. Basic authentication needs
+//no more than the type, as it uses the username and #password of the config.
+type GenericExtractorAuthentication struct {
+	Type    string                               `json:"type"`
+	Query   map[string]GenericExtractorAttribute `json:"query,omitempty"`
+	Format  string                               `json:"format,omitempty"`
+	Headers map[string]interface{}               `json:"headers,omitempty"`
+}
+
+//GenericExtractorAttribute references a value of the config, such as an encrypted secret.
+type GenericExtractorAttribute struct {
+	Attr string `json:"attr"`
+}
+
+type GenericExtractorHTTP struct {
+	Headers map[string]GenericExtractorAttribute `json:"headers,omitempty"`
+}
+
+//GenericExtractorPagination holds a scroller for each job which is paginated, which the job
+//refers to by name.
+type GenericExtractorPagination struct {
+	Method    string                              `json:"method"`
+	Scrollers map[string]GenericExtractorScroller `json:"scrollers"`
+}
+
+type GenericExtractorScroller struct {
+	Method        string `json:"method"`
+	Limit         int    `json:"limit,omitempty"`
+	LimitParam    string `json:"limitParam,omitempty"`
+	OffsetParam   string `json:"offsetParam,omitempty"`
+	PageParam     string `json:"pageParam,omitempty"`
+	URLKey        string `json:"urlKey,omitempty"`
+	ResponseParam string `json:"responseParam,omitempty"`
+	QueryParam    string `json:"queryParam,omitempty"`
+}
+
+type GenericExtractorConfig struct {
+	OutputBucket      string                `json:"outputBucket,omitempty"`
+	IncrementalOutput bool                  `json:"incrementalOutput"`
+	Jobs              []GenericExtractorJob `json:"jobs"`
+	Mappings          json.RawMessage       `json:"mappings,omitempty"`
+	Username          string                `json:"username,omitempty"`
+	EncryptedPassword string                `json:"#password,omitempty"`
+	EncryptedAPIKey   string                `json:"#apiKey,omitempty"`
+}
+
+type GenericExtractorJob struct {
+	Endpoint  string            `json:"endpoint"`
+	DataType  string            `json:"dataType,omitempty"`
+	DataField string            `json:"dataField,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Scroller  string            `json:"scroller,omitempty"`
+}
+
+//GenericExtractorAuthorization refers to OAuth credentials authorized for the extractor
+//(e.g. through the UI), which are kept by the Keboola OAuth API.
+type GenericExtractorAuthorization struct {
+	OAuthAPI struct {
+		ID string `json:"id"`
+	} `json:"oauth_api"`
+}
+
+//endregion
+
+//genericExtractorSecrets are the secrets of a Generic Extractor, which are kept in state as the ciphertext Keboola holds for them.
+var genericExtractorSecrets = []secretAttribute{
+	{key: "authentication.0.password"},
+	{key: "authentication.0.api_key"},
+}
+
+func resourceKeboolaGenericExtractor() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaGenericExtractorCreate,
+		Read:   resourceKeboolaGenericExtractorRead,
+		Update: resourceKeboolaGenericExtractorUpdate,
+		Delete: resourceKeboolaGenericExtractorDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceKeboolaGenericExtractorCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"base_url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"authentication": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateGenericExtractorAuthenticationType,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"api_key": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"api_key_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"api_key_location": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "query",
+							ValidateFunc: validateGenericExtractorAPIKeyLocation,
+						},
+						"oauth_credentials_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"job": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"endpoint": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"data_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"data_field": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"params": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"pagination": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"method": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateGenericExtractorPaginationMethod,
+									},
+									"limit": {
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+									"limit_param": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"offset_param": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"page_param": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"url_key": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"response_param": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"query_param": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"mappings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"output_bucket": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"incremental_output": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"secret_hashes": &secretHashesSchema,
+		},
+	}
+}
+
+//resourceKeboolaGenericExtractorCustomizeDiff only plans a change to a secret when it differs from the one
+//which was encrypted as the ciphertext in state.
+func resourceKeboolaGenericExtractorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeSecretsDiff(d, meta, genericExtractorSecrets)
+}
+
+func resourceKeboolaGenericExtractorCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Generic Extractor in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	createExtractorForm := url.Values{}
+	createExtractorForm.Add("name", d.Get("name").(string))
+	createExtractorForm.Add("description", d.Get("description").(string))
+
+	createExtractorBuffer := buffer.FromForm(createExtractorForm)
+
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/components/%s/configs", genericExtractorComponentID), createExtractorBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	configuration, err := mapGenericExtractorConfiguration(d, client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateGenericExtractorConfiguration(d, configuration, "Created Generic Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, genericExtractorSecrets, resourceKeboolaGenericExtractorRead)
+}
+
+//mapGenericExtractorConfiguration builds the extractor configuration from the resource. Secrets are
+//encrypted for the extractor before being sent; those which have not changed are taken from the
+//existing configuration instead, as only a hash of them is kept in state.
+func mapGenericExtractorConfiguration(d *schema.ResourceData, client *KBCClient, existing *GenericExtractorConfiguration) (GenericExtractorConfiguration, error) {
+	configuration := GenericExtractorConfiguration{
+		Parameters: GenericExtractorParameters{
+			API: GenericExtractorAPI{
+				BaseURL: d.Get("base_url").(string),
+			},
+			Config: GenericExtractorConfig{
+				OutputBucket:      d.Get("output_bucket").(string),
+				IncrementalOutput: d.Get("incremental_output").(bool),
+			},
+		},
+	}
+
+	if mappings := d.Get("mappings").(string); mappings != "" {
+		configuration.Parameters.Config.Mappings = json.RawMessage(mappings)
+	}
+
+	jobs, pagination := mapGenericExtractorJobsToModel(d.Get("job").([]interface{}))
+
+	configuration.Parameters.Config.Jobs = jobs
+	configuration.Parameters.API.Pagination = pagination
+
+	authentications := d.Get("authentication").([]interface{})
+
+	if len(authentications) == 0 {
+		return configuration, nil
+	}
+
+	authentication := authentications[0].(map[string]interface{})
+	api := &configuration.Parameters.API
+	config := &configuration.Parameters.Config
+
+	var existingConfig GenericExtractorConfig
+	if existing != nil {
+		existingConfig = existing.Parameters.Config
+	}
+
+	switch authentication["type"].(string) {
+	case genericExtractorAuthenticationBasic:
+		username := authentication["username"].(string)
+		password := authentication["password"].(string)
+
+		if username == "" || password == "" {
+			return GenericExtractorConfiguration{}, fmt.Errorf("basic authentication needs both a username and a password")
+		}
+
+		encryptedPassword, err := encryptGenericExtractorSecret(d, client, "authentication.0.password", password, existingConfig.EncryptedPassword)
+
+		if err != nil {
+			return GenericExtractorConfiguration{}, fmt.Errorf("Unable to encrypt Generic Extractor password: %v", err)
+		}
+
+		api.Authentication = &GenericExtractorAuthentication{Type: "basic"}
+		config.Username = username
+		config.EncryptedPassword = encryptedPassword
+	case genericExtractorAuthenticationAPIKey:
+		apiKey := authentication["api_key"].(string)
+		apiKeyName := authentication["api_key_name"].(string)
+
+		if apiKey == "" || apiKeyName == "" {
+			return GenericExtractorConfiguration{}, fmt.Errorf("api_key authentication needs both an api_key and an api_key_name")
+		}
+
+		encryptedAPIKey, err := encryptGenericExtractorSecret(d, client, "authentication.0.api_key", apiKey, existingConfig.EncryptedAPIKey)
+
+		if err != nil {
+			return GenericExtractorConfiguration{}, fmt.Errorf("Unable to encrypt Generic Extractor API key: %v", err)
+		}
+
+		config.EncryptedAPIKey = encryptedAPIKey
+
+		//the key is sent either as a query parameter or a header, both referring to the encrypted key in the config
+		apiKeyAttribute := map[string]GenericExtractorAttribute{apiKeyName: {Attr: "#apiKey"}}
+
+		if authentication["api_key_location"].(string) == "header" {
+			api.HTTP = &GenericExtractorHTTP{Headers: apiKeyAttribute}
+		} else {
+			api.Authentication = &GenericExtractorAuthentication{Type: "query", Query: apiKeyAttribute}
+		}
+	case genericExtractorAuthenticationOAuth:
+		credentialsID := authentication["oauth_credentials_id"].(string)
+
+		if credentialsID == "" {
+			return GenericExtractorConfiguration{}, fmt.Errorf("oauth authentication needs the oauth_credentials_id of the authorized credentials")
+		}
+
+		api.Authentication = &GenericExtractorAuthentication{
+			Type:   "oauth20",
+			Format: "json",
+			Headers: map[string]interface{}{
+				"Authorization": map[string]interface{}{
+					"function": "concat",
+					"args": []interface{}{
+						"Bearer ",
+						map[string]interface{}{"authorization": "data.access_token"},
+					},
+				},
+			},
+		}
+
+		configuration.Authorization = &GenericExtractorAuthorization{}
+		configuration.Authorization.OAuthAPI.ID = credentialsID
+	}
+
+	return configuration, nil
+}
+
+//encryptGenericExtractorSecret encrypts a secret for the extractor, unless it has not changed since
+//it was encrypted for the existing configuration, in which case the existing encrypted secret is kept.
+func encryptGenericExtractorSecret(d *schema.ResourceData, client *KBCClient, key string, secret string, existingSecret string) (string, error) {
+	if existingSecret != "" && !d.HasChange(key) {
+		return existingSecret, nil
+	}
+
+	return client.EncryptValue(genericExtractorComponentID, secret)
+}
+
+//mapGenericExtractorJobsToModel maps the jobs of the resource, along with a scroller named after
+//each paginated job, as Keboola only paginates jobs through the scrollers of the API.
+func mapGenericExtractorJobsToModel(jobs []interface{}) ([]GenericExtractorJob, *GenericExtractorPagination) {
+	extractorJobs := make([]GenericExtractorJob, 0, len(jobs))
+	scrollers := make(map[string]GenericExtractorScroller)
+
+	for index, job := range jobs {
+		config := job.(map[string]interface{})
+
+		extractorJob := GenericExtractorJob{
+			Endpoint:  config["endpoint"].(string),
+			DataType:  config["data_type"].(string),
+			DataField: config["data_field"].(string),
+		}
+
+		if params := config["params"].(map[string]interface{}); len(params) > 0 {
+			extractorJob.Params = make(map[string]string, len(params))
+
+			for name, value := range params {
+				extractorJob.Params[name] = value.(string)
+			}
+		}
+
+		if paginations := config["pagination"].([]interface{}); len(paginations) > 0 && paginations[0] != nil {
+			pagination := paginations[0].(map[string]interface{})
+
+			extractorJob.Scroller = fmt.Sprintf("job_%d", index)
+			scrollers[extractorJob.Scroller] = GenericExtractorScroller{
+				Method:        pagination["method"].(string),
+				Limit:         pagination["limit"].(int),
+				LimitParam:    pagination["limit_param"].(string),
+				OffsetParam:   pagination["offset_param"].(string),
+				PageParam:     pagination["page_param"].(string),
+				URLKey:        pagination["url_key"].(string),
+				ResponseParam: pagination["response_param"].(string),
+				QueryParam:    pagination["query_param"].(string),
+			}
+		}
+
+		extractorJobs = append(extractorJobs, extractorJob)
+	}
+
+	if len(scrollers) == 0 {
+		return extractorJobs, nil
+	}
+
+	return extractorJobs, &GenericExtractorPagination{Method: "multiple", Scrollers: scrollers}
+}
+
+func mapGenericExtractorJobsToSchema(extractorJobs []GenericExtractorJob, pagination *GenericExtractorPagination) []map[string]interface{} {
+	jobs := make([]map[string]interface{}, 0, len(extractorJobs))
+
+	for _, extractorJob := range extractorJobs {
+		jobDetails := map[string]interface{}{
+			"endpoint":   extractorJob.Endpoint,
+			"data_type":  extractorJob.DataType,
+			"data_field": extractorJob.DataField,
+			"params":     extractorJob.Params,
+		}
+
+		if pagination != nil {
+			if scroller, ok := pagination.Scrollers[extractorJob.Scroller]; ok && extractorJob.Scroller != "" {
+				jobDetails["pagination"] = []map[string]interface{}{
+					{
+						"method":         scroller.Method,
+						"limit":          scroller.Limit,
+						"limit_param":    scroller.LimitParam,
+						"offset_param":   scroller.OffsetParam,
+						"page_param":     scroller.PageParam,
+						"url_key":        scroller.URLKey,
+						"response_param": scroller.ResponseParam,
+						"query_param":    scroller.QueryParam,
+					},
+				}
+			}
+		}
+
+		jobs = append(jobs, jobDetails)
+	}
+
+	return jobs
+}
+
+//mapGenericExtractorAuthenticationToSchema maps the authentication of the extractor back to the
+//resource. Keboola only returns the encrypted secrets, which are kept in state in place of the secrets themselves.
+func mapGenericExtractorAuthenticationToSchema(configuration GenericExtractorConfiguration) []map[string]interface{} {
+	api := configuration.Parameters.API
+	config := configuration.Parameters.Config
+
+	switch {
+	case configuration.Authorization != nil:
+		return []map[string]interface{}{
+			{
+				"type":                 genericExtractorAuthenticationOAuth,
+				"oauth_credentials_id": configuration.Authorization.OAuthAPI.ID,
+			},
+		}
+	case api.Authentication != nil && api.Authentication.Type == "basic":
+		return []map[string]interface{}{
+			{
+				"type":     genericExtractorAuthenticationBasic,
+				"username": config.Username,
+				"password": config.EncryptedPassword,
+			},
+		}
+	case config.EncryptedAPIKey != "":
+		apiKeyLocation := "query"
+		apiKeyAttributes := map[string]GenericExtractorAttribute{}
+
+		if api.Authentication != nil && api.Authentication.Type == "query" {
+			apiKeyAttributes = api.Authentication.Query
+		} else if api.HTTP != nil {
+			apiKeyLocation = "header"
+			apiKeyAttributes = api.HTTP.Headers
+		}
+
+		apiKeyName := ""
+		for name, attribute := range apiKeyAttributes {
+			if attribute.Attr == "#apiKey" {
+				apiKeyName = name
+			}
+		}
+
+		return []map[string]interface{}{
+			{
+				"type":             genericExtractorAuthenticationAPIKey,
+				"api_key":          config.EncryptedAPIKey,
+				"api_key_name":     apiKeyName,
+				"api_key_location": apiKeyLocation,
+			},
+		}
+	}
+
+	return nil
+}
+
+func updateGenericExtractorConfiguration(d *schema.ResourceData, configuration GenericExtractorConfiguration, changeDescription string, client *KBCClient) error {
+	genericConfigJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", string(genericConfigJSON))
+	updateConfigurationForm.Add("changeDescription", changeDescription)
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	updateConfigurationResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/%s/configs/%s", genericExtractorComponentID, d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateConfigurationResponse) {
+		return extractError(err, updateConfigurationResponse)
+	}
+
+	return nil
+}
+
+func getGenericExtractor(id string, client *KBCClient) (*GenericExtractor, int, error) {
+	getExtractorResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", genericExtractorComponentID, id))
+
+	if hasErrors(err, getExtractorResponse) {
+		if err == nil {
+			return nil, getExtractorResponse.StatusCode, extractError(err, getExtractorResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var genericExtractor GenericExtractor
+
+	decoder := json.NewDecoder(getExtractorResponse.Body)
+	err = decoder.Decode(&genericExtractor)
+
+	if err != nil {
+		return nil, getExtractorResponse.StatusCode, err
+	}
+
+	return &genericExtractor, getExtractorResponse.StatusCode, nil
+}
+
+func resourceKeboolaGenericExtractorRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Generic Extractor from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	genericExtractor, statusCode, err := getGenericExtractor(d.Id(), client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	configuration := genericExtractor.Configuration
+
+	d.Set("name", genericExtractor.Name)
+	d.Set("description", genericExtractor.Description)
+	d.Set("base_url", configuration.Parameters.API.BaseURL)
+	d.Set("authentication", mapGenericExtractorAuthenticationToSchema(configuration))
+	d.Set("job", mapGenericExtractorJobsToSchema(configuration.Parameters.Config.Jobs, configuration.Parameters.API.Pagination))
+	d.Set("output_bucket", configuration.Parameters.Config.OutputBucket)
+	d.Set("incremental_output", configuration.Parameters.Config.IncrementalOutput)
+
+	if len(configuration.Parameters.Config.Mappings) > 0 {
+		d.Set("mappings", string(configuration.Parameters.Config.Mappings))
+	} else {
+		d.Set("mappings", "")
+	}
+
+	return nil
+}
+
+func resourceKeboolaGenericExtractorUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Generic Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	genericExtractor, _, err := getGenericExtractor(d.Id(), client)
+
+	if err != nil {
+		return err
+	}
+
+	configuration, err := mapGenericExtractorConfiguration(d, client, &genericExtractor.Configuration)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateGenericExtractorConfiguration(d, configuration, "Updated Generic Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, genericExtractorSecrets, resourceKeboolaGenericExtractorRead)
+}
+
+func resourceKeboolaGenericExtractorDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Generic Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", genericExtractorComponentID, d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package keboola
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccGenericExtractor_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGenericExtractorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGenericExtractorBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "name", "test_generic_extractor"),
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "base_url", "https://api.example.com/v1/"),
+					resource.TestMatchResourceAttr("keboola_generic_extractor.test_extractor", "authentication.0.api_key", regexp.MustCompile("^KBC::")),
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "job.#", "1"),
+				),
+			},
+			{
+				Config: testGenericExtractorUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "authentication.0.type", "basic"),
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "job.#", "2"),
+					resource.TestCheckResourceAttr("keboola_generic_extractor.test_extractor", "job.1.pagination.0.method", "pagenum"),
+				),
+			},
+		},
+	})
+}
+
+func TestGenericExtractorCreateEncryptsSecrets(t *testing.T) {
+	var savedConfiguration string
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "encryption.example.com":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write([]byte("KBC::ProjectSecure::" + string(body)))
+		case r.URL.Path == "/v2/storage/tokens/verify":
+			w.Write([]byte(`{ "owner": { "id": 567 } }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/ex-generic-v2/configs":
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/ex-generic-v2/configs/1234":
+			r.ParseForm()
+			savedConfiguration = r.PostForm.Get("configuration")
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/ex-generic-v2/configs/1234":
+			w.Write([]byte(fmt.Sprintf(`{ "id": "1234", "name": "extractor", "configuration": %s }`, savedConfiguration)))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaGenericExtractor().Schema, map[string]interface{}{
+		"name":     "extractor",
+		"base_url": "https://api.example.com/v1/",
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"type":             "api_key",
+				"api_key":          "secret",
+				"api_key_name":     "X-Api-Key",
+				"api_key_location": "header",
+			},
+		},
+		"job": []interface{}{
+			map[string]interface{}{
+				"endpoint":   "orders",
+				"data_field": "items",
+				"params":     map[string]interface{}{"status": "open"},
+				"pagination": []interface{}{
+					map[string]interface{}{
+						"method":       "offset",
+						"limit":        100,
+						"offset_param": "skip",
+					},
+				},
+			},
+		},
+		"mappings": `{ "orders": { "id": { "type": "column", "mapping": { "destination": "id" } } } }`,
+	})
+
+	err := resourceKeboolaGenericExtractorCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Contains(t, savedConfiguration, `"#apiKey":"KBC::ProjectSecure::secret"`)
+	assert.Contains(t, savedConfiguration, `"http":{"headers":{"X-Api-Key":{"attr":"#apiKey"}}}`)
+	assert.Contains(t, savedConfiguration, `"pagination":{"method":"multiple","scrollers":{"job_0":{"method":"offset","limit":100,"offsetParam":"skip"}}}`)
+	assert.Contains(t, savedConfiguration, `"scroller":"job_0"`)
+
+	assert.Equal(t, "KBC::ProjectSecure::secret", d.Get("authentication.0.api_key"), "Only the ciphertext of the API key should be kept in state")
+	assert.Equal(t, client.secretHash("1234", "authentication.0.api_key", "KBC::ProjectSecure::secret", "secret"), d.Get("secret_hashes.authentication_0_api_key"))
+	assert.Equal(t, "X-Api-Key", d.Get("authentication.0.api_key_name"))
+	assert.Equal(t, "header", d.Get("authentication.0.api_key_location"))
+	assert.Equal(t, "open", d.Get("job.0.params.status"))
+	assert.Equal(t, "skip", d.Get("job.0.pagination.0.offset_param"))
+}
+
+func TestGenericExtractorUpdateKeepsUnchangedSecrets(t *testing.T) {
+	existing := GenericExtractorConfiguration{}
+	existing.Parameters.Config.EncryptedPassword = "KBC::ProjectSecure::existing"
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+	})
+	defer server.Close()
+
+	//only a hash of the password is in state, which has not changed since it was encrypted
+	d := resourceKeboolaGenericExtractor().Data(&terraform.InstanceState{
+		ID: "1234",
+		Attributes: map[string]string{
+			"name":                      "extractor",
+			"base_url":                  "https://api.example.com/v1/",
+			"authentication.#":          "1",
+			"authentication.0.type":     "basic",
+			"authentication.0.username": "keboola",
+			"authentication.0.password": "KBC::ProjectSecure::existing",
+			"job.#":                     "1",
+			"job.0.endpoint":            "orders",
+		},
+	})
+
+	configuration, err := mapGenericExtractorConfiguration(d, client, &existing)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "basic", configuration.Parameters.API.Authentication.Type)
+	assert.Equal(t, "keboola", configuration.Parameters.Config.Username)
+	assert.Nil(t, configuration.Parameters.API.Pagination, "Jobs without pagination should not need any scrollers")
+	assert.Equal(t, "KBC::ProjectSecure::existing", configuration.Parameters.Config.EncryptedPassword)
+}
+
+func TestGenericExtractorAuthenticationRequiresCredentials(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKeboolaGenericExtractor().Schema, map[string]interface{}{
+		"name":     "extractor",
+		"base_url": "https://api.example.com/v1/",
+		"authentication": []interface{}{
+			map[string]interface{}{"type": "oauth"},
+		},
+		"job": []interface{}{
+			map[string]interface{}{"endpoint": "orders"},
+		},
+	})
+
+	_, err := mapGenericExtractorConfiguration(d, nil, nil)
+
+	assert.EqualError(t, err, "oauth authentication needs the oauth_credentials_id of the authorized credentials")
+}
+
+func TestGenericExtractorReadsOAuthAuthorization(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "1234",
+			"name": "extractor",
+			"configuration": {
+				"parameters": {
+					"api": { "baseUrl": "https://api.example.com/v1/", "authentication": { "type": "oauth20" } },
+					"config": { "jobs": [ { "endpoint": "orders" } ] }
+				},
+				"authorization": { "oauth_api": { "id": "5678" } }
+			}
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaGenericExtractor().Schema, map[string]interface{}{})
+	d.SetId("1234")
+
+	err := resourceKeboolaGenericExtractorRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "oauth", d.Get("authentication.0.type"))
+	assert.Equal(t, "5678", d.Get("authentication.0.oauth_credentials_id"))
+	assert.Equal(t, "orders", d.Get("job.0.endpoint"))
+}
+
+func testAccCheckGenericExtractorDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_generic_extractor" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/ex-generic-v2/configs/%s", rs.Primary.ID))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Generic extractor still exists")
+		}
+	}
+
+	return nil
+}
+
+const testGenericExtractorBasic = `
+	resource "keboola_generic_extractor" "test_extractor" {
+		name = "test_generic_extractor"
+		description = "test description"
+		base_url = "https://api.example.com/v1/"
+		output_bucket = "test-generic"
+
+		authentication {
+			type = "api_key"
+			api_key = "secret"
+			api_key_name = "apiKey"
+		}
+
+		job {
+			endpoint = "orders"
+			data_field = "items"
+		}
+	}`
+
+const testGenericExtractorUpdate = `
+	resource "keboola_generic_extractor" "test_extractor" {
+		name = "test_generic_extractor"
+		description = "test description"
+		base_url = "https://api.example.com/v1/"
+		output_bucket = "test-generic"
+
+		authentication {
+			type = "basic"
+			username = "keboola"
+			password = "secret"
+		}
+
+		job {
+			endpoint = "orders"
+			data_field = "items"
+		}
+
+		job {
+			endpoint = "customers"
+			data_field = "items"
+
+			params {
+				"active" = "1"
+			}
+
+			pagination {
+				method = "pagenum"
+				limit = 50
+				page_param = "page"
+			}
+		}
+
+		mappings = <<EOF
+		{ "customers": { "id": { "type": "column", "mapping": { "destination": "id" }, "primaryKey": true } } }
+		EOF
+	}`
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -22,9 +21,10 @@ type CreateGoodDataProject struct {
 }
 
 type GoodDataWriter struct {
-	ID          string `json:"id,omitempty"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID            string                      `json:"id,omitempty"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	Configuration GoodDataWriterConfiguration `json:"configuration"`
 }
 
 //endregion
@@ -39,6 +39,10 @@ func resourceKeboolaGoodDataWriter() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+
 		DeprecationMessage: "keboola_gooddata_writer has been deprecated and should be replaced with keboola_gooddata_writer_v3",
 
 		Schema: map[string]*schema.Schema{
@@ -59,6 +63,10 @@ func resourceKeboolaGoodDataWriter() *schema.Resource {
 				Optional: true,
 				Default:  "keboola_demo",
 			},
+			"pid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -69,12 +77,14 @@ func resourceKeboolaGoodDataWriterCreate(d *schema.ResourceData, meta interface{
 	writerID := d.Get("writer_id").(string)
 	client := meta.(*KBCClient)
 
-	err := provisionGoodDataProject(writerID, d.Get("description").(string), d.Get("auth_token").(string), client)
+	pid, err := provisionGoodDataProject(writerID, d.Get("description").(string), d.Get("auth_token").(string), client, d.Timeout(schema.TimeoutCreate))
 
 	if err != nil {
 		return err
 	}
 
+	d.Set("pid", pid)
+
 	createdConfigID, err := createGoodDataWriterConfiguration(writerID, d.Get("name").(string), d.Get("description").(string), client)
 
 	if err != nil {
@@ -86,7 +96,7 @@ func resourceKeboolaGoodDataWriterCreate(d *schema.ResourceData, meta interface{
 	return resourceKeboolaGoodDataWriterRead(d, meta)
 }
 
-func provisionGoodDataProject(writerID string, description string, authToken string, client *KBCClient) error {
+func provisionGoodDataProject(writerID string, description string, authToken string, client *KBCClient, timeout time.Duration) (string, error) {
 	createProject := CreateGoodDataProject{
 		WriterID:    writerID,
 		Description: description,
@@ -95,51 +105,34 @@ func provisionGoodDataProject(writerID string, description string, authToken str
 
 	createJSON, err := json.Marshal(createProject)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	createBuffer := bytes.NewBuffer(createJSON)
 	createWriterResp, err := client.PostToSyrup("gooddata-writer/v2", createBuffer)
 
 	if hasErrors(err, createWriterResp) {
-		return extractError(err, createWriterResp)
+		return "", extractError(err, createWriterResp)
 	}
 
-	createWriterStatus := "waiting"
-	var createWriterStatusRes StorageJobStatus
+	var createWriterStatusRes SyrupJobStatus
 
 	createWriterDecoder := json.NewDecoder(createWriterResp.Body)
 	err = createWriterDecoder.Decode(&createWriterStatusRes)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	jobURL, err := url.Parse(createWriterStatusRes.URL)
+	jobStatus, err := client.WaitForSyrupJob(client.stopContext(), createWriterStatusRes.URL, timeout)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	for createWriterStatus != "success" && createWriterStatus != "error" {
-		jobStatusResp, jobErr := client.GetFromSyrup(strings.TrimLeft(jobURL.Path, "/"))
-
-		if hasErrors(jobErr, jobStatusResp) {
-			return extractError(jobErr, jobStatusResp)
-		}
-
-		decoder := json.NewDecoder(jobStatusResp.Body)
-		err = decoder.Decode(&createWriterStatusRes)
-
-		if err != nil {
-			return err
-		}
+	pid, _ := jobStatus.Result["pid"].(string)
 
-		time.Sleep(250 * time.Millisecond)
-		createWriterStatus = createWriterStatusRes.Status
-	}
-
-	return nil
+	return pid, nil
 }
 
 func createGoodDataWriterConfiguration(writerID string, name string, description string, client *KBCClient) (createdID string, err error) {
@@ -203,6 +196,11 @@ func resourceKeboolaGoodDataWriterRead(d *schema.ResourceData, meta interface{})
 	d.Set("name", goodDataWriter.Name)
 	d.Set("description", goodDataWriter.Description)
 
+	//the project is kept from provisioning until the writer has saved it to its configuration
+	if pid := goodDataWriter.Configuration.Parameters.Project.ProjectId; pid != "" {
+		d.Set("pid", pid)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,41 @@
+package keboola
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoodDataWriterReadReadsProjectID(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/components/gooddata-writer/configs/writer1", r.URL.Path)
+		w.Write([]byte(`{ "id": "writer1", "name": "GoodData", "configuration": { "parameters": { "project": { "pid": "abc123def" } } } }`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaGoodDataWriter().Data(&terraform.InstanceState{ID: "writer1"})
+
+	err := resourceKeboolaGoodDataWriterRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123def", d.Get("pid"), "The project should be read back, e.g. when the writer is imported")
+}
+
+func TestGoodDataWriterReadKeepsProvisionedProjectID(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "writer1", "name": "GoodData", "configuration": {} }`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaGoodDataWriter().Data(&terraform.InstanceState{
+		ID:         "writer1",
+		Attributes: map[string]string{"pid": "abc123def"},
+	})
+
+	err := resourceKeboolaGoodDataWriterRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123def", d.Get("pid"))
+}
@@ -0,0 +1,193 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//LinkedBucket is the data model for a bucket linked from a bucket shared by another project
+//within the Keboola Storage API.
+type LinkedBucket struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	Backend      string `json:"backend"`
+	SourceBucket *struct {
+		ID      string `json:"id"`
+		Project struct {
+			ID json.Number `json:"id"`
+		} `json:"project"`
+	} `json:"sourceBucket"`
+}
+
+//endregion
+
+func resourceKeboolaLinkedBucket() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaLinkedBucketCreate,
+		Read:   resourceKeboolaLinkedBucketRead,
+		Delete: resourceKeboolaLinkedBucketDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"stage": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateStorageBucketStage,
+			},
+			"source_project_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"is_linked": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"backend": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKeboolaLinkedBucketCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Linking Storage Bucket in Keboola.")
+
+	linkBucketForm := url.Values{}
+	linkBucketForm.Add("name", d.Get("name").(string))
+	linkBucketForm.Add("stage", d.Get("stage").(string))
+	linkBucketForm.Add("sourceProjectId", d.Get("source_project_id").(string))
+	linkBucketForm.Add("sourceBucketId", d.Get("source_bucket_id").(string))
+
+	linkBucketBuffer := buffer.FromForm(linkBucketForm)
+
+	client := meta.(*KBCClient)
+	linkResponse, err := client.PostToStorage("storage/buckets", linkBucketBuffer)
+
+	if hasErrors(err, linkResponse) {
+		return extractError(err, linkResponse)
+	}
+
+	//linking is processed asynchronously by some stacks, in which case the bucket is the result of the job
+	if linkResponse.StatusCode == http.StatusAccepted {
+		var linkJob StorageJobStatus
+
+		if err := json.NewDecoder(linkResponse.Body).Decode(&linkJob); err != nil {
+			return err
+		}
+
+		linkJobStatus, err := client.WaitForStorageJob(client.stopContext(), linkJob.ID, d.Timeout(schema.TimeoutCreate))
+
+		if err != nil {
+			return err
+		}
+
+		d.SetId(string(linkJobStatus.Results.ID))
+
+		return resourceKeboolaLinkedBucketRead(d, meta)
+	}
+
+	var linkedBucket LinkedBucket
+
+	decoder := json.NewDecoder(linkResponse.Body)
+	err = decoder.Decode(&linkedBucket)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(linkedBucket.ID)
+
+	return resourceKeboolaLinkedBucketRead(d, meta)
+}
+
+func resourceKeboolaLinkedBucketRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Linked Storage Bucket from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var linkedBucket LinkedBucket
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&linkedBucket)
+
+	if err != nil {
+		return err
+	}
+
+	//a bucket whose source is no longer shared with the project is no longer linked, and so is
+	//removed from state to be linked again (which reports whether the bucket is still shared)
+	if linkedBucket.SourceBucket == nil {
+		log.Printf("[WARN] Storage Bucket %s is no longer linked to %s, as the source bucket is no longer shared, removing from state.", d.Id(), d.Get("source_bucket_id").(string))
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", strings.TrimPrefix(linkedBucket.Name, "c-"))
+	d.Set("stage", linkedBucket.Stage)
+	d.Set("source_project_id", linkedBucket.SourceBucket.Project.ID.String())
+	d.Set("source_bucket_id", linkedBucket.SourceBucket.ID)
+	d.Set("is_linked", true)
+	d.Set("backend", linkedBucket.Backend)
+
+	return nil
+}
+
+func resourceKeboolaLinkedBucketDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Unlinking Storage Bucket in Keboola: %s", d.Id())
+
+	//forcing the delete removes the tables of the linked bucket, which are only aliases of the shared tables
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/buckets/%s?force=1", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package keboola
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestAccLinkedBucket_Basic links a bucket shared by another project in the same organization, given
+//by the KBC_SOURCE_PROJECT_ID and KBC_SOURCE_BUCKET_ID environment variables.
+func TestAccLinkedBucket_Basic(t *testing.T) {
+	sourceProjectID := os.Getenv("KBC_SOURCE_PROJECT_ID")
+	sourceBucketID := os.Getenv("KBC_SOURCE_BUCKET_ID")
+
+	if sourceProjectID == "" || sourceBucketID == "" {
+		t.Skip("KBC_SOURCE_PROJECT_ID and KBC_SOURCE_BUCKET_ID must be set to link a shared bucket")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testLinkedBucketBasic, sourceProjectID, sourceBucketID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_linked_bucket.test_linked_bucket", "is_linked", "true"),
+					resource.TestCheckResourceAttr("keboola_linked_bucket.test_linked_bucket", "source_bucket_id", sourceBucketID),
+				),
+			},
+			{
+				ResourceName:      "keboola_linked_bucket.test_linked_bucket",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestLinkedBucketCreateLinksSourceBucket(t *testing.T) {
+	var linkBucketForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/buckets":
+			r.ParseForm()
+			linkBucketForm = r.PostForm
+			w.Write([]byte(`{ "id": "in.c-shared-curated" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/buckets/in.c-shared-curated":
+			w.Write([]byte(`{
+				"id": "in.c-shared-curated",
+				"name": "c-shared-curated",
+				"stage": "in",
+				"backend": "snowflake",
+				"sourceBucket": { "id": "out.c-curated", "project": { "id": 123 } }
+			}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaLinkedBucket().Schema, map[string]interface{}{
+		"name":              "shared-curated",
+		"stage":             "in",
+		"source_project_id": "123",
+		"source_bucket_id":  "out.c-curated",
+	})
+
+	err := resourceKeboolaLinkedBucketCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "123", linkBucketForm.Get("sourceProjectId"))
+	assert.Equal(t, "out.c-curated", linkBucketForm.Get("sourceBucketId"))
+	assert.Equal(t, "in.c-shared-curated", d.Id())
+	assert.Equal(t, "shared-curated", d.Get("name"))
+	assert.Equal(t, true, d.Get("is_linked"))
+}
+
+func TestLinkedBucketReadDetectsRevokedShare(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "in.c-shared-curated", "name": "c-shared-curated", "stage": "in" }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaLinkedBucket().Schema, map[string]interface{}{
+		"source_bucket_id": "out.c-curated",
+	})
+	d.SetId("in.c-shared-curated")
+
+	err := resourceKeboolaLinkedBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A bucket whose source is no longer shared should be removed from state")
+}
+
+const testLinkedBucketBasic = `
+	resource "keboola_linked_bucket" "test_linked_bucket" {
+		name = "test_linked_bucket"
+		stage = "in"
+		source_project_id = "%s"
+		source_bucket_id = "%s"
+	}`
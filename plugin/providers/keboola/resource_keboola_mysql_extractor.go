@@ -0,0 +1,581 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//MySQLExtractor is the data model for MySQL Extractors within
+//the Keboola Storage API.
+type MySQLExtractor struct {
+	ID            string                      `json:"id,omitempty"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	Configuration MySQLExtractorConfiguration `json:"configuration"`
+}
+
+type MySQLExtractorConfiguration struct {
+	Parameters MySQLExtractorParameters `json:"parameters"`
+}
+
+type MySQLExtractorParameters struct {
+	Database MySQLDatabaseParameters `json:"db"`
+	Tables   []MySQLExtractorTable   `json:"tables"`
+}
+
+type MySQLDatabaseParameters struct {
+	Host              string          `json:"host"`
+	Port              json.Number     `json:"port"`
+	Database          string          `json:"database"`
+	User              string          `json:"user"`
+	EncryptedPassword string          `json:"#password,omitempty"`
+	SSH               *MySQLSSHTunnel `json:"ssh,omitempty"`
+}
+
+type MySQLSSHTunnel struct {
+	Enabled bool        `json:"enabled"`
+	SSHHost string      `json:"sshHost"`
+	SSHPort json.Number `json:"sshPort"`
+	User    string      `json:"user"`
+	Keys    struct {
+		EncryptedPrivateKey string `json:"#private"`
+		PublicKey           string `json:"public,omitempty"`
+	} `json:"keys"`
+}
+
+type MySQLExtractorTable struct {
+	ID                        int                       `json:"id"`
+	Name                      string                    `json:"name"`
+	Enabled                   bool                      `json:"enabled"`
+	Incremental               bool                      `json:"incremental"`
+	IncrementalFetchingColumn string                    `json:"incrementalFetchingColumn,omitempty"`
+	IncrementalFetchingLimit  int                       `json:"incrementalFetchingLimit,omitempty"`
+	OutputTable               string                    `json:"outputTable"`
+	PrimaryKey                []string                  `json:"primaryKey,omitempty"`
+	Query                     string                    `json:"query,omitempty"`
+	InputTable                *MySQLExtractorInputTable `json:"table,omitempty"`
+	Columns                   []string                  `json:"columns,omitempty"`
+}
+
+type MySQLExtractorInputTable struct {
+	Schema    string `json:"schema"`
+	TableName string `json:"tableName"`
+}
+
+//endregion
+
+//mySQLExtractorSecrets are the secrets of a MySQL Extractor, which are kept in state as the ciphertext Keboola holds for them.
+var mySQLExtractorSecrets = []secretAttribute{
+	{key: "db_parameters.0.password", required: true},
+	{key: "db_parameters.0.ssh_tunnel.0.private_key", required: true},
+}
+
+func resourceKeboolaMySQLExtractor() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaMySQLExtractorCreate,
+		Read:   resourceKeboolaMySQLExtractorRead,
+		Update: resourceKeboolaMySQLExtractorUpdate,
+		Delete: resourceKeboolaMySQLExtractorDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceKeboolaMySQLExtractorCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"db_parameters": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  3306,
+						},
+						"database": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"user": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"ssh_tunnel": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"host": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"port": {
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  22,
+									},
+									"user": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"private_key": {
+										Type:      schema.TypeString,
+										Optional:  true,
+										Computed:  true,
+										Sensitive: true,
+									},
+									"public_key": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"table": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"incremental": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"incremental_fetching_column": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"incremental_fetching_limit": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"output_table": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"primary_key": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"query": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"schema": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"table_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"columns": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"secret_hashes": &secretHashesSchema,
+		},
+	}
+}
+
+//resourceKeboolaMySQLExtractorCustomizeDiff only plans a change to a secret when it differs from the one which
+//was encrypted as the ciphertext in state.
+func resourceKeboolaMySQLExtractorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeSecretsDiff(d, meta, mySQLExtractorSecrets)
+}
+
+func resourceKeboolaMySQLExtractorCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating MySQL Extractor in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	createExtractorForm := url.Values{}
+	createExtractorForm.Add("name", d.Get("name").(string))
+	createExtractorForm.Add("description", d.Get("description").(string))
+
+	createExtractorBuffer := buffer.FromForm(createExtractorForm)
+
+	createResponse, err := client.PostToStorage("storage/components/keboola.ex-db-mysql/configs", createExtractorBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	parameters, err := mapMySQLExtractorParameters(d, client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateMySQLExtractorConfiguration(d, parameters, "Created MySQL Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, mySQLExtractorSecrets, resourceKeboolaMySQLExtractorRead)
+}
+
+//mapMySQLExtractorParameters builds the extractor configuration from the resource. Secrets are
+//encrypted for the extractor before being sent; those which have not changed are taken from the
+//existing configuration instead, as only their ciphertext is kept in state. Tables keep the IDs
+//they have in the existing configuration.
+func mapMySQLExtractorParameters(d *schema.ResourceData, client *KBCClient, existing *MySQLExtractorParameters) (MySQLExtractorParameters, error) {
+	dbParameters := d.Get("db_parameters.0").(map[string]interface{})
+
+	database := MySQLDatabaseParameters{
+		Host:     dbParameters["host"].(string),
+		Port:     json.Number(fmt.Sprint(dbParameters["port"].(int))),
+		Database: dbParameters["database"].(string),
+		User:     dbParameters["user"].(string),
+	}
+
+	if existing != nil && !d.HasChange("db_parameters.0.password") {
+		database.EncryptedPassword = existing.Database.EncryptedPassword
+	} else {
+		encryptedPassword, err := client.EncryptValue("keboola.ex-db-mysql", dbParameters["password"].(string))
+
+		if err != nil {
+			return MySQLExtractorParameters{}, fmt.Errorf("Unable to encrypt MySQL password: %v", err)
+		}
+
+		database.EncryptedPassword = encryptedPassword
+	}
+
+	if sshTunnels := dbParameters["ssh_tunnel"].([]interface{}); len(sshTunnels) > 0 {
+		sshTunnel := sshTunnels[0].(map[string]interface{})
+
+		database.SSH = &MySQLSSHTunnel{
+			Enabled: true,
+			SSHHost: sshTunnel["host"].(string),
+			SSHPort: json.Number(fmt.Sprint(sshTunnel["port"].(int))),
+			User:    sshTunnel["user"].(string),
+		}
+
+		database.SSH.Keys.PublicKey = sshTunnel["public_key"].(string)
+
+		if existing != nil && existing.Database.SSH != nil && !d.HasChange("db_parameters.0.ssh_tunnel.0.private_key") {
+			database.SSH.Keys.EncryptedPrivateKey = existing.Database.SSH.Keys.EncryptedPrivateKey
+		} else {
+			encryptedPrivateKey, err := client.EncryptValue("keboola.ex-db-mysql", sshTunnel["private_key"].(string))
+
+			if err != nil {
+				return MySQLExtractorParameters{}, fmt.Errorf("Unable to encrypt SSH private key: %v", err)
+			}
+
+			database.SSH.Keys.EncryptedPrivateKey = encryptedPrivateKey
+		}
+	}
+
+	var existingTables []MySQLExtractorTable
+	if existing != nil {
+		existingTables = existing.Tables
+	}
+
+	tables, err := mapMySQLExtractorTablesToModel(d.Get("table").([]interface{}), existingTables)
+
+	if err != nil {
+		return MySQLExtractorParameters{}, err
+	}
+
+	return MySQLExtractorParameters{
+		Database: database,
+		Tables:   tables,
+	}, nil
+}
+
+func mapMySQLExtractorTablesToModel(tables []interface{}, existingTables []MySQLExtractorTable) ([]MySQLExtractorTable, error) {
+	existingIDs := make(map[string]int)
+	distinctIDs := make(map[int]bool)
+
+	for _, existingTable := range existingTables {
+		existingIDs[existingTable.Name] = existingTable.ID
+		distinctIDs[existingTable.ID] = true
+	}
+
+	extractorTables := make([]MySQLExtractorTable, 0, len(tables))
+	distinctNames := make(map[string]bool)
+
+	for _, table := range tables {
+		config := table.(map[string]interface{})
+
+		extractorTable := MySQLExtractorTable{
+			Name:                      config["name"].(string),
+			Enabled:                   config["enabled"].(bool),
+			Incremental:               config["incremental"].(bool),
+			IncrementalFetchingColumn: config["incremental_fetching_column"].(string),
+			IncrementalFetchingLimit:  config["incremental_fetching_limit"].(int),
+			OutputTable:               config["output_table"].(string),
+			PrimaryKey:                AsStringArray(config["primary_key"].([]interface{})),
+		}
+
+		if distinctNames[extractorTable.Name] {
+			return nil, fmt.Errorf("table with name already exists: %s", extractorTable.Name)
+		}
+
+		distinctNames[extractorTable.Name] = true
+
+		if id, ok := existingIDs[extractorTable.Name]; ok {
+			extractorTable.ID = id
+		} else {
+			extractorTable.ID = generateExtractorTableID(distinctIDs)
+			distinctIDs[extractorTable.ID] = true
+		}
+
+		if query := config["query"].(string); query != "" {
+			extractorTable.Query = query
+		} else {
+			schemaName := config["schema"].(string)
+			tableName := config["table_name"].(string)
+
+			if schemaName == "" || tableName == "" {
+				return nil, fmt.Errorf("table %s must have either a query, or both a schema and a table_name", extractorTable.Name)
+			}
+
+			extractorTable.InputTable = &MySQLExtractorInputTable{
+				Schema:    schemaName,
+				TableName: tableName,
+			}
+
+			extractorTable.Columns = AsStringArray(config["columns"].([]interface{}))
+		}
+
+		extractorTables = append(extractorTables, extractorTable)
+	}
+
+	return extractorTables, nil
+}
+
+func mapMySQLExtractorTablesToSchema(extractorTables []MySQLExtractorTable) []map[string]interface{} {
+	tables := make([]map[string]interface{}, 0, len(extractorTables))
+
+	for _, extractorTable := range extractorTables {
+		tableDetails := map[string]interface{}{
+			"id":                          extractorTable.ID,
+			"name":                        extractorTable.Name,
+			"enabled":                     extractorTable.Enabled,
+			"incremental":                 extractorTable.Incremental,
+			"incremental_fetching_column": extractorTable.IncrementalFetchingColumn,
+			"incremental_fetching_limit":  extractorTable.IncrementalFetchingLimit,
+			"output_table":                extractorTable.OutputTable,
+			"primary_key":                 extractorTable.PrimaryKey,
+		}
+
+		if extractorTable.Query != "" {
+			tableDetails["query"] = extractorTable.Query
+		} else if extractorTable.InputTable != nil {
+			tableDetails["schema"] = extractorTable.InputTable.Schema
+			tableDetails["table_name"] = extractorTable.InputTable.TableName
+			tableDetails["columns"] = extractorTable.Columns
+		}
+
+		tables = append(tables, tableDetails)
+	}
+
+	return tables
+}
+
+func updateMySQLExtractorConfiguration(d *schema.ResourceData, parameters MySQLExtractorParameters, changeDescription string, client *KBCClient) error {
+	mySQLConfigJSON, err := json.Marshal(MySQLExtractorConfiguration{Parameters: parameters})
+
+	if err != nil {
+		return err
+	}
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", string(mySQLConfigJSON))
+	updateConfigurationForm.Add("changeDescription", changeDescription)
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	updateConfigurationResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/keboola.ex-db-mysql/configs/%s", d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateConfigurationResponse) {
+		return extractError(err, updateConfigurationResponse)
+	}
+
+	return nil
+}
+
+func getMySQLExtractor(id string, client *KBCClient) (*MySQLExtractor, int, error) {
+	getExtractorResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.ex-db-mysql/configs/%s", id))
+
+	if hasErrors(err, getExtractorResponse) {
+		if err == nil {
+			return nil, getExtractorResponse.StatusCode, extractError(err, getExtractorResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var mySQLExtractor MySQLExtractor
+
+	decoder := json.NewDecoder(getExtractorResponse.Body)
+	err = decoder.Decode(&mySQLExtractor)
+
+	if err != nil {
+		return nil, getExtractorResponse.StatusCode, err
+	}
+
+	return &mySQLExtractor, getExtractorResponse.StatusCode, nil
+}
+
+func resourceKeboolaMySQLExtractorRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading MySQL Extractor from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	mySQLExtractor, statusCode, err := getMySQLExtractor(d.Id(), client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", mySQLExtractor.Name)
+	d.Set("description", mySQLExtractor.Description)
+
+	database := mySQLExtractor.Configuration.Parameters.Database
+	port, _ := database.Port.Int64()
+
+	//Keboola only returns the encrypted secrets, which are kept in state in place of the secrets themselves.
+	dbParameters := map[string]interface{}{
+		"host":     database.Host,
+		"port":     int(port),
+		"database": database.Database,
+		"user":     database.User,
+		"password": database.EncryptedPassword,
+	}
+
+	if database.SSH != nil && database.SSH.Enabled {
+		sshPort, _ := database.SSH.SSHPort.Int64()
+
+		dbParameters["ssh_tunnel"] = []map[string]interface{}{
+			{
+				"host":        database.SSH.SSHHost,
+				"port":        int(sshPort),
+				"user":        database.SSH.User,
+				"private_key": database.SSH.Keys.EncryptedPrivateKey,
+				"public_key":  database.SSH.Keys.PublicKey,
+			},
+		}
+	}
+
+	d.Set("db_parameters", []map[string]interface{}{dbParameters})
+	d.Set("table", mapMySQLExtractorTablesToSchema(mySQLExtractor.Configuration.Parameters.Tables))
+
+	return nil
+}
+
+func resourceKeboolaMySQLExtractorUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating MySQL Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	mySQLExtractor, _, err := getMySQLExtractor(d.Id(), client)
+
+	if err != nil {
+		return err
+	}
+
+	parameters, err := mapMySQLExtractorParameters(d, client, &mySQLExtractor.Configuration.Parameters)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateMySQLExtractorConfiguration(d, parameters, "Updated MySQL Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, mySQLExtractorSecrets, resourceKeboolaMySQLExtractorRead)
+}
+
+func resourceKeboolaMySQLExtractorDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting MySQL Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/keboola.ex-db-mysql/configs/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
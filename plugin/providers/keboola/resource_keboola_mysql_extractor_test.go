@@ -0,0 +1,246 @@
+package keboola
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccMySQLExtractor_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMySQLExtractorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testMySQLExtractorBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "name", "test_mysql_extractor"),
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "db_parameters.0.host", "mysql.example.com"),
+					resource.TestMatchResourceAttr("keboola_mysql_extractor.test_extractor", "db_parameters.0.password", regexp.MustCompile("^KBC::")),
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "table.#", "1"),
+				),
+			},
+			{
+				Config: testMySQLExtractorUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "db_parameters.0.ssh_tunnel.0.host", "bastion.example.com"),
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "table.#", "2"),
+					resource.TestCheckResourceAttr("keboola_mysql_extractor.test_extractor", "table.1.incremental_fetching_column", "updated_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestMySQLExtractorCreateEncryptsSecrets(t *testing.T) {
+	var savedConfiguration string
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "encryption.example.com":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write([]byte("KBC::ProjectSecure::" + string(body)))
+		case r.URL.Path == "/v2/storage/tokens/verify":
+			w.Write([]byte(`{ "owner": { "id": 567 } }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs":
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234":
+			r.ParseForm()
+			savedConfiguration = r.PostForm.Get("configuration")
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.ex-db-mysql/configs/1234":
+			w.Write([]byte(fmt.Sprintf(`{ "id": "1234", "name": "extractor", "configuration": %s }`, savedConfiguration)))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaMySQLExtractor().Schema, map[string]interface{}{
+		"name": "extractor",
+		"db_parameters": []interface{}{
+			map[string]interface{}{
+				"host":     "mysql.example.com",
+				"database": "shop",
+				"user":     "keboola",
+				"password": "secret",
+				"ssh_tunnel": []interface{}{
+					map[string]interface{}{
+						"host":        "bastion.example.com",
+						"user":        "tunnel",
+						"private_key": "private-key",
+					},
+				},
+			},
+		},
+		"table": []interface{}{
+			map[string]interface{}{
+				"name":         "orders",
+				"output_table": "in.c-mysql.orders",
+				"query":        "SELECT * FROM orders",
+			},
+		},
+	})
+
+	err := resourceKeboolaMySQLExtractorCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Contains(t, savedConfiguration, `"#password":"KBC::ProjectSecure::secret"`)
+	assert.Contains(t, savedConfiguration, `"#private":"KBC::ProjectSecure::private-key"`)
+	assert.Contains(t, savedConfiguration, `"port":3306`)
+
+	assert.Equal(t, "KBC::ProjectSecure::secret", d.Get("db_parameters.0.password"), "Only the ciphertext of the password should be kept in state")
+	assert.Equal(t, "KBC::ProjectSecure::private-key", d.Get("db_parameters.0.ssh_tunnel.0.private_key"), "Only the ciphertext of the private key should be kept in state")
+	assert.Equal(t, client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::secret", "secret"), d.Get("secret_hashes.db_parameters_0_password"))
+	assert.Equal(t, client.secretHash("1234", "db_parameters.0.ssh_tunnel.0.private_key", "KBC::ProjectSecure::private-key", "private-key"), d.Get("secret_hashes.db_parameters_0_ssh_tunnel_0_private_key"))
+	assert.Equal(t, "bastion.example.com", d.Get("db_parameters.0.ssh_tunnel.0.host"))
+	assert.Equal(t, "SELECT * FROM orders", d.Get("table.0.query"))
+}
+
+func TestMySQLExtractorTablesKeepExistingIDs(t *testing.T) {
+	tables := []interface{}{
+		map[string]interface{}{
+			"name":                        "orders",
+			"enabled":                     true,
+			"incremental":                 true,
+			"incremental_fetching_column": "updated_at",
+			"incremental_fetching_limit":  1000,
+			"output_table":                "in.c-mysql.orders",
+			"primary_key":                 []interface{}{"id"},
+			"query":                       "",
+			"schema":                      "shop",
+			"table_name":                  "orders",
+			"columns":                     []interface{}{},
+		},
+		map[string]interface{}{
+			"name":                        "customers",
+			"enabled":                     true,
+			"incremental":                 false,
+			"incremental_fetching_column": "",
+			"incremental_fetching_limit":  0,
+			"output_table":                "in.c-mysql.customers",
+			"primary_key":                 []interface{}{},
+			"query":                       "SELECT id, name FROM customers",
+			"schema":                      "",
+			"table_name":                  "",
+			"columns":                     []interface{}{},
+		},
+	}
+
+	result, err := mapMySQLExtractorTablesToModel(tables, []MySQLExtractorTable{{ID: 42, Name: "orders"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result[0].ID, "Existing tables should keep their IDs")
+	assert.NotEqual(t, 42, result[1].ID, "New tables should not reuse the IDs of existing tables")
+	assert.Equal(t, &MySQLExtractorInputTable{Schema: "shop", TableName: "orders"}, result[0].InputTable)
+	assert.Equal(t, "updated_at", result[0].IncrementalFetchingColumn)
+	assert.Nil(t, result[1].InputTable)
+
+	assert.Equal(t, tables[0].(map[string]interface{})["output_table"], mapMySQLExtractorTablesToSchema(result)[0]["output_table"])
+}
+
+func TestMySQLExtractorTablesRequireSource(t *testing.T) {
+	tables := []interface{}{
+		map[string]interface{}{
+			"name":                        "orders",
+			"enabled":                     true,
+			"incremental":                 false,
+			"incremental_fetching_column": "",
+			"incremental_fetching_limit":  0,
+			"output_table":                "in.c-mysql.orders",
+			"primary_key":                 []interface{}{},
+			"query":                       "",
+			"schema":                      "shop",
+			"table_name":                  "",
+			"columns":                     []interface{}{},
+		},
+	}
+
+	_, err := mapMySQLExtractorTablesToModel(tables, nil)
+
+	assert.EqualError(t, err, "table orders must have either a query, or both a schema and a table_name")
+}
+
+func testAccCheckMySQLExtractorDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_mysql_extractor" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.ex-db-mysql/configs/%s", url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("MySQL extractor still exists")
+		}
+	}
+
+	return nil
+}
+
+const testMySQLExtractorBasic = `
+	resource "keboola_mysql_extractor" "test_extractor" {
+		name = "test_mysql_extractor"
+		description = "test description"
+
+		db_parameters {
+			host = "mysql.example.com"
+			database = "shop"
+			user = "keboola"
+			password = "secret"
+		}
+
+		table {
+			name = "orders"
+			output_table = "in.c-mysql.orders"
+			schema = "shop"
+			table_name = "orders"
+			primary_key = [ "id" ]
+		}
+	}`
+
+const testMySQLExtractorUpdate = `
+	resource "keboola_mysql_extractor" "test_extractor" {
+		name = "test_mysql_extractor"
+		description = "test description"
+
+		db_parameters {
+			host = "mysql.example.com"
+			database = "shop"
+			user = "keboola"
+			password = "secret"
+
+			ssh_tunnel {
+				host = "bastion.example.com"
+				user = "tunnel"
+				private_key = "not-a-real-private-key"
+			}
+		}
+
+		table {
+			name = "orders"
+			output_table = "in.c-mysql.orders"
+			schema = "shop"
+			table_name = "orders"
+			primary_key = [ "id" ]
+		}
+
+		table {
+			name = "order_items"
+			output_table = "in.c-mysql.order_items"
+			schema = "shop"
+			table_name = "order_items"
+			incremental = true
+			incremental_fetching_column = "updated_at"
+		}
+	}`
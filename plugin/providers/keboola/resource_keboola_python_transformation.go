@@ -0,0 +1,111 @@
+package keboola
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var pythonTransformation = codeTransformationComponent{id: "keboola.python-transformation-v2"}
+
+func resourceKeboolaPythonTransformation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaPythonTransformationCreate,
+		Read:   resourceKeboolaPythonTransformationRead,
+		Update: resourceKeboolaPythonTransformationUpdate,
+		Delete: resourceKeboolaPythonTransformationDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: pythonTransformation.customizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"script": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"script_file"},
+			},
+			//the script is read from the file whenever the transformation is planned, and is updated when its contents change
+			"script_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateFileExists,
+				ConflictsWith: []string{"script"},
+			},
+			"script_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			//the pip packages installed before the script is run
+			"packages": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"backend_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCodeTransformationBackendSize,
+			},
+			"input":  &codeTransformationInputSchema,
+			"output": &outputSchema,
+		},
+	}
+}
+
+func resourceKeboolaPythonTransformationCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Python Transformation in Keboola.")
+
+	client := meta.(*KBCClient)
+	err := pythonTransformation.create(d, client)
+
+	if err != nil {
+		return err
+	}
+
+	return resourceKeboolaPythonTransformationRead(d, meta)
+}
+
+func resourceKeboolaPythonTransformationRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Python Transformation from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+
+	return pythonTransformation.read(d, client)
+}
+
+func resourceKeboolaPythonTransformationUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Python Transformation in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	err := pythonTransformation.update(d, client, "Updated Python Transformation configuration via Terraform")
+
+	if err != nil {
+		return err
+	}
+
+	return resourceKeboolaPythonTransformationRead(d, meta)
+}
+
+func resourceKeboolaPythonTransformationDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Python Transformation in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	return pythonTransformation.delete(d, client)
+}
@@ -0,0 +1,229 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccPythonTransformation_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPythonTransformationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPythonTransformationBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "name", "test_python_transformation"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "script", "print('hello')\n"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "packages.0", "pandas"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "input.0.destination", "orders.csv"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "output.0.destination", "out.c-python.orders"),
+				),
+			},
+			{
+				Config: testPythonTransformationUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "script", "print('updated')\n"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "backend_size", "medium"),
+					resource.TestCheckResourceAttr("keboola_python_transformation.test_transformation", "output.0.incremental", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestPythonTransformationCreateSavesConfiguration(t *testing.T) {
+	var createTransformationForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.python-transformation-v2/configs":
+			r.ParseForm()
+			createTransformationForm = r.PostForm
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.python-transformation-v2/configs/1234":
+			w.Write([]byte(`{ "id": "1234", "name": "orders", "configuration": ` + createTransformationForm.Get("configuration") + ` }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaPythonTransformation().Schema, map[string]interface{}{
+		"name":         "orders",
+		"script":       "import pandas\n",
+		"packages":     []interface{}{"pandas"},
+		"backend_size": "large",
+		"input": []interface{}{
+			map[string]interface{}{
+				"source":      "in.c-sales.orders",
+				"destination": "orders.csv",
+				"columns":     []interface{}{"id", "total"},
+			},
+		},
+		"output": []interface{}{
+			map[string]interface{}{
+				"source":      "totals.csv",
+				"destination": "out.c-sales.totals",
+				"primary_key": []interface{}{"id"},
+			},
+		},
+	})
+
+	err := resourceKeboolaPythonTransformationCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", d.Id())
+	assert.Equal(t, "orders", createTransformationForm.Get("name"))
+
+	var configuration CodeTransformationConfiguration
+	json.Unmarshal([]byte(createTransformationForm.Get("configuration")), &configuration)
+
+	assert.Equal(t, []string{"import pandas\n"}, configuration.Parameters.Blocks[0].Codes[0].Script)
+	assert.Equal(t, []string{"pandas"}, configuration.Parameters.Packages)
+	assert.Equal(t, "large", configuration.Runtime.Backend.Type)
+	assert.Equal(t, []string{"id", "total"}, configuration.Storage.Input.Tables[0].Columns)
+	assert.Equal(t, "out.c-sales.totals", configuration.Storage.Output.Tables[0].Destination)
+
+	assert.Equal(t, "import pandas\n", d.Get("script"))
+	assert.Equal(t, pythonTransformation.hashScript("import pandas\n"), d.Get("script_hash"))
+	assert.Equal(t, "orders.csv", d.Get("input.0.destination"))
+	assert.Equal(t, "id", d.Get("output.0.primary_key.0"))
+}
+
+func TestPythonTransformationReadJoinsCodeBlocks(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "1234",
+			"name": "orders",
+			"configuration": {
+				"parameters": {
+					"blocks": [
+						{ "name": "Load", "codes": [ { "name": "Read", "script": [ "import pandas" ] } ] },
+						{ "name": "Save", "codes": [ { "name": "Write", "script": [ "print('done')" ] } ] }
+					]
+				}
+			}
+		}`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaPythonTransformation().Data(&terraform.InstanceState{ID: "1234"})
+
+	err := resourceKeboolaPythonTransformationRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "import pandas\n\nprint('done')", d.Get("script"))
+	assert.Equal(t, "", d.Get("backend_size"))
+}
+
+func TestPythonTransformationReadKeepsScriptFile(t *testing.T) {
+	scriptFile := writeTestDataFile(t, "print('hello')\n")
+	defer os.Remove(scriptFile)
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "1234", "name": "orders", "configuration": { "parameters": { "blocks": [ { "name": "Terraform", "codes": [ { "name": "Script", "script": [ "print('changed')\n" ] } ] } ] } } }`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaPythonTransformation().Data(&terraform.InstanceState{
+		ID: "1234",
+		Attributes: map[string]string{
+			"name":        "orders",
+			"script_file": scriptFile,
+		},
+	})
+
+	err := resourceKeboolaPythonTransformationRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Get("script"), "The script should not be read back when it is loaded from a file")
+	assert.Equal(t, pythonTransformation.hashScript("print('changed')\n"), d.Get("script_hash"))
+
+	script, err := readCodeTransformationScript("", scriptFile)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, pythonTransformation.hashScript(script), d.Get("script_hash"), "A script changed outside of Terraform should differ from the script file")
+}
+
+func TestPythonTransformationReadRemovesDeletedTransformation(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := resourceKeboolaPythonTransformation().Data(&terraform.InstanceState{ID: "1234"})
+
+	err := resourceKeboolaPythonTransformationRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+}
+
+func testAccCheckPythonTransformationDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_python_transformation" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.python-transformation-v2/configs/%s", url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Python transformation still exists")
+		}
+	}
+
+	return nil
+}
+
+const testPythonTransformationBasic = `
+	resource "keboola_python_transformation" "test_transformation" {
+		name = "test_python_transformation"
+		description = "test description"
+		script = "print('hello')\n"
+		packages = [ "pandas" ]
+
+		input {
+			source = "in.c-python.orders"
+			destination = "orders.csv"
+		}
+
+		output {
+			source = "orders.csv"
+			destination = "out.c-python.orders"
+			primary_key = [ "id" ]
+		}
+	}`
+
+const testPythonTransformationUpdate = `
+	resource "keboola_python_transformation" "test_transformation" {
+		name = "test_python_transformation"
+		description = "test description"
+		script = "print('updated')\n"
+		packages = [ "pandas" ]
+		backend_size = "medium"
+
+		input {
+			source = "in.c-python.orders"
+			destination = "orders.csv"
+		}
+
+		output {
+			source = "orders.csv"
+			destination = "out.c-python.orders"
+			primary_key = [ "id" ]
+			incremental = true
+		}
+	}`
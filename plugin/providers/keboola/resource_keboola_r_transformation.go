@@ -0,0 +1,128 @@
+package keboola
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var rTransformation = codeTransformationComponent{id: "keboola.r-transformation-v2", normaliseScript: normaliseRScript}
+
+func resourceKeboolaRTransformation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaRTransformationCreate,
+		Read:   resourceKeboolaRTransformationRead,
+		Update: resourceKeboolaRTransformationUpdate,
+		Delete: resourceKeboolaRTransformationDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: rTransformation.customizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"script": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"script_file"},
+				DiffSuppressFunc: suppressEquivalentScript(normaliseRScript),
+			},
+			//the script is read from the file whenever the transformation is planned, and is updated when its contents change
+			"script_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateFileExists,
+				ConflictsWith: []string{"script"},
+			},
+			"script_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			//the CRAN packages installed before the script is run
+			"packages": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"backend_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCodeTransformationBackendSize,
+			},
+			"input":  &codeTransformationInputSchema,
+			"output": &outputSchema,
+		},
+	}
+}
+
+//normaliseRScript removes the whitespace that R ignores from a script, i.e. indentation, trailing whitespace,
+//blank lines and Windows line endings, so that reformatting a script is not planned as a change. Whitespace within
+//a line is kept, as it can be part of a string.
+func normaliseRScript(script string) string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.Replace(script, "\r\n", "\n", -1), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func resourceKeboolaRTransformationCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating R Transformation in Keboola.")
+
+	client := meta.(*KBCClient)
+	err := rTransformation.create(d, client)
+
+	if err != nil {
+		return err
+	}
+
+	return resourceKeboolaRTransformationRead(d, meta)
+}
+
+func resourceKeboolaRTransformationRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading R Transformation from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+
+	return rTransformation.read(d, client)
+}
+
+func resourceKeboolaRTransformationUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating R Transformation in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	err := rTransformation.update(d, client, "Updated R Transformation configuration via Terraform")
+
+	if err != nil {
+		return err
+	}
+
+	return resourceKeboolaRTransformationRead(d, meta)
+}
+
+func resourceKeboolaRTransformationDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting R Transformation in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	return rTransformation.delete(d, client)
+}
@@ -0,0 +1,148 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccRTransformation_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRTransformationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRTransformationBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_r_transformation.test_transformation", "name", "test_r_transformation"),
+					resource.TestCheckResourceAttr("keboola_r_transformation.test_transformation", "packages.0", "data.table"),
+					resource.TestCheckResourceAttr("keboola_r_transformation.test_transformation", "input.0.destination", "orders.csv"),
+					resource.TestCheckResourceAttr("keboola_r_transformation.test_transformation", "output.0.destination", "out.c-r.orders"),
+				),
+			},
+			{
+				//only the indentation of the script differs, which should not be planned as a change
+				Config:   testRTransformationReindented,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestNormaliseRScript(t *testing.T) {
+	script := "library(data.table)\n\norders <- fread('in/tables/orders.csv')\nif (nrow(orders) > 0) {\n  print('loaded')\n}\n"
+	reformatted := "library(data.table)\r\n    orders <- fread('in/tables/orders.csv')   \r\n\r\nif (nrow(orders) > 0) {\r\n\tprint('loaded')\r\n}"
+
+	assert.Equal(t, normaliseRScript(script), normaliseRScript(reformatted), "Indentation, blank lines and line endings should be ignored")
+	assert.NotEqual(t, normaliseRScript("print('a b')"), normaliseRScript("print('a  b')"), "Whitespace within a line can be part of a string, so should not be ignored")
+	assert.Equal(t, rTransformation.hashScript(script), rTransformation.hashScript(reformatted))
+}
+
+func TestRTransformationScriptSuppressesWhitespaceChanges(t *testing.T) {
+	suppressDiff := resourceKeboolaRTransformation().Schema["script"].DiffSuppressFunc
+
+	assert.True(t, suppressDiff("script", "x <- 1\nprint(x)", "  x <- 1\n\n  print(x)\n", nil))
+	assert.False(t, suppressDiff("script", "x <- 1\nprint(x)", "x <- 2\nprint(x)", nil))
+}
+
+func TestRTransformationCreateSavesConfiguration(t *testing.T) {
+	var createTransformationForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.r-transformation-v2/configs":
+			r.ParseForm()
+			createTransformationForm = r.PostForm
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.r-transformation-v2/configs/1234":
+			w.Write([]byte(`{ "id": "1234", "name": "orders", "configuration": ` + createTransformationForm.Get("configuration") + ` }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaRTransformation().Schema, map[string]interface{}{
+		"name":     "orders",
+		"script":   "library(data.table)\n",
+		"packages": []interface{}{"data.table"},
+	})
+
+	err := resourceKeboolaRTransformationCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", d.Id())
+
+	var configuration CodeTransformationConfiguration
+	json.Unmarshal([]byte(createTransformationForm.Get("configuration")), &configuration)
+
+	assert.Equal(t, []string{"library(data.table)\n"}, configuration.Parameters.Blocks[0].Codes[0].Script)
+	assert.Equal(t, []string{"data.table"}, configuration.Parameters.Packages)
+	assert.Nil(t, configuration.Runtime, "The backend should be left to Keboola when no size is set")
+	assert.Equal(t, "library(data.table)\n", d.Get("script"))
+	assert.Equal(t, rTransformation.hashScript("library(data.table)"), d.Get("script_hash"))
+}
+
+func testAccCheckRTransformationDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_r_transformation" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.r-transformation-v2/configs/%s", url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("R transformation still exists")
+		}
+	}
+
+	return nil
+}
+
+const testRTransformationBasic = `
+	resource "keboola_r_transformation" "test_transformation" {
+		name = "test_r_transformation"
+		description = "test description"
+		script = "library(data.table)\norders <- fread('in/tables/orders.csv')\nfwrite(orders, 'out/tables/orders.csv')\n"
+		packages = [ "data.table" ]
+
+		input {
+			source = "in.c-r.orders"
+			destination = "orders.csv"
+		}
+
+		output {
+			source = "orders.csv"
+			destination = "out.c-r.orders"
+			primary_key = [ "id" ]
+		}
+	}`
+
+const testRTransformationReindented = `
+	resource "keboola_r_transformation" "test_transformation" {
+		name = "test_r_transformation"
+		description = "test description"
+		script = "library(data.table)\n\n  orders <- fread('in/tables/orders.csv')\n  fwrite(orders, 'out/tables/orders.csv')\n"
+		packages = [ "data.table" ]
+
+		input {
+			source = "in.c-r.orders"
+			destination = "orders.csv"
+		}
+
+		output {
+			source = "orders.csv"
+			destination = "out.c-r.orders"
+			primary_key = [ "id" ]
+		}
+	}`
@@ -0,0 +1,523 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//RedshiftWriter is the data model for Redshift Writers within
+//the Keboola Storage API.
+type RedshiftWriter struct {
+	ID            string                      `json:"id,omitempty"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	Configuration RedshiftWriterConfiguration `json:"configuration"`
+}
+
+type RedshiftWriterConfiguration struct {
+	Storage    RedshiftWriterStorage    `json:"storage"`
+	Parameters RedshiftWriterParameters `json:"parameters"`
+}
+
+type RedshiftWriterStorage struct {
+	Input struct {
+		Tables []RedshiftWriterStorageTable `json:"tables"`
+	} `json:"input"`
+}
+
+type RedshiftWriterStorageTable struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Columns     []string `json:"columns"`
+}
+
+type RedshiftWriterParameters struct {
+	Database RedshiftDatabaseParameters `json:"db"`
+	Tables   []RedshiftWriterTable      `json:"tables"`
+}
+
+//RedshiftDatabaseParameters are the connection details of the Redshift cluster. The data is staged
+//in Keboola's own S3 bucket before being copied into Redshift, so no staging bucket is configured.
+type RedshiftDatabaseParameters struct {
+	Driver            string      `json:"driver"`
+	Host              string      `json:"host"`
+	Port              json.Number `json:"port"`
+	Database          string      `json:"database"`
+	Schema            string      `json:"schema"`
+	User              string      `json:"user"`
+	EncryptedPassword string      `json:"#password,omitempty"`
+}
+
+type RedshiftWriterTable struct {
+	DatabaseName string                    `json:"dbName"`
+	Export       bool                      `json:"export"`
+	TableID      string                    `json:"tableId"`
+	Incremental  bool                      `json:"incremental"`
+	PrimaryKey   []string                  `json:"primaryKey"`
+	Items        []RedshiftWriterTableItem `json:"items"`
+}
+
+type RedshiftWriterTableItem struct {
+	Name         string `json:"name"`
+	DatabaseName string `json:"dbName"`
+	Type         string `json:"type"`
+	Size         string `json:"size"`
+	IsNullable   bool   `json:"nullable"`
+	DefaultValue string `json:"default"`
+}
+
+//endregion
+
+//redshiftWriterSecrets are the secrets of a Redshift Writer, which are kept in state as the ciphertext Keboola holds for them.
+var redshiftWriterSecrets = []secretAttribute{
+	{key: "db_parameters.0.password", required: true},
+}
+
+func resourceKeboolaRedshiftWriter() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaRedshiftWriterCreate,
+		Read:   resourceKeboolaRedshiftWriterRead,
+		Update: resourceKeboolaRedshiftWriterUpdate,
+		Delete: resourceKeboolaRedshiftWriterDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceKeboolaRedshiftWriterCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"db_parameters": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  5439,
+						},
+						"database": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"schema": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"user": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"table": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"table_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"db_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"export": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"incremental": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"primary_key": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"column": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"db_name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"size": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"nullable": {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+									"default": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"secret_hashes": &secretHashesSchema,
+		},
+	}
+}
+
+//resourceKeboolaRedshiftWriterCustomizeDiff only plans a change to the password when it differs from the one
+//which was encrypted as the ciphertext in state.
+func resourceKeboolaRedshiftWriterCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeSecretsDiff(d, meta, redshiftWriterSecrets)
+}
+
+func resourceKeboolaRedshiftWriterCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Redshift Writer in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	createWriterForm := url.Values{}
+	createWriterForm.Add("name", d.Get("name").(string))
+	createWriterForm.Add("description", d.Get("description").(string))
+
+	createWriterBuffer := buffer.FromForm(createWriterForm)
+
+	createResponse, err := client.PostToStorage("storage/components/keboola.wr-redshift-v2/configs", createWriterBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	configuration, err := mapRedshiftWriterConfiguration(d, client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateRedshiftWriterConfiguration(d, configuration, "Created Redshift Writer configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, redshiftWriterSecrets, resourceKeboolaRedshiftWriterRead)
+}
+
+//mapRedshiftWriterConfiguration builds the writer configuration from the resource. The password is
+//encrypted for the writer before being sent, unless it has not changed, in which case it is taken
+//from the existing configuration instead, as only its ciphertext is kept in state.
+func mapRedshiftWriterConfiguration(d *schema.ResourceData, client *KBCClient, existing *RedshiftWriterConfiguration) (RedshiftWriterConfiguration, error) {
+	dbParameters := d.Get("db_parameters.0").(map[string]interface{})
+
+	database := RedshiftDatabaseParameters{
+		Driver:   "redshift",
+		Host:     dbParameters["host"].(string),
+		Port:     json.Number(fmt.Sprint(dbParameters["port"].(int))),
+		Database: dbParameters["database"].(string),
+		Schema:   dbParameters["schema"].(string),
+		User:     dbParameters["user"].(string),
+	}
+
+	if existing != nil && !d.HasChange("db_parameters.0.password") {
+		database.EncryptedPassword = existing.Parameters.Database.EncryptedPassword
+	} else {
+		encryptedPassword, err := client.EncryptValue("keboola.wr-redshift-v2", dbParameters["password"].(string))
+
+		if err != nil {
+			return RedshiftWriterConfiguration{}, fmt.Errorf("Unable to encrypt Redshift password: %v", err)
+		}
+
+		database.EncryptedPassword = encryptedPassword
+	}
+
+	tables, storageTables, err := mapRedshiftWriterTablesToModel(d.Get("table").([]interface{}))
+
+	if err != nil {
+		return RedshiftWriterConfiguration{}, err
+	}
+
+	configuration := RedshiftWriterConfiguration{
+		Parameters: RedshiftWriterParameters{
+			Database: database,
+			Tables:   tables,
+		},
+	}
+
+	configuration.Storage.Input.Tables = storageTables
+
+	return configuration, nil
+}
+
+//mapRedshiftWriterTablesToModel maps the tables to export, along with the input mapping which
+//provides each of them to the writer with only the columns being written.
+func mapRedshiftWriterTablesToModel(tables []interface{}) ([]RedshiftWriterTable, []RedshiftWriterStorageTable, error) {
+	writerTables := make([]RedshiftWriterTable, 0, len(tables))
+	storageTables := make([]RedshiftWriterStorageTable, 0, len(tables))
+	distinctTableIDs := make(map[string]bool)
+
+	for _, table := range tables {
+		config := table.(map[string]interface{})
+
+		writerTable := RedshiftWriterTable{
+			DatabaseName: config["db_name"].(string),
+			Export:       config["export"].(bool),
+			TableID:      config["table_id"].(string),
+			Incremental:  config["incremental"].(bool),
+			PrimaryKey:   AsStringArray(config["primary_key"].([]interface{})),
+		}
+
+		if distinctTableIDs[writerTable.TableID] {
+			return nil, nil, fmt.Errorf("table with table_id already exists: %s", writerTable.TableID)
+		}
+
+		distinctTableIDs[writerTable.TableID] = true
+
+		columnConfigs := config["column"].([]interface{})
+		writerTable.Items = make([]RedshiftWriterTableItem, 0, len(columnConfigs))
+		columnNames := make([]string, 0, len(columnConfigs))
+
+		for _, column := range columnConfigs {
+			columnConfig := column.(map[string]interface{})
+
+			writerTable.Items = append(writerTable.Items, RedshiftWriterTableItem{
+				Name:         columnConfig["name"].(string),
+				DatabaseName: columnConfig["db_name"].(string),
+				Type:         columnConfig["type"].(string),
+				Size:         columnConfig["size"].(string),
+				IsNullable:   columnConfig["nullable"].(bool),
+				DefaultValue: columnConfig["default"].(string),
+			})
+
+			columnNames = append(columnNames, columnConfig["name"].(string))
+		}
+
+		for _, primaryKey := range writerTable.PrimaryKey {
+			if !containsString(columnNames, primaryKey) {
+				return nil, nil, fmt.Errorf("primary key %s of table %s is not one of its columns", primaryKey, writerTable.TableID)
+			}
+		}
+
+		writerTables = append(writerTables, writerTable)
+		storageTables = append(storageTables, RedshiftWriterStorageTable{
+			Source:      writerTable.TableID,
+			Destination: fmt.Sprintf("%s.csv", writerTable.TableID),
+			Columns:     columnNames,
+		})
+	}
+
+	return writerTables, storageTables, nil
+}
+
+func mapRedshiftWriterTablesToSchema(writerTables []RedshiftWriterTable) []map[string]interface{} {
+	tables := make([]map[string]interface{}, 0, len(writerTables))
+
+	for _, writerTable := range writerTables {
+		columns := make([]map[string]interface{}, 0, len(writerTable.Items))
+
+		for _, item := range writerTable.Items {
+			columns = append(columns, map[string]interface{}{
+				"name":     item.Name,
+				"db_name":  item.DatabaseName,
+				"type":     item.Type,
+				"size":     item.Size,
+				"nullable": item.IsNullable,
+				"default":  item.DefaultValue,
+			})
+		}
+
+		tables = append(tables, map[string]interface{}{
+			"table_id":    writerTable.TableID,
+			"db_name":     writerTable.DatabaseName,
+			"export":      writerTable.Export,
+			"incremental": writerTable.Incremental,
+			"primary_key": writerTable.PrimaryKey,
+			"column":      columns,
+		})
+	}
+
+	return tables
+}
+
+func updateRedshiftWriterConfiguration(d *schema.ResourceData, configuration RedshiftWriterConfiguration, changeDescription string, client *KBCClient) error {
+	redshiftConfigJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", string(redshiftConfigJSON))
+	updateConfigurationForm.Add("changeDescription", changeDescription)
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	updateConfigurationResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/keboola.wr-redshift-v2/configs/%s", d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateConfigurationResponse) {
+		return extractError(err, updateConfigurationResponse)
+	}
+
+	return nil
+}
+
+func getRedshiftWriter(id string, client *KBCClient) (*RedshiftWriter, int, error) {
+	getWriterResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.wr-redshift-v2/configs/%s", id))
+
+	if hasErrors(err, getWriterResponse) {
+		if err == nil {
+			return nil, getWriterResponse.StatusCode, extractError(err, getWriterResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var redshiftWriter RedshiftWriter
+
+	decoder := json.NewDecoder(getWriterResponse.Body)
+	err = decoder.Decode(&redshiftWriter)
+
+	if err != nil {
+		return nil, getWriterResponse.StatusCode, err
+	}
+
+	return &redshiftWriter, getWriterResponse.StatusCode, nil
+}
+
+func resourceKeboolaRedshiftWriterRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Redshift Writer from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	redshiftWriter, statusCode, err := getRedshiftWriter(d.Id(), client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", redshiftWriter.Name)
+	d.Set("description", redshiftWriter.Description)
+
+	database := redshiftWriter.Configuration.Parameters.Database
+	port, _ := database.Port.Int64()
+
+	//Keboola only returns the encrypted password, which is kept in state in place of the password itself.
+	d.Set("db_parameters", []map[string]interface{}{
+		{
+			"host":     database.Host,
+			"port":     int(port),
+			"database": database.Database,
+			"schema":   database.Schema,
+			"user":     database.User,
+			"password": database.EncryptedPassword,
+		},
+	})
+
+	d.Set("table", mapRedshiftWriterTablesToSchema(redshiftWriter.Configuration.Parameters.Tables))
+
+	return nil
+}
+
+func resourceKeboolaRedshiftWriterUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Redshift Writer in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	redshiftWriter, _, err := getRedshiftWriter(d.Id(), client)
+
+	if err != nil {
+		return err
+	}
+
+	configuration, err := mapRedshiftWriterConfiguration(d, client, &redshiftWriter.Configuration)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateRedshiftWriterConfiguration(d, configuration, "Updated Redshift Writer configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, redshiftWriterSecrets, resourceKeboolaRedshiftWriterRead)
+}
+
+func resourceKeboolaRedshiftWriterDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Redshift Writer in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/keboola.wr-redshift-v2/configs/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
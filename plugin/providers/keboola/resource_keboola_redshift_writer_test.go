@@ -0,0 +1,261 @@
+package keboola
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccRedshiftWriter_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftWriterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testRedshiftWriterBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "name", "test_redshift_writer"),
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "db_parameters.0.port", "5439"),
+					resource.TestMatchResourceAttr("keboola_redshift_writer.test_writer", "db_parameters.0.password", regexp.MustCompile("^KBC::")),
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "table.#", "1"),
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "table.0.column.#", "2"),
+				),
+			},
+			{
+				Config: testRedshiftWriterUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "table.0.incremental", "true"),
+					resource.TestCheckResourceAttr("keboola_redshift_writer.test_writer", "table.0.column.1.type", "timestamp"),
+				),
+			},
+		},
+	})
+}
+
+func TestRedshiftWriterCreateEncryptsPassword(t *testing.T) {
+	var savedConfiguration string
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "encryption.example.com":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write([]byte("KBC::ProjectSecure::" + string(body)))
+		case r.URL.Path == "/v2/storage/tokens/verify":
+			w.Write([]byte(`{ "owner": { "id": 567 } }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.wr-redshift-v2/configs":
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/keboola.wr-redshift-v2/configs/1234":
+			r.ParseForm()
+			savedConfiguration = r.PostForm.Get("configuration")
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.wr-redshift-v2/configs/1234":
+			w.Write([]byte(fmt.Sprintf(`{ "id": "1234", "name": "writer", "configuration": %s }`, savedConfiguration)))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaRedshiftWriter().Schema, map[string]interface{}{
+		"name": "writer",
+		"db_parameters": []interface{}{
+			map[string]interface{}{
+				"host":     "redshift.example.com",
+				"database": "dwh",
+				"schema":   "sales",
+				"user":     "keboola",
+				"password": "secret",
+			},
+		},
+		"table": []interface{}{
+			map[string]interface{}{
+				"table_id":    "out.c-sales.orders",
+				"db_name":     "orders",
+				"incremental": true,
+				"primary_key": []interface{}{"id"},
+				"column": []interface{}{
+					map[string]interface{}{
+						"name":    "id",
+						"db_name": "id",
+						"type":    "int",
+					},
+					map[string]interface{}{
+						"name":     "total",
+						"db_name":  "total_amount",
+						"type":     "decimal",
+						"size":     "12,2",
+						"nullable": true,
+					},
+				},
+			},
+		},
+	})
+
+	err := resourceKeboolaRedshiftWriterCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Contains(t, savedConfiguration, `"#password":"KBC::ProjectSecure::secret"`)
+	assert.Contains(t, savedConfiguration, `"driver":"redshift"`)
+	assert.Contains(t, savedConfiguration, `"port":5439`)
+	assert.Contains(t, savedConfiguration, `{"source":"out.c-sales.orders","destination":"out.c-sales.orders.csv","columns":["id","total"]}`)
+	assert.Contains(t, savedConfiguration, `"incremental":true,"primaryKey":["id"]`)
+
+	assert.Equal(t, "KBC::ProjectSecure::secret", d.Get("db_parameters.0.password"), "Only the ciphertext of the password should be kept in state")
+	assert.Equal(t, client.secretHash("1234", "db_parameters.0.password", "KBC::ProjectSecure::secret", "secret"), d.Get("secret_hashes.db_parameters_0_password"))
+	assert.Equal(t, "sales", d.Get("db_parameters.0.schema"))
+	assert.Equal(t, "total_amount", d.Get("table.0.column.1.db_name"))
+	assert.Equal(t, "12,2", d.Get("table.0.column.1.size"))
+	assert.Equal(t, true, d.Get("table.0.column.1.nullable"))
+	assert.Equal(t, true, d.Get("table.0.export"))
+}
+
+func TestRedshiftWriterUpdateKeepsUnchangedPassword(t *testing.T) {
+	existing := RedshiftWriterConfiguration{}
+	existing.Parameters.Database.EncryptedPassword = "KBC::ProjectSecure::existing"
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+	})
+	defer server.Close()
+
+	//only a hash of the password is in state, which has not changed since it was encrypted
+	d := resourceKeboolaRedshiftWriter().Data(&terraform.InstanceState{
+		ID: "1234",
+		Attributes: map[string]string{
+			"name":                     "writer",
+			"db_parameters.#":          "1",
+			"db_parameters.0.host":     "redshift.example.com",
+			"db_parameters.0.port":     "5439",
+			"db_parameters.0.database": "dwh",
+			"db_parameters.0.schema":   "sales",
+			"db_parameters.0.user":     "keboola",
+			"db_parameters.0.password": "KBC::ProjectSecure::existing",
+		},
+	})
+
+	configuration, err := mapRedshiftWriterConfiguration(d, client, &existing)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "KBC::ProjectSecure::existing", configuration.Parameters.Database.EncryptedPassword)
+	assert.Empty(t, configuration.Parameters.Tables)
+	assert.Empty(t, configuration.Storage.Input.Tables)
+}
+
+func TestRedshiftWriterTablesRequirePrimaryKeyColumns(t *testing.T) {
+	tables := []interface{}{
+		map[string]interface{}{
+			"table_id":    "out.c-sales.orders",
+			"db_name":     "orders",
+			"export":      true,
+			"incremental": false,
+			"primary_key": []interface{}{"order_id"},
+			"column": []interface{}{
+				map[string]interface{}{
+					"name":     "id",
+					"db_name":  "id",
+					"type":     "int",
+					"size":     "",
+					"nullable": false,
+					"default":  "",
+				},
+			},
+		},
+	}
+
+	_, _, err := mapRedshiftWriterTablesToModel(tables)
+
+	assert.EqualError(t, err, "primary key order_id of table out.c-sales.orders is not one of its columns")
+}
+
+func testAccCheckRedshiftWriterDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_redshift_writer" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.wr-redshift-v2/configs/%s", url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Redshift writer still exists")
+		}
+	}
+
+	return nil
+}
+
+const testRedshiftWriterBasic = `
+	resource "keboola_redshift_writer" "test_writer" {
+		name = "test_redshift_writer"
+		description = "test description"
+
+		db_parameters {
+			host = "redshift.example.com"
+			database = "dwh"
+			schema = "sales"
+			user = "keboola"
+			password = "secret"
+		}
+
+		table {
+			table_id = "out.c-sales.orders"
+			db_name = "orders"
+			primary_key = [ "id" ]
+
+			column {
+				name = "id"
+				db_name = "id"
+				type = "int"
+			}
+
+			column {
+				name = "created"
+				db_name = "created_at"
+				type = "varchar"
+				size = "255"
+			}
+		}
+	}`
+
+const testRedshiftWriterUpdate = `
+	resource "keboola_redshift_writer" "test_writer" {
+		name = "test_redshift_writer"
+		description = "test description"
+
+		db_parameters {
+			host = "redshift.example.com"
+			database = "dwh"
+			schema = "sales"
+			user = "keboola"
+			password = "secret"
+		}
+
+		table {
+			table_id = "out.c-sales.orders"
+			db_name = "orders"
+			incremental = true
+			primary_key = [ "id" ]
+
+			column {
+				name = "id"
+				db_name = "id"
+				type = "int"
+			}
+
+			column {
+				name = "created"
+				db_name = "created_at"
+				type = "timestamp"
+			}
+		}
+	}`
@@ -0,0 +1,374 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//S3Extractor is the data model for AWS S3 Extractors within
+//the Keboola Storage API.
+type S3Extractor struct {
+	ID            string                   `json:"id,omitempty"`
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description"`
+	Configuration S3ExtractorConfiguration `json:"configuration"`
+}
+
+type S3ExtractorConfiguration struct {
+	Storage    S3ExtractorStorage    `json:"storage"`
+	Parameters S3ExtractorParameters `json:"parameters"`
+}
+
+type S3ExtractorStorage struct {
+	Output struct {
+		Tables []ExtractorOutputTable `json:"tables"`
+	} `json:"output"`
+}
+
+type S3ExtractorParameters struct {
+	AccessKeyID              string `json:"accessKeyId"`
+	EncryptedSecretAccessKey string `json:"#secretAccessKey,omitempty"`
+	Region                   string `json:"region,omitempty"`
+	Bucket                   string `json:"bucket"`
+	Key                      string `json:"key"`
+	IncludeSubfolders        bool   `json:"includeSubfolders"`
+	NewFilesOnly             bool   `json:"newFilesOnly"`
+	SaveAs                   string `json:"saveAs"`
+}
+
+//endregion
+
+//s3ExtractorSecrets are the secrets of an S3 Extractor, which are kept in state as the ciphertext Keboola holds for them.
+var s3ExtractorSecrets = []secretAttribute{
+	{key: "secret_access_key", required: true},
+}
+
+func resourceKeboolaS3Extractor() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaS3ExtractorCreate,
+		Read:   resourceKeboolaS3ExtractorRead,
+		Update: resourceKeboolaS3ExtractorUpdate,
+		Delete: resourceKeboolaS3ExtractorDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceKeboolaS3ExtractorCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"access_key_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"secret_access_key": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			//all of the files whose key starts with the prefix are extracted
+			"key_prefix": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key_wildcard"},
+			},
+			//the files whose key matches the wildcard (e.g. exports/orders-*.csv) are extracted
+			"key_wildcard": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key_prefix"},
+			},
+			"include_subfolders": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"new_files_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"output":        &extractorOutputSchema,
+			"secret_hashes": &secretHashesSchema,
+		},
+	}
+}
+
+//resourceKeboolaS3ExtractorCustomizeDiff checks that the files to extract are given by exactly one
+//of a key prefix or a wildcard, as ConflictsWith only ensures that they are not both set. A change to
+//the secret access key is only planned when it differs from the one encrypted as the ciphertext in state.
+func resourceKeboolaS3ExtractorCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("key_prefix") || !d.NewValueKnown("key_wildcard") {
+		return customizeSecretsDiff(d, meta, s3ExtractorSecrets)
+	}
+
+	if d.Get("key_prefix").(string) == "" && d.Get("key_wildcard").(string) == "" {
+		return fmt.Errorf("one of key_prefix or key_wildcard must be set")
+	}
+
+	return customizeSecretsDiff(d, meta, s3ExtractorSecrets)
+}
+
+func resourceKeboolaS3ExtractorCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating S3 Extractor in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	createExtractorForm := url.Values{}
+	createExtractorForm.Add("name", d.Get("name").(string))
+	createExtractorForm.Add("description", d.Get("description").(string))
+
+	createExtractorBuffer := buffer.FromForm(createExtractorForm)
+
+	createResponse, err := client.PostToStorage("storage/components/keboola.ex-aws-s3/configs", createExtractorBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	configuration, err := mapS3ExtractorConfiguration(d, client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateS3ExtractorConfiguration(d, configuration, "Created S3 Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, s3ExtractorSecrets, resourceKeboolaS3ExtractorRead)
+}
+
+//mapS3ExtractorConfiguration builds the extractor configuration from the resource. The secret access key
+//is encrypted for the extractor before being sent, unless it has not changed, in which case it is taken
+//from the existing configuration instead, as only its ciphertext is kept in state.
+func mapS3ExtractorConfiguration(d *schema.ResourceData, client *KBCClient, existing *S3ExtractorConfiguration) (S3ExtractorConfiguration, error) {
+	key, err := s3ExtractorKey(d.Get("key_prefix").(string), d.Get("key_wildcard").(string))
+
+	if err != nil {
+		return S3ExtractorConfiguration{}, err
+	}
+
+	outputTables, saveAs, err := mapExtractorOutputToModel(d.Get("output.0").(map[string]interface{}))
+
+	if err != nil {
+		return S3ExtractorConfiguration{}, err
+	}
+
+	parameters := S3ExtractorParameters{
+		AccessKeyID:       d.Get("access_key_id").(string),
+		Region:            d.Get("region").(string),
+		Bucket:            d.Get("bucket").(string),
+		Key:               key,
+		IncludeSubfolders: d.Get("include_subfolders").(bool),
+		NewFilesOnly:      d.Get("new_files_only").(bool),
+		SaveAs:            saveAs,
+	}
+
+	if existing != nil && !d.HasChange("secret_access_key") {
+		parameters.EncryptedSecretAccessKey = existing.Parameters.EncryptedSecretAccessKey
+	} else {
+		encryptedSecretAccessKey, err := client.EncryptValue("keboola.ex-aws-s3", d.Get("secret_access_key").(string))
+
+		if err != nil {
+			return S3ExtractorConfiguration{}, fmt.Errorf("Unable to encrypt S3 secret access key: %v", err)
+		}
+
+		parameters.EncryptedSecretAccessKey = encryptedSecretAccessKey
+	}
+
+	configuration := S3ExtractorConfiguration{
+		Parameters: parameters,
+	}
+
+	configuration.Storage.Output.Tables = outputTables
+
+	return configuration, nil
+}
+
+//s3ExtractorKey maps the key prefix or wildcard to the key of the extractor, which is always a wildcard.
+func s3ExtractorKey(keyPrefix string, keyWildcard string) (string, error) {
+	if keyPrefix != "" && keyWildcard != "" {
+		return "", fmt.Errorf("only one of key_prefix or key_wildcard can be set")
+	}
+
+	if keyWildcard != "" {
+		return keyWildcard, nil
+	}
+
+	if keyPrefix != "" {
+		return keyPrefix + "*", nil
+	}
+
+	return "", fmt.Errorf("one of key_prefix or key_wildcard must be set")
+}
+
+func updateS3ExtractorConfiguration(d *schema.ResourceData, configuration S3ExtractorConfiguration, changeDescription string, client *KBCClient) error {
+	s3ConfigJSON, err := json.Marshal(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	updateConfigurationForm := url.Values{}
+	updateConfigurationForm.Add("name", d.Get("name").(string))
+	updateConfigurationForm.Add("description", d.Get("description").(string))
+	updateConfigurationForm.Add("configuration", string(s3ConfigJSON))
+	updateConfigurationForm.Add("changeDescription", changeDescription)
+
+	updateConfigurationBuffer := buffer.FromForm(updateConfigurationForm)
+
+	updateConfigurationResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/keboola.ex-aws-s3/configs/%s", d.Id()), updateConfigurationBuffer)
+
+	if hasErrors(err, updateConfigurationResponse) {
+		return extractError(err, updateConfigurationResponse)
+	}
+
+	return nil
+}
+
+func getS3Extractor(id string, client *KBCClient) (*S3Extractor, int, error) {
+	getExtractorResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.ex-aws-s3/configs/%s", id))
+
+	if hasErrors(err, getExtractorResponse) {
+		if err == nil {
+			return nil, getExtractorResponse.StatusCode, extractError(err, getExtractorResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var s3Extractor S3Extractor
+
+	decoder := json.NewDecoder(getExtractorResponse.Body)
+	err = decoder.Decode(&s3Extractor)
+
+	if err != nil {
+		return nil, getExtractorResponse.StatusCode, err
+	}
+
+	return &s3Extractor, getExtractorResponse.StatusCode, nil
+}
+
+func resourceKeboolaS3ExtractorRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading S3 Extractor from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	s3Extractor, statusCode, err := getS3Extractor(d.Id(), client)
+
+	if statusCode == 404 {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	parameters := s3Extractor.Configuration.Parameters
+
+	d.Set("name", s3Extractor.Name)
+	d.Set("description", s3Extractor.Description)
+	d.Set("access_key_id", parameters.AccessKeyID)
+	d.Set("region", parameters.Region)
+	d.Set("bucket", parameters.Bucket)
+	d.Set("include_subfolders", parameters.IncludeSubfolders)
+	d.Set("new_files_only", parameters.NewFilesOnly)
+
+	//Keboola only returns the encrypted secret access key, which is kept in state in place of the key itself.
+	d.Set("secret_access_key", parameters.EncryptedSecretAccessKey)
+
+	//a key ending in its only wildcard is read back as a prefix, unless it was configured as a wildcard
+	if _, isWildcard := d.GetOk("key_wildcard"); !isWildcard && strings.HasSuffix(parameters.Key, "*") && strings.Count(parameters.Key, "*") == 1 {
+		d.Set("key_prefix", strings.TrimSuffix(parameters.Key, "*"))
+		d.Set("key_wildcard", "")
+	} else {
+		d.Set("key_prefix", "")
+		d.Set("key_wildcard", parameters.Key)
+	}
+
+	d.Set("output", mapExtractorOutputToSchema(s3Extractor.Configuration.Storage.Output.Tables))
+
+	return nil
+}
+
+func resourceKeboolaS3ExtractorUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating S3 Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	s3Extractor, _, err := getS3Extractor(d.Id(), client)
+
+	if err != nil {
+		return err
+	}
+
+	configuration, err := mapS3ExtractorConfiguration(d, client, &s3Extractor.Configuration)
+
+	if err != nil {
+		return err
+	}
+
+	err = updateS3ExtractorConfiguration(d, configuration, "Updated S3 Extractor configuration via Terraform", client)
+
+	if err != nil {
+		return err
+	}
+
+	return readAfterSendingSecrets(d, meta, s3ExtractorSecrets, resourceKeboolaS3ExtractorRead)
+}
+
+func resourceKeboolaS3ExtractorDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting S3 Extractor in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/keboola.ex-aws-s3/configs/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package keboola
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccS3Extractor_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckS3ExtractorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testS3ExtractorBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "name", "test_s3_extractor"),
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "key_prefix", "exports/orders/"),
+					resource.TestMatchResourceAttr("keboola_s3_extractor.test_extractor", "secret_access_key", regexp.MustCompile("^KBC::")),
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "output.0.destination", "in.c-s3.orders"),
+				),
+			},
+			{
+				Config: testS3ExtractorUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "key_prefix", ""),
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "key_wildcard", "exports/orders/*.csv"),
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "new_files_only", "true"),
+					resource.TestCheckResourceAttr("keboola_s3_extractor.test_extractor", "output.0.incremental", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestS3ExtractorCreateEncryptsSecretAccessKey(t *testing.T) {
+	var savedConfiguration string
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "encryption.example.com":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write([]byte("KBC::ProjectSecure::" + string(body)))
+		case r.URL.Path == "/v2/storage/tokens/verify":
+			w.Write([]byte(`{ "owner": { "id": 567 } }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.ex-aws-s3/configs":
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/keboola.ex-aws-s3/configs/1234":
+			r.ParseForm()
+			savedConfiguration = r.PostForm.Get("configuration")
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.ex-aws-s3/configs/1234":
+			w.Write([]byte(fmt.Sprintf(`{ "id": "1234", "name": "extractor", "configuration": %s }`, savedConfiguration)))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaS3Extractor().Schema, map[string]interface{}{
+		"name":              "extractor",
+		"access_key_id":     "AKIAEXAMPLE",
+		"secret_access_key": "secret",
+		"region":            "eu-central-1",
+		"bucket":            "company-exports",
+		"key_prefix":        "exports/orders/",
+		"new_files_only":    true,
+		"output": []interface{}{
+			map[string]interface{}{
+				"destination": "in.c-s3.orders",
+				"incremental": true,
+				"primary_key": []interface{}{"id"},
+			},
+		},
+	})
+
+	err := resourceKeboolaS3ExtractorCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Contains(t, savedConfiguration, `"accessKeyId":"AKIAEXAMPLE","#secretAccessKey":"KBC::ProjectSecure::secret"`)
+	assert.Contains(t, savedConfiguration, `"region":"eu-central-1","bucket":"company-exports","key":"exports/orders/*"`)
+	assert.Contains(t, savedConfiguration, `"newFilesOnly":true,"saveAs":"orders"`)
+	assert.Contains(t, savedConfiguration, `{"source":"orders","destination":"in.c-s3.orders","incremental":true,"primary_key":["id"]}`)
+
+	assert.Equal(t, "KBC::ProjectSecure::secret", d.Get("secret_access_key"), "Only the ciphertext of the secret access key should be kept in state")
+	assert.Equal(t, client.secretHash("1234", "secret_access_key", "KBC::ProjectSecure::secret", "secret"), d.Get("secret_hashes.secret_access_key"))
+	assert.Equal(t, "exports/orders/", d.Get("key_prefix"))
+	assert.Equal(t, "", d.Get("key_wildcard"))
+	assert.Equal(t, "in.c-s3.orders", d.Get("output.0.destination"))
+}
+
+func TestS3ExtractorUpdateKeepsUnchangedSecretAccessKey(t *testing.T) {
+	existing := S3ExtractorConfiguration{}
+	existing.Parameters.EncryptedSecretAccessKey = "KBC::ProjectSecure::existing"
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+	})
+	defer server.Close()
+
+	d := resourceKeboolaS3Extractor().Data(&terraform.InstanceState{
+		ID: "1234",
+		Attributes: map[string]string{
+			"name":                 "extractor",
+			"access_key_id":        "AKIAEXAMPLE",
+			"secret_access_key":    "KBC::ProjectSecure::existing",
+			"bucket":               "company-exports",
+			"key_wildcard":         "exports/orders-*.csv",
+			"output.#":             "1",
+			"output.0.destination": "in.c-s3.orders",
+		},
+	})
+
+	configuration, err := mapS3ExtractorConfiguration(d, client, &existing)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "KBC::ProjectSecure::existing", configuration.Parameters.EncryptedSecretAccessKey)
+	assert.Equal(t, "exports/orders-*.csv", configuration.Parameters.Key)
+}
+
+func TestS3ExtractorKey(t *testing.T) {
+	key, err := s3ExtractorKey("exports/", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "exports/*", key)
+
+	key, err = s3ExtractorKey("", "exports/*.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "exports/*.csv", key)
+
+	_, err = s3ExtractorKey("", "")
+	assert.EqualError(t, err, "one of key_prefix or key_wildcard must be set")
+
+	_, err = s3ExtractorKey("exports/", "exports/*.csv")
+	assert.EqualError(t, err, "only one of key_prefix or key_wildcard can be set")
+}
+
+func testAccCheckS3ExtractorDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_s3_extractor" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.ex-aws-s3/configs/%s", url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("S3 extractor still exists")
+		}
+	}
+
+	return nil
+}
+
+const testS3ExtractorBasic = `
+	resource "keboola_s3_extractor" "test_extractor" {
+		name = "test_s3_extractor"
+		description = "test description"
+		access_key_id = "AKIAEXAMPLE"
+		secret_access_key = "secret"
+		bucket = "company-exports"
+		key_prefix = "exports/orders/"
+
+		output {
+			destination = "in.c-s3.orders"
+			primary_key = [ "id" ]
+		}
+	}`
+
+const testS3ExtractorUpdate = `
+	resource "keboola_s3_extractor" "test_extractor" {
+		name = "test_s3_extractor"
+		description = "test description"
+		access_key_id = "AKIAEXAMPLE"
+		secret_access_key = "secret"
+		bucket = "company-exports"
+		key_wildcard = "exports/orders/*.csv"
+		new_files_only = true
+
+		output {
+			destination = "in.c-s3.orders"
+			incremental = true
+			primary_key = [ "id" ]
+		}
+	}`
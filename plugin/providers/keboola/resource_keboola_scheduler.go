@@ -0,0 +1,303 @@
+package keboola
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+const (
+	schedulerComponentID = "keboola.scheduler"
+
+	scheduleStateEnabled  = "enabled"
+	scheduleStateDisabled = "disabled"
+)
+
+//region Keboola API Contracts
+
+//Scheduler is the configuration of a schedule, stored as a configuration
+//of the keboola.scheduler component within the Keboola Storage API.
+type Scheduler struct {
+	ID            string                 `json:"id,omitempty"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Configuration SchedulerConfiguration `json:"configuration"`
+}
+
+type SchedulerConfiguration struct {
+	Schedule SchedulerSchedule `json:"schedule"`
+	Target   SchedulerTarget   `json:"target"`
+}
+
+type SchedulerSchedule struct {
+	CronTab  string `json:"cronTab"`
+	Timezone string `json:"timezone"`
+	State    string `json:"state"`
+}
+
+type SchedulerTarget struct {
+	ComponentID     string `json:"componentId"`
+	ConfigurationID string `json:"configurationId"`
+	Mode            string `json:"mode"`
+}
+
+//SchedulerActivation activates a schedule configuration within the Keboola Scheduler API.
+type SchedulerActivation struct {
+	ConfigurationID string `json:"configurationId"`
+}
+
+//ActivatedSchedule is a schedule which has been activated within the Keboola Scheduler API.
+type ActivatedSchedule struct {
+	ID string `json:"id"`
+}
+
+//endregion
+
+func resourceKeboolaScheduler() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaSchedulerCreate,
+		Read:   resourceKeboolaSchedulerRead,
+		Update: resourceKeboolaSchedulerUpdate,
+		Delete: resourceKeboolaSchedulerDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"component_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"configuration_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"cron_schedule": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCronSchedule,
+			},
+			"timezone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "UTC",
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"schedule_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKeboolaSchedulerCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Scheduler in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	configurationJSON, err := json.Marshal(mapSchedulerConfiguration(d))
+
+	if err != nil {
+		return err
+	}
+
+	createSchedulerForm := url.Values{}
+	createSchedulerForm.Add("name", d.Get("name").(string))
+	createSchedulerForm.Add("description", d.Get("description").(string))
+	createSchedulerForm.Add("configuration", string(configurationJSON))
+
+	createSchedulerBuffer := buffer.FromForm(createSchedulerForm)
+
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/components/%s/configs", schedulerComponentID), createSchedulerBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(createResult.ID))
+
+	if err := activateSchedule(d, client); err != nil {
+		return err
+	}
+
+	return resourceKeboolaSchedulerRead(d, meta)
+}
+
+func mapSchedulerConfiguration(d *schema.ResourceData) SchedulerConfiguration {
+	state := scheduleStateEnabled
+	if !d.Get("enabled").(bool) {
+		state = scheduleStateDisabled
+	}
+
+	return SchedulerConfiguration{
+		Schedule: SchedulerSchedule{
+			CronTab:  d.Get("cron_schedule").(string),
+			Timezone: d.Get("timezone").(string),
+			State:    state,
+		},
+		Target: SchedulerTarget{
+			ComponentID:     d.Get("component_id").(string),
+			ConfigurationID: d.Get("configuration_id").(string),
+			Mode:            "run",
+		},
+	}
+}
+
+//activateSchedule activates the schedule configuration within the Keboola Scheduler API, which then
+//triggers the target configuration on the cron schedule (unless the schedule has been disabled).
+//Activating a configuration again replaces its schedule with one using the current configuration.
+func activateSchedule(d *schema.ResourceData, client *KBCClient) error {
+	activationJSON, err := json.Marshal(SchedulerActivation{ConfigurationID: d.Id()})
+
+	if err != nil {
+		return err
+	}
+
+	activateResponse, err := client.PostToScheduler("schedules", bytes.NewBuffer(activationJSON))
+
+	if hasErrors(err, activateResponse) {
+		return fmt.Errorf("Unable to activate schedule %s: %v", d.Id(), extractError(err, activateResponse))
+	}
+
+	var activatedSchedule ActivatedSchedule
+
+	decoder := json.NewDecoder(activateResponse.Body)
+	err = decoder.Decode(&activatedSchedule)
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("schedule_id", activatedSchedule.ID)
+
+	return nil
+}
+
+func resourceKeboolaSchedulerRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Scheduler from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", schedulerComponentID, d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var scheduler Scheduler
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&scheduler)
+
+	if err != nil {
+		return err
+	}
+
+	enabled := scheduler.Configuration.Schedule.State == scheduleStateEnabled
+
+	if d.Get("enabled").(bool) && !enabled {
+		log.Printf("[WARN] Schedule %s has been disabled outside of Terraform (e.g. paused in the UI).", d.Id())
+	}
+
+	d.Set("name", scheduler.Name)
+	d.Set("description", scheduler.Description)
+	d.Set("component_id", scheduler.Configuration.Target.ComponentID)
+	d.Set("configuration_id", scheduler.Configuration.Target.ConfigurationID)
+	d.Set("cron_schedule", scheduler.Configuration.Schedule.CronTab)
+	d.Set("timezone", scheduler.Configuration.Schedule.Timezone)
+	d.Set("enabled", enabled)
+
+	return nil
+}
+
+func resourceKeboolaSchedulerUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Scheduler in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	configurationJSON, err := json.Marshal(mapSchedulerConfiguration(d))
+
+	if err != nil {
+		return err
+	}
+
+	updateSchedulerForm := url.Values{}
+	updateSchedulerForm.Add("name", d.Get("name").(string))
+	updateSchedulerForm.Add("description", d.Get("description").(string))
+	updateSchedulerForm.Add("configuration", string(configurationJSON))
+	updateSchedulerForm.Add("changeDescription", "Update schedule via Terraform")
+
+	updateSchedulerBuffer := buffer.FromForm(updateSchedulerForm)
+
+	updateResponse, err := client.PutToStorage(fmt.Sprintf("storage/components/%s/configs/%s", schedulerComponentID, d.Id()), updateSchedulerBuffer)
+
+	if hasErrors(err, updateResponse) {
+		return extractError(err, updateResponse)
+	}
+
+	if d.HasChange("cron_schedule") || d.HasChange("timezone") || d.HasChange("enabled") {
+		if err := activateSchedule(d, client); err != nil {
+			return err
+		}
+	}
+
+	return resourceKeboolaSchedulerRead(d, meta)
+}
+
+func resourceKeboolaSchedulerDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Scheduler in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	deactivateResponse, err := client.DeleteFromScheduler(fmt.Sprintf("configurations/%s", d.Id()))
+
+	if hasErrors(err, deactivateResponse) && (err != nil || deactivateResponse.StatusCode != 404) {
+		return fmt.Errorf("Unable to deactivate schedule %s: %v", d.Id(), extractError(err, deactivateResponse))
+	}
+
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/%s/configs/%s", schedulerComponentID, d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
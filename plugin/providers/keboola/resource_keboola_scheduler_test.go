@@ -0,0 +1,152 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccScheduler_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSchedulerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testSchedulerBasic, "0 6 * * *", "UTC", "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_scheduler.test_scheduler", "cron_schedule", "0 6 * * *"),
+					resource.TestCheckResourceAttr("keboola_scheduler.test_scheduler", "enabled", "true"),
+					resource.TestCheckResourceAttrSet("keboola_scheduler.test_scheduler", "schedule_id"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testSchedulerBasic, "30 7 * * 1-5", "Europe/Prague", "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_scheduler.test_scheduler", "cron_schedule", "30 7 * * 1-5"),
+					resource.TestCheckResourceAttr("keboola_scheduler.test_scheduler", "timezone", "Europe/Prague"),
+					resource.TestCheckResourceAttr("keboola_scheduler.test_scheduler", "enabled", "false"),
+				),
+			},
+			{
+				ResourceName:            "keboola_scheduler.test_scheduler",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"schedule_id"},
+			},
+		},
+	})
+}
+
+func TestSchedulerCreateActivatesSchedule(t *testing.T) {
+	var savedConfiguration string
+	var activation SchedulerActivation
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.Host == "scheduler.example.com" && r.URL.Path == "/schedules":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &activation)
+			w.Write([]byte(`{ "id": "5678", "configurationId": "1234" }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.scheduler/configs":
+			r.ParseForm()
+			savedConfiguration = r.PostForm.Get("configuration")
+			w.Write([]byte(`{ "id": "1234" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.scheduler/configs/1234":
+			w.Write([]byte(fmt.Sprintf(`{ "id": "1234", "name": "nightly", "configuration": %s }`, savedConfiguration)))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaScheduler().Schema, map[string]interface{}{
+		"name":             "nightly",
+		"component_id":     "keboola.ex-db-mysql",
+		"configuration_id": "4321",
+		"cron_schedule":    "0 6 * * *",
+	})
+
+	err := resourceKeboolaSchedulerCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", activation.ConfigurationID, "The schedule configuration should be activated")
+	assert.JSONEq(t, `{
+		"schedule": { "cronTab": "0 6 * * *", "timezone": "UTC", "state": "enabled" },
+		"target": { "componentId": "keboola.ex-db-mysql", "configurationId": "4321", "mode": "run" }
+	}`, savedConfiguration)
+	assert.Equal(t, "5678", d.Get("schedule_id"))
+	assert.Equal(t, true, d.Get("enabled"))
+}
+
+func TestSchedulerReadDetectsPausedSchedule(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "1234",
+			"name": "nightly",
+			"configuration": {
+				"schedule": { "cronTab": "0 6 * * *", "timezone": "UTC", "state": "disabled" },
+				"target": { "componentId": "keboola.ex-db-mysql", "configurationId": "4321", "mode": "run" }
+			}
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaScheduler().Schema, map[string]interface{}{
+		"enabled": true,
+	})
+	d.SetId("1234")
+
+	err := resourceKeboolaSchedulerRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, false, d.Get("enabled"), "A schedule paused in the UI should be read as disabled")
+}
+
+func TestValidateCronSchedule(t *testing.T) {
+	_, errors := validateCronSchedule("0 6 * * 1-5", "cron_schedule")
+	assert.Empty(t, errors)
+
+	_, errors = validateCronSchedule("0 6 * *", "cron_schedule")
+	assert.NotEmpty(t, errors, "A crontab schedule needs five fields")
+}
+
+func testAccCheckSchedulerDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_scheduler" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.scheduler/configs/%s", rs.Primary.ID))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Scheduler still exists")
+		}
+	}
+
+	return nil
+}
+
+const testSchedulerBasic = `
+	resource "keboola_csvimport_extractor" "test_extractor" {
+		name = "test_scheduled_extractor"
+		destination = "in.c-test.scheduled"
+	}
+
+	resource "keboola_scheduler" "test_scheduler" {
+		name = "test_scheduler"
+		component_id = "keboola.csv-import"
+		configuration_id = "${keboola_csvimport_extractor.test_extractor.id}"
+		cron_schedule = "%s"
+		timezone = "%s"
+		enabled = %s
+	}`
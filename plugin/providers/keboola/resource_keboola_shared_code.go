@@ -0,0 +1,366 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+const sharedCodeComponentID = "keboola.shared-code"
+
+//region Keboola API Contracts
+
+//SharedCodeConfiguration is the configuration of the shared code component which holds the shared
+//code (as its rows) for one type of transformation, e.g. keboola.python-transformation-v2.
+type SharedCodeConfiguration struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Configuration struct {
+		ComponentID string `json:"componentId"`
+	} `json:"configuration"`
+	Rows []SharedCodeRow `json:"rows"`
+}
+
+//SharedCodeRow is a single piece of shared code.
+type SharedCodeRow struct {
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name"`
+	Configuration struct {
+		CodeContent SharedCodeContent `json:"code_content"`
+	} `json:"configuration"`
+}
+
+//SharedCodeContent is the content of a piece of shared code. Depending on the type of transformation
+//this is either a single script, or a list of statements (e.g. SQL queries), which are joined by new lines.
+type SharedCodeContent string
+
+//UnmarshalJSON accepts both a single script and a list of statements.
+func (content *SharedCodeContent) UnmarshalJSON(data []byte) error {
+	var statements []string
+
+	if err := json.Unmarshal(data, &statements); err != nil {
+		var script string
+
+		if err := json.Unmarshal(data, &script); err != nil {
+			return err
+		}
+
+		*content = SharedCodeContent(script)
+		return nil
+	}
+
+	*content = SharedCodeContent(strings.Join(statements, "\n"))
+	return nil
+}
+
+//endregion
+
+//resourceKeboolaSharedCode manages a piece of code shared between transformations of the same type. Keboola
+//keeps the shared code for each type of transformation as the rows of a single configuration of the shared code
+//component, which is found (or created, if there is none yet) from the type of transformation.
+func resourceKeboolaSharedCode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaSharedCodeCreate,
+		Read:   resourceKeboolaSharedCodeRead,
+		Update: resourceKeboolaSharedCodeUpdate,
+		Delete: resourceKeboolaSharedCodeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKeboolaSharedCodeImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			//the type of transformation which the code is shared between, e.g. keboola.python-transformation-v2
+			"target_component_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			//the name which the code is referred to by within transformations
+			"code_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			//only a hash of the code is kept in state, which still changes when the code does
+			"code": {
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: hashContent,
+			},
+			"configuration_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+//findSharedCodeConfiguration finds the configuration holding the shared code for a type of transformation,
+//returning nil when there is none yet.
+func findSharedCodeConfiguration(client *KBCClient, targetComponentID string) (*SharedCodeConfiguration, error) {
+	//the configurations of a component are not paginated, so are all listed by a single request
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/%s/configs", sharedCodeComponentID))
+
+	if hasErrors(err, getResponse) {
+		return nil, extractError(err, getResponse)
+	}
+
+	var configurations []SharedCodeConfiguration
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&configurations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, configuration := range configurations {
+		if configuration.Configuration.ComponentID == targetComponentID {
+			return &configuration, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//sharedCodeConfigurationMutex stops shared code created in parallel from creating more than one
+//configuration for the same type of transformation.
+var sharedCodeConfigurationMutex sync.Mutex
+
+//sharedCodeConfigurationID gets the ID of the configuration holding the shared code for a type of
+//transformation, creating the configuration when there is none yet.
+func sharedCodeConfigurationID(client *KBCClient, targetComponentID string) (string, error) {
+	sharedCodeConfigurationMutex.Lock()
+	defer sharedCodeConfigurationMutex.Unlock()
+
+	configuration, err := findSharedCodeConfiguration(client, targetComponentID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if configuration != nil {
+		return configuration.ID, nil
+	}
+
+	log.Printf("[INFO] Creating Shared Code configuration for %s in Keboola.", targetComponentID)
+
+	configurationJSON, err := json.Marshal(map[string]string{"componentId": targetComponentID})
+
+	if err != nil {
+		return "", err
+	}
+
+	createConfigurationForm := url.Values{}
+	createConfigurationForm.Add("name", fmt.Sprintf("Shared Code for %s", targetComponentID))
+	createConfigurationForm.Add("configuration", string(configurationJSON))
+
+	createConfigurationBuffer := buffer.FromForm(createConfigurationForm)
+
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/components/%s/configs", sharedCodeComponentID), createConfigurationBuffer)
+
+	if hasErrors(err, createResponse) {
+		return "", extractError(err, createResponse)
+	}
+
+	var createResult CreateResourceResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createResult)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(createResult.ID), nil
+}
+
+func sharedCodeRowsEndpoint(d *schema.ResourceData) string {
+	return fmt.Sprintf("storage/components/%s/configs/%s/rows", sharedCodeComponentID, d.Get("configuration_id").(string))
+}
+
+func sharedCodeRowConfiguration(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{"code_content": []string{d.Get("code").(string)}}
+}
+
+func mapSharedCodeRowForm(d *schema.ResourceData, rowConfiguration map[string]interface{}) (url.Values, error) {
+	rowConfigurationJSON, err := json.Marshal(rowConfiguration)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowForm := url.Values{}
+	rowForm.Add("name", d.Get("code_id").(string))
+	rowForm.Add("configuration", string(rowConfigurationJSON))
+
+	return rowForm, nil
+}
+
+func resourceKeboolaSharedCodeCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Shared Code in Keboola.")
+
+	client := meta.(*KBCClient)
+	configurationID, err := sharedCodeConfigurationID(client, d.Get("target_component_id").(string))
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("configuration_id", configurationID)
+
+	createRowForm, err := mapSharedCodeRowForm(d, sharedCodeRowConfiguration(d))
+
+	if err != nil {
+		return err
+	}
+
+	createRowBuffer := buffer.FromForm(createRowForm)
+
+	createResponse, err := client.PostToStorage(sharedCodeRowsEndpoint(d), createRowBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createdRow SharedCodeRow
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createdRow)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(createdRow.ID)
+
+	return resourceKeboolaSharedCodeRead(d, meta)
+}
+
+func resourceKeboolaSharedCodeRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Shared Code from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("%s/%s", sharedCodeRowsEndpoint(d), d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var sharedCodeRow SharedCodeRow
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&sharedCodeRow)
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("code_id", sharedCodeRow.Name)
+	d.Set("code", hashContent(string(sharedCodeRow.Configuration.CodeContent)))
+
+	return nil
+}
+
+func resourceKeboolaSharedCodeUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Shared Code in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	rowConfiguration := sharedCodeRowConfiguration(d)
+
+	//only a hash of the code is kept in state, so unless the code has changed the row keeps its existing code
+	if !d.HasChange("code") {
+		getResponse, err := client.GetFromStorage(fmt.Sprintf("%s/%s", sharedCodeRowsEndpoint(d), d.Id()))
+
+		if hasErrors(err, getResponse) {
+			return extractError(err, getResponse)
+		}
+
+		var existingRow struct {
+			Configuration map[string]interface{} `json:"configuration"`
+		}
+
+		decoder := json.NewDecoder(getResponse.Body)
+		err = decoder.Decode(&existingRow)
+
+		if err != nil {
+			return err
+		}
+
+		rowConfiguration = existingRow.Configuration
+	}
+
+	updateRowForm, err := mapSharedCodeRowForm(d, rowConfiguration)
+
+	if err != nil {
+		return err
+	}
+
+	updateRowForm.Add("changeDescription", "Update shared code via Terraform")
+
+	updateRowBuffer := buffer.FromForm(updateRowForm)
+
+	updateResponse, err := client.PutToStorage(fmt.Sprintf("%s/%s", sharedCodeRowsEndpoint(d), d.Id()), updateRowBuffer)
+
+	if hasErrors(err, updateResponse) {
+		return extractError(err, updateResponse)
+	}
+
+	return resourceKeboolaSharedCodeRead(d, meta)
+}
+
+//resourceKeboolaSharedCodeImport imports shared code by its type of transformation and its own ID
+//(e.g. keboola.python-transformation-v2/123456), finding the configuration holding it from the former.
+func resourceKeboolaSharedCodeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Shared Code must be imported as <target_component_id>/<row_id>, got %q", d.Id())
+	}
+
+	configuration, err := findSharedCodeConfiguration(meta.(*KBCClient), parts[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if configuration == nil {
+		return nil, fmt.Errorf("No Shared Code configuration found for %s", parts[0])
+	}
+
+	d.Set("target_component_id", parts[0])
+	d.Set("configuration_id", configuration.ID)
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKeboolaSharedCodeDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Shared Code in Keboola: %s", d.Id())
+
+	//the configuration holding the shared code is left in place, as it is shared with any other shared code
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("%s/%s", sharedCodeRowsEndpoint(d), d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccSharedCode_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSharedCodeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testSharedCodeBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_shared_code.test_code", "code_id", "load_orders"),
+					resource.TestCheckResourceAttr("keboola_shared_code.test_code", "code", hashContent("import pandas\n")),
+					resource.TestCheckResourceAttrSet("keboola_shared_code.test_code", "configuration_id"),
+				),
+			},
+			{
+				Config: testSharedCodeUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_shared_code.test_code", "code", hashContent("import pandas as pd\n")),
+				),
+			},
+		},
+	})
+}
+
+func TestSharedCodeCreateUsesExistingConfiguration(t *testing.T) {
+	var createRowForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs":
+			assert.Empty(t, r.URL.Query().Get("offset"), "The configurations of a component are not paginated")
+			w.Write([]byte(`[
+				{ "id": "100", "configuration": { "componentId": "keboola.snowflake-transformation" } },
+				{ "id": "200", "configuration": { "componentId": "keboola.python-transformation-v2" } }
+			]`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows":
+			r.ParseForm()
+			createRowForm = r.PostForm
+			w.Write([]byte(`{ "id": "300" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows/300":
+			w.Write([]byte(`{ "id": "300", "name": "load_orders", "configuration": ` + createRowForm.Get("configuration") + ` }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaSharedCode().Schema, map[string]interface{}{
+		"target_component_id": "keboola.python-transformation-v2",
+		"code_id":             "load_orders",
+		"code":                "import pandas\n",
+	})
+
+	err := resourceKeboolaSharedCodeCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "300", d.Id())
+	assert.Equal(t, "200", d.Get("configuration_id"))
+	assert.Equal(t, "load_orders", createRowForm.Get("name"))
+	assert.JSONEq(t, `{ "code_content": [ "import pandas\n" ] }`, createRowForm.Get("configuration"))
+	assert.Equal(t, hashContent("import pandas\n"), d.Get("code"))
+}
+
+func TestSharedCodeCreateCreatesConfigurationForNewTargetComponent(t *testing.T) {
+	var createConfigurationForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs":
+			w.Write([]byte(`[]`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs":
+			r.ParseForm()
+			createConfigurationForm = r.PostForm
+			w.Write([]byte(`{ "id": "200" }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows":
+			w.Write([]byte(`{ "id": "300" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows/300":
+			w.Write([]byte(`{ "id": "300", "name": "load_orders", "configuration": { "code_content": "library(data.table)" } }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaSharedCode().Schema, map[string]interface{}{
+		"target_component_id": "keboola.r-transformation-v2",
+		"code_id":             "load_orders",
+		"code":                "library(data.table)",
+	})
+
+	err := resourceKeboolaSharedCodeCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "200", d.Get("configuration_id"))
+	assert.JSONEq(t, `{ "componentId": "keboola.r-transformation-v2" }`, createConfigurationForm.Get("configuration"))
+	assert.Equal(t, hashContent("library(data.table)"), d.Get("code"), "Code saved as a single script should be read back")
+}
+
+func TestSharedCodeContentJoinsStatements(t *testing.T) {
+	var content SharedCodeContent
+
+	err := json.Unmarshal([]byte(`[ "SELECT 1;", "SELECT 2;" ]`), &content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SharedCodeContent("SELECT 1;\nSELECT 2;"), content)
+}
+
+func TestSharedCodeReadRemovesDeletedCode(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := resourceKeboolaSharedCode().Data(&terraform.InstanceState{
+		ID:         "300",
+		Attributes: map[string]string{"configuration_id": "200"},
+	})
+
+	err := resourceKeboolaSharedCodeRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+}
+
+func TestSharedCodeUpdateKeepsCodeWhenOnlyRenamed(t *testing.T) {
+	var updateRowForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows/300" && updateRowForm == nil:
+			w.Write([]byte(`{ "id": "300", "name": "load_orders", "configuration": { "code_content": [ "import pandas\n" ] } }`))
+		case r.Method == "PUT" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows/300":
+			r.ParseForm()
+			updateRowForm = r.PostForm
+			w.Write([]byte(`{ "id": "300" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/components/keboola.shared-code/configs/200/rows/300":
+			w.Write([]byte(`{ "id": "300", "name": "load_all_orders", "configuration": ` + updateRowForm.Get("configuration") + ` }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := resourceKeboolaSharedCode().Data(&terraform.InstanceState{
+		ID: "300",
+		Attributes: map[string]string{
+			"target_component_id": "keboola.python-transformation-v2",
+			"configuration_id":    "200",
+			"code_id":             "load_orders",
+			"code":                hashContent("import pandas\n"),
+		},
+	})
+	d.Set("code_id", "load_all_orders")
+
+	err := resourceKeboolaSharedCodeUpdate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "load_all_orders", updateRowForm.Get("name"))
+	assert.JSONEq(t, `{ "code_content": [ "import pandas\n" ] }`, updateRowForm.Get("configuration"), "The existing code should be sent rather than its hash")
+	assert.Equal(t, hashContent("import pandas\n"), d.Get("code"))
+}
+
+func TestSharedCodeImportFindsConfiguration(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[ { "id": "200", "configuration": { "componentId": "keboola.python-transformation-v2" } } ]`))
+	})
+	defer server.Close()
+
+	d := resourceKeboolaSharedCode().Data(&terraform.InstanceState{ID: "keboola.python-transformation-v2/300"})
+
+	imported, err := resourceKeboolaSharedCodeImport(d, client)
+
+	assert.NoError(t, err)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, "300", d.Id())
+	assert.Equal(t, "200", d.Get("configuration_id"))
+	assert.Equal(t, "keboola.python-transformation-v2", d.Get("target_component_id"))
+
+	_, err = resourceKeboolaSharedCodeImport(resourceKeboolaSharedCode().Data(&terraform.InstanceState{ID: "300"}), client)
+
+	assert.Error(t, err, "The type of transformation is needed to find the configuration")
+}
+
+func TestHashContent(t *testing.T) {
+	hashed := hashContent("SELECT 1;")
+
+	assert.Equal(t, "sha256:", hashed[:7])
+	assert.Equal(t, hashed, hashContent(hashed), "Content which has already been hashed should be kept as it is")
+	assert.NotEqual(t, hashed, hashContent("SELECT 2;"))
+}
+
+func testAccCheckSharedCodeDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_shared_code" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/components/keboola.shared-code/configs/%s/rows/%s", rs.Primary.Attributes["configuration_id"], url.PathEscape(rs.Primary.ID)))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Shared code still exists")
+		}
+	}
+
+	return nil
+}
+
+const testSharedCodeBasic = `
+	resource "keboola_shared_code" "test_code" {
+		target_component_id = "keboola.python-transformation-v2"
+		code_id = "load_orders"
+		code = "import pandas\n"
+	}`
+
+const testSharedCodeUpdate = `
+	resource "keboola_shared_code" "test_code" {
+		target_component_id = "keboola.python-transformation-v2"
+		code_id = "load_orders"
+		code = "import pandas as pd\n"
+	}`
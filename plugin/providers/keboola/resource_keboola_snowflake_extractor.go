@@ -53,6 +53,8 @@ func resourceKeboolaSnowflakeExtractor() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizeSnowflakeCredentialsDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -63,6 +65,7 @@ func resourceKeboolaSnowflakeExtractor() *schema.Resource {
 				Optional: true,
 			},
 			"snowflake_db_parameters": &snowflakeDBParametersSchema,
+			"secret_hashes":           &secretHashesSchema,
 		},
 	}
 }
@@ -100,7 +103,11 @@ func resourceKeboolaSnowflakeExtractorCreate(d *schema.ResourceData, meta interf
 	d.SetPartial("name")
 	d.SetPartial("description")
 
-	snowflakeDatabaseCredentials := d.Get("snowflake_db_parameters").(map[string]interface{})
+	snowflakeDatabaseCredentials, err := encryptSnowflakeCredentials(d.Get("snowflake_db_parameters").(map[string]interface{}), "keboola.ex-db-snowflake", client)
+
+	if err != nil {
+		return err
+	}
 
 	err = createSnowflakeExtractorCredentialsConfiguration(snowflakeDatabaseCredentials, createdSnowflakeID, client)
 
@@ -114,7 +121,7 @@ func resourceKeboolaSnowflakeExtractorCreate(d *schema.ResourceData, meta interf
 
 	d.Partial(false)
 
-	return resourceKeboolaSnowflakeExtractorRead(d, meta)
+	return readAfterSendingSecrets(d, meta, snowflakeSecrets, resourceKeboolaSnowflakeExtractorRead)
 }
 
 func createSnowflakeExtractorCredentialsConfiguration(snowflakeCredentials map[string]interface{}, createdSnowflakeID string, client *KBCClient) error {
@@ -175,37 +182,7 @@ func resourceKeboolaSnowflakeExtractorRead(d *schema.ResourceData, meta interfac
 	d.Set("name", snowflakeExtractor.Name)
 	d.Set("description", snowflakeExtractor.Description)
 
-	dbParameters := make(map[string]interface{})
-
-	databaseCredentials := snowflakeExtractor.Configuration.Parameters.Database
-
-	if databaseCredentials.HostName != "" {
-		dbParameters["hostname"] = databaseCredentials.HostName
-	}
-
-	if databaseCredentials.Port != "" {
-		dbParameters["port"] = databaseCredentials.Port
-	}
-
-	if databaseCredentials.Database != "" {
-		dbParameters["database"] = databaseCredentials.Database
-	}
-
-	if databaseCredentials.Schema != "" {
-		dbParameters["schema"] = databaseCredentials.Schema
-	}
-
-	if databaseCredentials.Warehouse != "" {
-		dbParameters["warehouse"] = databaseCredentials.Warehouse
-	}
-
-	if databaseCredentials.Username != "" {
-		dbParameters["username"] = databaseCredentials.Username
-	}
-
-	if databaseCredentials.EncryptedPassword != "" {
-		dbParameters["hashed_password"] = databaseCredentials.EncryptedPassword
-	}
+	dbParameters := mapSnowflakeCredentialsToSchema(snowflakeExtractor.Configuration.Parameters.Database, d)
 
 	if len(dbParameters) > 0 {
 		d.Set("snowflake_db_parameters", dbParameters)
@@ -234,7 +211,11 @@ func resourceKeboolaSnowflakeExtractorUpdate(d *schema.ResourceData, meta interf
 		return err
 	}
 
-	snowflakeCredentials := d.Get("snowflake_db_parameters").(map[string]interface{})
+	snowflakeCredentials, err := snowflakeCredentialsToSave(d, "keboola.ex-db-snowflake", client)
+
+	if err != nil {
+		return err
+	}
 
 	snowflakeExtractor.Configuration.Parameters.Database = mapSnowflakeCredentialsToConfiguration(snowflakeCredentials)
 
@@ -258,7 +239,7 @@ func resourceKeboolaSnowflakeExtractorUpdate(d *schema.ResourceData, meta interf
 		return extractError(err, updateCredentialsResponse)
 	}
 
-	return resourceKeboolaSnowflakeExtractorRead(d, meta)
+	return readAfterSendingSecrets(d, meta, snowflakeSecrets, resourceKeboolaSnowflakeExtractorRead)
 }
 
 func resourceKeboolaSnowflakeExtractorDelete(d *schema.ResourceData, meta interface{}) error {
@@ -106,6 +106,8 @@ func resourceKeboolaSnowflakeWriter() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizeSnowflakeCredentialsDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -118,6 +120,7 @@ func resourceKeboolaSnowflakeWriter() *schema.Resource {
 				ForceNew: true,
 			},
 			"snowflake_db_parameters": &snowflakeDBParametersSchema,
+			"secret_hashes":           &secretHashesSchema,
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -158,16 +161,22 @@ func resourceKeboolaSnowflakeWriterCreate(d *schema.ResourceData, meta interface
 		}
 
 		snowflakeDatabaseCredentials = map[string]interface{}{
-			"hostname":        provisionedSnowflake.Credentials.HostName,
-			"port":            strconv.Itoa(provisionedSnowflake.Credentials.Port),
-			"database":        provisionedSnowflake.Credentials.Database,
-			"schema":          provisionedSnowflake.Credentials.Schema,
-			"warehouse":       provisionedSnowflake.Credentials.Warehouse,
-			"username":        provisionedSnowflake.Credentials.Username,
-			"hashed_password": provisionedSnowflake.Credentials.Password,
+			"hostname":  provisionedSnowflake.Credentials.HostName,
+			"port":      strconv.Itoa(provisionedSnowflake.Credentials.Port),
+			"database":  provisionedSnowflake.Credentials.Database,
+			"schema":    provisionedSnowflake.Credentials.Schema,
+			"warehouse": provisionedSnowflake.Credentials.Warehouse,
+			"username":  provisionedSnowflake.Credentials.Username,
+			"password":  provisionedSnowflake.Credentials.Password,
 		}
 	}
 
+	snowflakeDatabaseCredentials, err = encryptSnowflakeCredentials(snowflakeDatabaseCredentials, "keboola.wr-db-snowflake", client)
+
+	if err != nil {
+		return err
+	}
+
 	err = createSnowflakeCredentialsConfiguration(snowflakeDatabaseCredentials, createdSnowflakeID, client)
 
 	if err != nil {
@@ -180,7 +189,7 @@ func resourceKeboolaSnowflakeWriterCreate(d *schema.ResourceData, meta interface
 
 	d.Partial(false)
 
-	return resourceKeboolaSnowflakeWriterRead(d, meta)
+	return readAfterSendingSecrets(d, meta, snowflakeSecrets, resourceKeboolaSnowflakeWriterRead)
 }
 
 func createSnowflakeWriterConfiguration(name string, description string, client *KBCClient) (createdSnowflakeID string, err error) {
@@ -305,18 +314,8 @@ func resourceKeboolaSnowflakeWriterRead(d *schema.ResourceData, meta interface{}
 	d.Set("name", snowflakeWriter.Name)
 	d.Set("description", snowflakeWriter.Description)
 
-	if d.Get("provision_new_database") == false {
-		dbParameters := make(map[string]interface{})
-
-		databaseCredentials := snowflakeWriter.Configuration.Parameters.Database
-
-		dbParameters["hostname"] = databaseCredentials.HostName
-		dbParameters["port"] = databaseCredentials.Port
-		dbParameters["database"] = databaseCredentials.Database
-		dbParameters["schema"] = databaseCredentials.Schema
-		dbParameters["warehouse"] = databaseCredentials.Warehouse
-		dbParameters["username"] = databaseCredentials.Username
-		dbParameters["hashed_password"] = databaseCredentials.EncryptedPassword
+	if d.Get("provision_new_instance").(bool) == false {
+		dbParameters := mapSnowflakeCredentialsToSchema(snowflakeWriter.Configuration.Parameters.Database, d)
 
 		d.Set("snowflake_db_parameters", dbParameters)
 	}
@@ -344,9 +343,13 @@ func resourceKeboolaSnowflakeWriterUpdate(d *schema.ResourceData, meta interface
 		return err
 	}
 
-	snowflakeCredentials := d.Get("snowflake_db_parameters").(map[string]interface{})
-
 	if d.Get("provision_new_instance").(bool) == false {
+		snowflakeCredentials, err := snowflakeCredentialsToSave(d, "keboola.wr-db-snowflake", client)
+
+		if err != nil {
+			return err
+		}
+
 		snowflakeWriter.Configuration.Parameters.Database = mapSnowflakeCredentialsToConfiguration(snowflakeCredentials)
 	}
 
@@ -370,7 +373,7 @@ func resourceKeboolaSnowflakeWriterUpdate(d *schema.ResourceData, meta interface
 		return extractError(err, updateCredentialsResponse)
 	}
 
-	return resourceKeboolaSnowflakeWriterRead(d, meta)
+	return readAfterSendingSecrets(d, meta, snowflakeSecrets, resourceKeboolaSnowflakeWriterRead)
 }
 
 func resourceKeboolaSnowflakeWriterDelete(d *schema.ResourceData, meta interface{}) error {
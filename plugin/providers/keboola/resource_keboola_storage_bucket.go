@@ -3,9 +3,11 @@ package keboola
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
@@ -16,11 +18,16 @@ import (
 //StorageBucket is the data model for storage buckets within
 //the Keboola Storage API.
 type StorageBucket struct {
-	ID          string `json:"id,omitempty"`
-	Name        string `json:"name"`
-	Stage       string `json:"stage"`
-	Description string `json:"description"`
-	Backend     string `json:"backend,omitempty"`
+	ID            string  `json:"id,omitempty"`
+	Name          string  `json:"name"`
+	Stage         string  `json:"stage"`
+	DisplayName   string  `json:"displayName,omitempty"`
+	Description   string  `json:"description"`
+	Backend       string  `json:"backend,omitempty"`
+	IsReadOnly    bool    `json:"isReadOnly,omitempty"`
+	DataSizeBytes int     `json:"dataSizeBytes,omitempty"`
+	RowsCount     int     `json:"rowsCount,omitempty"`
+	Created       KBCTime `json:"created,omitempty"`
 }
 
 //endregion
@@ -29,9 +36,17 @@ func resourceKeboolaStorageBucket() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceKeboolaStorageBucketCreate,
 		Read:   resourceKeboolaStorageBucketRead,
+		Update: resourceKeboolaStorageBucketUpdate,
 		Delete: resourceKeboolaStorageBucketDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceKeboolaStorageBucketImport,
+		},
+		CustomizeDiff: resourceKeboolaStorageBucketCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -46,14 +61,21 @@ func resourceKeboolaStorageBucket() *schema.Resource {
 				ForceNew:     true,
 				ValidateFunc: validateStorageBucketStage,
 			},
+			//the description and display name are updated in place, as recreating the bucket drops all of its tables
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			//the default backend of the project is used when not set
 			"backend": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				ForceNew:     true,
 				ValidateFunc: validateStorageBucketBackend,
 			},
@@ -72,6 +94,49 @@ func resourceKeboolaStorageBucket() *schema.Resource {
 				Optional:     true,
 				ForceNew:     true,
 			},
+			//sharing is an alternative to the keboola_storage_bucket_sharing resource. When it is not set, the
+			//sharing of the bucket is only read, so that sharing by keboola_storage_bucket_sharing is left in place
+			"sharing": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStorageBucketSharing,
+			},
+			"target_project_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"target_users": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			//deleting a bucket which still contains tables fails, unless it is forced to delete the tables too
+			"force_delete": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: suppressUnsetDefault("false"),
+			},
+			"is_read_only": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"data_size_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"rows_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"created": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -83,7 +148,14 @@ func resourceKeboolaStorageBucketCreate(d *schema.ResourceData, meta interface{}
 	createBucketForm.Add("name", d.Get("name").(string))
 	createBucketForm.Add("stage", d.Get("stage").(string))
 	createBucketForm.Add("description", d.Get("description").(string))
-	createBucketForm.Add("backend", d.Get("backend").(string))
+
+	if backend := d.Get("backend").(string); backend != "" {
+		createBucketForm.Add("backend", backend)
+	}
+
+	if displayName := d.Get("display_name").(string); displayName != "" {
+		createBucketForm.Add("displayName", displayName)
+	}
 
 	if d.Get("is_linked").(bool) == true {
 		createBucketForm.Add("sourceProjectId", d.Get("source_project_id").(string))
@@ -110,21 +182,72 @@ func resourceKeboolaStorageBucketCreate(d *schema.ResourceData, meta interface{}
 
 	d.SetId(string(createBucketResult.ID))
 
+	if sharing := d.Get("sharing").(string); sharing != "" {
+		err := shareStorageBucket(client, d.Id(), sharing,
+			AsStringArray(d.Get("target_project_ids").(*schema.Set).List()),
+			AsStringArray(d.Get("target_users").(*schema.Set).List()),
+			d.Timeout(schema.TimeoutCreate))
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return resourceKeboolaStorageBucketRead(d, meta)
 }
 
+//parseStorageBucketID splits a bucket ID in to its stage and name, accepting the name both with
+//and without its c- prefix (e.g. in.c-main or in.main), and returns the ID as Keboola expects it.
+func parseStorageBucketID(bucketID string) (normalizedID string, stage string, name string, err error) {
+	parts := strings.SplitN(bucketID, ".", 2)
+
+	if len(parts) != 2 || parts[1] == "" || parts[1] == "c-" {
+		return "", "", "", fmt.Errorf("%q is not a valid Storage Bucket ID, expected the stage and name of the bucket (e.g. in.c-main)", bucketID)
+	}
+
+	if _, errors := validateStorageBucketStage(parts[0], "stage"); len(errors) > 0 {
+		return "", "", "", fmt.Errorf("%q is not a valid Storage Bucket ID: %v", bucketID, errors[0])
+	}
+
+	stage = parts[0]
+	name = strings.TrimPrefix(parts[1], "c-")
+
+	return fmt.Sprintf("%s.c-%s", stage, name), stage, name, nil
+}
+
+func resourceKeboolaStorageBucketImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	bucketID, stage, name, err := parseStorageBucketID(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(bucketID)
+	d.Set("stage", stage)
+	d.Set("name", name)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKeboolaStorageBucketCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return validateBucketSharingTargets(
+		d.Get("sharing").(string),
+		AsStringArray(d.Get("target_project_ids").(*schema.Set).List()),
+		AsStringArray(d.Get("target_users").(*schema.Set).List()))
+}
+
 func resourceKeboolaStorageBucketRead(d *schema.ResourceData, meta interface{}) error {
 	log.Println("[INFO] Reading Storage Buckets from Keboola.")
 
-	client := meta.(*KBCClient)
-	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", d.Id()))
-
 	if d.Id() == "" {
 		return nil
 	}
 
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", d.Id()))
+
 	if hasErrors(err, getResponse) {
-		if getResponse.StatusCode == 404 {
+		if err == nil && getResponse.StatusCode == 404 {
 			d.SetId("")
 			return nil
 		}
@@ -132,31 +255,123 @@ func resourceKeboolaStorageBucketRead(d *schema.ResourceData, meta interface{})
 		return extractError(err, getResponse)
 	}
 
+	bucketJSON, err := ioutil.ReadAll(getResponse.Body)
+
+	if err != nil {
+		return err
+	}
+
 	var storageBucket StorageBucket
+	var bucketSharing StorageBucketSharing
 
-	decoder := json.NewDecoder(getResponse.Body)
-	err = decoder.Decode(&storageBucket)
+	if err := json.Unmarshal(bucketJSON, &storageBucket); err != nil {
+		return err
+	}
 
-	if err != nil {
+	if err := json.Unmarshal(bucketJSON, &bucketSharing); err != nil {
 		return err
 	}
 
+	targetProjectIDs, targetUsers := mapBucketSharingTargets(bucketSharing, AsStringArray(d.Get("target_users").(*schema.Set).List()))
+
 	d.Set("id", storageBucket.ID)
 	d.Set("name", strings.TrimPrefix(storageBucket.Name, "c-"))
 	d.Set("stage", storageBucket.Stage)
 	d.Set("description", storageBucket.Description)
+	d.Set("display_name", storageBucket.DisplayName)
 	d.Set("backend", storageBucket.Backend)
+	d.Set("sharing", bucketSharing.Sharing)
+	d.Set("target_project_ids", targetProjectIDs)
+	d.Set("target_users", targetUsers)
+	d.Set("is_read_only", storageBucket.IsReadOnly)
+	d.Set("data_size_bytes", storageBucket.DataSizeBytes)
+	d.Set("rows_count", storageBucket.RowsCount)
+	d.Set("created", storageBucket.Created.RFC3339())
 
 	return nil
 }
 
+func resourceKeboolaStorageBucketUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Storage Bucket in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	if d.HasChange("description") || d.HasChange("display_name") {
+		updateBucketForm := url.Values{}
+		updateBucketForm.Add("description", d.Get("description").(string))
+
+		if displayName := d.Get("display_name").(string); displayName != "" {
+			updateBucketForm.Add("displayName", displayName)
+		}
+
+		updateBucketBuffer := buffer.FromForm(updateBucketForm)
+
+		updateResponse, err := client.PutToStorage(fmt.Sprintf("storage/buckets/%s", d.Id()), updateBucketBuffer)
+
+		if hasErrors(err, updateResponse) {
+			return extractError(err, updateResponse)
+		}
+	}
+
+	if d.HasChange("sharing") || d.HasChange("target_project_ids") || d.HasChange("target_users") {
+		if err := updateStorageBucketSharing(d, client); err != nil {
+			return err
+		}
+	}
+
+	return resourceKeboolaStorageBucketRead(d, meta)
+}
+
+//updateStorageBucketSharing shares, or changes the sharing of, the bucket, depending on whether it was
+//shared before. As sharing is only read when it is not set, removing it does not unshare the bucket;
+//a bucket which is to be unshared again should be shared with keboola_storage_bucket_sharing instead.
+func updateStorageBucketSharing(d *schema.ResourceData, client *KBCClient) error {
+	oldSharing, newSharing := d.GetChange("sharing")
+	targetProjectIDs := AsStringArray(d.Get("target_project_ids").(*schema.Set).List())
+	targetUsers := AsStringArray(d.Get("target_users").(*schema.Set).List())
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	switch {
+	case oldSharing.(string) == "":
+		log.Printf("[INFO] Sharing Storage Bucket in Keboola: %s", d.Id())
+		return shareStorageBucket(client, d.Id(), newSharing.(string), targetProjectIDs, targetUsers, timeout)
+	default:
+		log.Printf("[INFO] Changing sharing of Storage Bucket in Keboola: %s", d.Id())
+		return changeStorageBucketSharing(client, d.Id(), newSharing.(string), targetProjectIDs, targetUsers, timeout)
+	}
+}
+
 func resourceKeboolaStorageBucketDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Deleting Storage Bucket in Keboola: %s", d.Id())
 
 	client := meta.(*KBCClient)
+
+	if d.Get("force_delete").(bool) {
+		//large buckets can take a while to be deleted along with their tables, so they are deleted by a storage job
+		destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/buckets/%s?force=1&async=1", d.Id()))
+
+		if hasErrors(err, destroyResponse) {
+			return extractError(err, destroyResponse)
+		}
+
+		if err := waitForAcceptedStorageJob(client, destroyResponse, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return err
+		}
+
+		d.SetId("")
+
+		return nil
+	}
+
 	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/buckets/%s", d.Id()))
 
 	if hasErrors(err, destroyResponse) {
+		if err == nil && destroyResponse.StatusCode == 400 {
+			if tableCount, countErr := countStorageBucketTables(client, d.Id()); countErr == nil && tableCount > 0 {
+				return fmt.Errorf("Unable to delete Storage Bucket %s, as it still contains %d table(s). Remove the tables first, or set force_delete to delete them along with the bucket", d.Id(), tableCount)
+			}
+		}
+
 		return extractError(err, destroyResponse)
 	}
 
@@ -164,3 +379,22 @@ func resourceKeboolaStorageBucketDelete(d *schema.ResourceData, meta interface{}
 
 	return nil
 }
+
+func countStorageBucketTables(client *KBCClient, bucketID string) (int, error) {
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s/tables", bucketID))
+
+	if hasErrors(err, getResponse) {
+		return 0, extractError(err, getResponse)
+	}
+
+	var storageTables []StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTables)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(storageTables), nil
+}
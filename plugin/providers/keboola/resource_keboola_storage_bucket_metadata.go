@@ -0,0 +1,277 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+const defaultMetadataProvider = "user"
+
+func resourceKeboolaStorageBucketMetadata() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageBucketMetadataCreate,
+		Read:   resourceKeboolaStorageBucketMetadataRead,
+		Update: resourceKeboolaStorageBucketMetadataUpdate,
+		Delete: resourceKeboolaStorageBucketMetadataDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"metadata_provider": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  defaultMetadataProvider,
+			},
+			"metadata": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKeboolaStorageBucketMetadataCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Bucket Metadata in Keboola.")
+
+	bucketID := d.Get("bucket_id").(string)
+
+	client := meta.(*KBCClient)
+	err := setMetadata(client, fmt.Sprintf("storage/buckets/%s/metadata", bucketID), d.Get("metadata_provider").(string), d.Get("metadata").(map[string]interface{}))
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(bucketID)
+
+	return resourceKeboolaStorageBucketMetadataRead(d, meta)
+}
+
+func resourceKeboolaStorageBucketMetadataRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Bucket Metadata from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	metadata, statusCode, err := getMetadata(client, fmt.Sprintf("storage/buckets/%s/metadata", d.Id()))
+
+	if err != nil {
+		if statusCode == 404 {
+			log.Printf("[WARN] Storage Bucket %s no longer exists, removing its metadata from state.", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	provider := d.Get("metadata_provider").(string)
+	if provider == "" {
+		provider = defaultMetadataProvider
+	}
+
+	d.Set("bucket_id", d.Id())
+	d.Set("metadata_provider", provider)
+	d.Set("metadata", withoutUnmanagedSystemMetadata(mapMetadataEntriesToSchema(metadata, provider), d.Get("metadata").(map[string]interface{})))
+
+	return nil
+}
+
+func resourceKeboolaStorageBucketMetadataUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Storage Bucket Metadata in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	endpoint := fmt.Sprintf("storage/buckets/%s/metadata", d.Id())
+	provider := d.Get("metadata_provider").(string)
+
+	if d.HasChange("metadata") {
+		oldMetadata, newMetadata := d.GetChange("metadata")
+		removedKeys := except(mapKeys(oldMetadata.(map[string]interface{})), mapKeys(newMetadata.(map[string]interface{})))
+
+		if err := deleteMetadata(client, endpoint, provider, removedKeys); err != nil {
+			return err
+		}
+
+		if err := setMetadata(client, endpoint, provider, changedMetadata(oldMetadata.(map[string]interface{}), newMetadata.(map[string]interface{}))); err != nil {
+			return err
+		}
+	}
+
+	return resourceKeboolaStorageBucketMetadataRead(d, meta)
+}
+
+func resourceKeboolaStorageBucketMetadataDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Bucket Metadata in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	keys := mapKeys(d.Get("metadata").(map[string]interface{}))
+
+	err := deleteMetadata(client, fmt.Sprintf("storage/buckets/%s/metadata", d.Id()), d.Get("metadata_provider").(string), keys)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+//getMetadata reads all of the metadata entries from a metadata endpoint (of a bucket, table or column),
+//along with the status code of the response, so that callers can tell when the owner no longer exists.
+func getMetadata(client *KBCClient, endpoint string) ([]MetadataEntry, int, error) {
+	getResponse, err := client.GetFromStorage(endpoint)
+
+	if hasErrors(err, getResponse) {
+		if err == nil {
+			return nil, getResponse.StatusCode, extractError(err, getResponse)
+		}
+
+		return nil, 0, err
+	}
+
+	var metadata []MetadataEntry
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&metadata)
+
+	if err != nil {
+		return nil, getResponse.StatusCode, err
+	}
+
+	return metadata, getResponse.StatusCode, nil
+}
+
+//setMetadata creates or updates the given metadata entries on a metadata endpoint, leaving any other
+//entries (including those of other providers) as they are.
+func setMetadata(client *KBCClient, endpoint string, provider string, metadata map[string]interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	metadataForm := url.Values{}
+	metadataForm.Add("provider", provider)
+
+	for index, key := range mapKeys(metadata) {
+		metadataForm.Add(fmt.Sprintf("metadata[%d][key]", index), key)
+		metadataForm.Add(fmt.Sprintf("metadata[%d][value]", index), metadata[key].(string))
+	}
+
+	metadataBuffer := buffer.FromForm(metadataForm)
+
+	setResponse, err := client.PostToStorage(endpoint, metadataBuffer)
+
+	if hasErrors(err, setResponse) {
+		return extractError(err, setResponse)
+	}
+
+	return nil
+}
+
+//deleteMetadata removes the entries with the given keys, which were set by the given provider, from a
+//metadata endpoint. Entries which have already been removed are ignored.
+func deleteMetadata(client *KBCClient, endpoint string, provider string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	metadata, statusCode, err := getMetadata(client, endpoint)
+
+	if err != nil {
+		if statusCode == 404 {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range metadata {
+		if entry.Provider != provider || !containsString(keys, entry.Key) {
+			continue
+		}
+
+		log.Printf("[DEBUG] Removing metadata %s from %s", entry.Key, endpoint)
+
+		deleteResponse, err := client.DeleteFromStorage(fmt.Sprintf("%s/%s", endpoint, entry.ID))
+
+		if hasErrors(err, deleteResponse) {
+			if err == nil && deleteResponse.StatusCode == 404 {
+				continue
+			}
+
+			return extractError(err, deleteResponse)
+		}
+	}
+
+	return nil
+}
+
+//mapMetadataEntriesToSchema maps the metadata entries set by the given provider, ignoring those set by
+//Keboola itself or by components (such as KBC.createdBy.*).
+func mapMetadataEntriesToSchema(metadata []MetadataEntry, provider string) map[string]interface{} {
+	mappedMetadata := make(map[string]interface{})
+
+	for _, entry := range metadata {
+		if entry.Provider == provider {
+			mappedMetadata[entry.Key] = entry.Value
+		}
+	}
+
+	return mappedMetadata
+}
+
+//withoutUnmanagedSystemMetadata removes the KBC.* entries (such as KBC.description, which the Keboola UI
+//sets as the user provider) which are not already managed, so that they are not removed by the next apply.
+func withoutUnmanagedSystemMetadata(metadata map[string]interface{}, managedMetadata map[string]interface{}) map[string]interface{} {
+	for key := range metadata {
+		if _, managed := managedMetadata[key]; strings.HasPrefix(key, "KBC.") && !managed {
+			delete(metadata, key)
+		}
+	}
+
+	return metadata
+}
+
+//changedMetadata returns the entries which have been added, or whose value has changed, so that only
+//those are sent to Keboola.
+func changedMetadata(oldMetadata map[string]interface{}, newMetadata map[string]interface{}) map[string]interface{} {
+	changed := make(map[string]interface{})
+
+	for key, value := range newMetadata {
+		if oldValue, ok := oldMetadata[key]; !ok || oldValue != value {
+			changed[key] = value
+		}
+	}
+
+	return changed
+}
+
+//mapKeys returns the keys of a map held in the ResourceData, in a stable order.
+func mapKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
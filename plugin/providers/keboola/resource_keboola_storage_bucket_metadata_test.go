@@ -0,0 +1,191 @@
+package keboola
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageBucketMetadata_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageBucketMetadataDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageBucketMetadataBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket_metadata.test_metadata", "metadata.%", "2"),
+					resource.TestCheckResourceAttr("keboola_storage_bucket_metadata.test_metadata", "metadata.owner", "data-team"),
+				),
+			},
+			{
+				Config: testStorageBucketMetadataUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket_metadata.test_metadata", "metadata.%", "1"),
+					resource.TestCheckResourceAttr("keboola_storage_bucket_metadata.test_metadata", "metadata.owner", "analytics-team"),
+				),
+			},
+			{
+				ResourceName:      "keboola_storage_bucket_metadata.test_metadata",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMapMetadataEntriesToSchemaIgnoresOtherProviders(t *testing.T) {
+	metadata := []MetadataEntry{
+		{ID: "1", Key: "KBC.createdBy.component.id", Value: "keboola.ex-db-mysql", Provider: "system"},
+		{ID: "2", Key: "owner", Value: "data-team", Provider: "user"},
+		{ID: "3", Key: "classification", Value: "internal", Provider: "catalog"},
+	}
+
+	assert.Equal(t, map[string]interface{}{"owner": "data-team"}, mapMetadataEntriesToSchema(metadata, "user"))
+	assert.Equal(t, map[string]interface{}{"classification": "internal"}, mapMetadataEntriesToSchema(metadata, "catalog"))
+}
+
+func TestStorageBucketMetadataDeleteOnlyRemovesOwnEntries(t *testing.T) {
+	var deletedPaths []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/buckets/in.c-bucket/metadata":
+			w.Write([]byte(`[
+				{ "id": "1", "key": "KBC.createdBy.component.id", "value": "keboola.ex-db-mysql", "provider": "system" },
+				{ "id": "2", "key": "owner", "value": "data-team", "provider": "user" },
+				{ "id": "3", "key": "owner", "value": "someone-else", "provider": "catalog" },
+				{ "id": "4", "key": "unmanaged", "value": "set in the UI", "provider": "user" }
+			]`))
+		case r.Method == "DELETE":
+			deletedPaths = append(deletedPaths, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucketMetadata().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"metadata": map[string]interface{}{
+			"owner": "data-team",
+		},
+	})
+	d.SetId("in.c-bucket")
+
+	err := resourceKeboolaStorageBucketMetadataDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/v2/storage/buckets/in.c-bucket/metadata/2"}, deletedPaths, "Only managed entries of the metadata provider should be removed")
+	assert.Equal(t, "", d.Id())
+}
+
+func TestStorageBucketMetadataReadLeavesUnmanagedSystemKeys(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{ "id": "1", "key": "KBC.description", "value": "Set in the UI", "provider": "user" },
+			{ "id": "2", "key": "KBC.sourceSystem", "value": "salesforce", "provider": "user" },
+			{ "id": "3", "key": "team", "value": "data", "provider": "user" }
+		]`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucketMetadata().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"metadata": map[string]interface{}{
+			"team":             "data",
+			"KBC.sourceSystem": "salesforce",
+		},
+	})
+	d.SetId("in.c-bucket")
+
+	err := resourceKeboolaStorageBucketMetadataRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "data", "KBC.sourceSystem": "salesforce"}, d.Get("metadata"), "KBC.* keys should only be read when they are managed")
+}
+
+func TestChangedMetadataOnlyIncludesChangedKeys(t *testing.T) {
+	oldMetadata := map[string]interface{}{"team": "data", "pii": "false", "source-system": "salesforce"}
+	newMetadata := map[string]interface{}{"team": "data", "pii": "true", "owner": "jane"}
+
+	assert.Equal(t, map[string]interface{}{"pii": "true", "owner": "jane"}, changedMetadata(oldMetadata, newMetadata), "Only added and changed keys should be sent")
+	assert.Equal(t, newMetadata, changedMetadata(nil, newMetadata), "All keys should be sent when there is no previous metadata")
+}
+
+func TestStorageBucketMetadataReadRemovesDeletedBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucketMetadata().Schema, map[string]interface{}{})
+	d.SetId("in.c-deleted_bucket")
+
+	err := resourceKeboolaStorageBucketMetadataRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "Metadata of a bucket that no longer exists should be removed from state")
+}
+
+func testAccCheckStorageBucketMetadataDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_bucket_metadata" {
+			continue
+		}
+
+		metadata, _, err := getMetadata(client, fmt.Sprintf("storage/buckets/%s/metadata", rs.Primary.ID))
+
+		if err != nil {
+			continue
+		}
+
+		if managedMetadata := mapMetadataEntriesToSchema(metadata, rs.Primary.Attributes["metadata_provider"]); len(managedMetadata) > 0 {
+			return fmt.Errorf("Storage bucket still has metadata %v", managedMetadata)
+		}
+	}
+
+	return nil
+}
+
+const testStorageBucketMetadataBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket_metadata" "test_metadata" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+
+		metadata {
+			owner = "data-team"
+			classification = "internal"
+		}
+	}`
+
+const testStorageBucketMetadataUpdate = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket_metadata" "test_metadata" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+
+		metadata {
+			owner = "analytics-team"
+		}
+	}`
@@ -0,0 +1,293 @@
+package keboola
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//StorageBucketSharingProject is a project that a bucket has been shared with.
+type StorageBucketSharingProject struct {
+	ID json.Number `json:"id"`
+}
+
+//StorageBucketSharingUser is a user that a bucket has been shared with.
+type StorageBucketSharingUser struct {
+	ID    json.Number `json:"id"`
+	Email string      `json:"email"`
+}
+
+//StorageBucketLink is a bucket in another project which links to a shared bucket.
+type StorageBucketLink struct {
+	ID      string `json:"id"`
+	Project struct {
+		ID   json.Number `json:"id"`
+		Name string      `json:"name"`
+	} `json:"project"`
+}
+
+//StorageBucketSharing is the sharing state of a bucket within
+//the Keboola Storage API.
+type StorageBucketSharing struct {
+	ID                string `json:"id"`
+	Sharing           string `json:"sharing"`
+	SharingParameters struct {
+		Projects []StorageBucketSharingProject `json:"projects"`
+		Users    []StorageBucketSharingUser    `json:"users"`
+	} `json:"sharingParameters"`
+	LinkedBy []StorageBucketLink `json:"linkedBy"`
+}
+
+//endregion
+
+func resourceKeboolaStorageBucketSharing() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageBucketSharingCreate,
+		Read:   resourceKeboolaStorageBucketSharingRead,
+		Delete: resourceKeboolaStorageBucketSharingDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: resourceKeboolaStorageBucketSharingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"sharing": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateStorageBucketSharing,
+			},
+			"target_project_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"target_users": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceKeboolaStorageBucketSharingCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return validateBucketSharingTargets(
+		d.Get("sharing").(string),
+		AsStringArray(d.Get("target_project_ids").(*schema.Set).List()),
+		AsStringArray(d.Get("target_users").(*schema.Set).List()))
+}
+
+//validateBucketSharingTargets checks that target projects/users are given when (and only when)
+//the sharing mode shares the bucket with specific projects or users.
+func validateBucketSharingTargets(sharing string, targetProjectIDs []string, targetUsers []string) error {
+	if sharing == "specific-projects" && len(targetProjectIDs) == 0 {
+		return fmt.Errorf("target_project_ids must be set when sharing is %q", sharing)
+	}
+
+	if sharing == "specific-users" && len(targetUsers) == 0 {
+		return fmt.Errorf("target_users must be set when sharing is %q", sharing)
+	}
+
+	if sharing != "specific-projects" && len(targetProjectIDs) > 0 {
+		return fmt.Errorf("target_project_ids can only be set when sharing is %q", "specific-projects")
+	}
+
+	if sharing != "specific-users" && len(targetUsers) > 0 {
+		return fmt.Errorf("target_users can only be set when sharing is %q", "specific-users")
+	}
+
+	return nil
+}
+
+func resourceKeboolaStorageBucketSharingCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Sharing Storage Bucket in Keboola.")
+
+	bucketID := d.Get("bucket_id").(string)
+
+	client := meta.(*KBCClient)
+	err := shareStorageBucket(client, bucketID, d.Get("sharing").(string),
+		AsStringArray(d.Get("target_project_ids").(*schema.Set).List()),
+		AsStringArray(d.Get("target_users").(*schema.Set).List()),
+		d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(bucketID)
+
+	return resourceKeboolaStorageBucketSharingRead(d, meta)
+}
+
+func resourceKeboolaStorageBucketSharingRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Bucket Sharing from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var bucketSharing StorageBucketSharing
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&bucketSharing)
+
+	if err != nil {
+		return err
+	}
+
+	if bucketSharing.Sharing == "" {
+		log.Printf("[WARN] Storage Bucket %s is no longer shared, removing from state.", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	targetProjectIDs, targetUsers := mapBucketSharingTargets(bucketSharing, AsStringArray(d.Get("target_users").(*schema.Set).List()))
+
+	d.Set("bucket_id", d.Id())
+	d.Set("sharing", bucketSharing.Sharing)
+	d.Set("target_project_ids", targetProjectIDs)
+	d.Set("target_users", targetUsers)
+
+	return nil
+}
+
+func resourceKeboolaStorageBucketSharingDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Unsharing Storage Bucket in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	err := unshareStorageBucket(client, d.Id(), d.Timeout(schema.TimeoutDelete))
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+//shareStorageBucket shares a bucket which is not yet shared.
+func shareStorageBucket(client *KBCClient, bucketID string, sharing string, targetProjectIDs []string, targetUsers []string, timeout time.Duration) error {
+	shareResponse, err := client.PostToStorage(fmt.Sprintf("storage/buckets/%s/share", bucketID), bucketSharingBuffer(sharing, targetProjectIDs, targetUsers))
+
+	if hasErrors(err, shareResponse) {
+		return extractError(err, shareResponse)
+	}
+
+	return waitForAcceptedStorageJob(client, shareResponse, timeout)
+}
+
+//changeStorageBucketSharing changes how (or with whom) an already shared bucket is shared, without
+//unsharing it first, which would break the buckets linked to it.
+func changeStorageBucketSharing(client *KBCClient, bucketID string, sharing string, targetProjectIDs []string, targetUsers []string, timeout time.Duration) error {
+	changeResponse, err := client.PutToStorage(fmt.Sprintf("storage/buckets/%s/share", bucketID), bucketSharingBuffer(sharing, targetProjectIDs, targetUsers))
+
+	if hasErrors(err, changeResponse) {
+		return extractError(err, changeResponse)
+	}
+
+	return waitForAcceptedStorageJob(client, changeResponse, timeout)
+}
+
+func bucketSharingBuffer(sharing string, targetProjectIDs []string, targetUsers []string) *bytes.Buffer {
+	shareBucketForm := url.Values{}
+	shareBucketForm.Add("sharing", sharing)
+
+	for _, projectID := range targetProjectIDs {
+		shareBucketForm.Add("targetProjectIds[]", projectID)
+	}
+
+	for _, user := range targetUsers {
+		shareBucketForm.Add("targetUsers[]", user)
+	}
+
+	return buffer.FromForm(shareBucketForm)
+}
+
+//unshareStorageBucket stops sharing a bucket. Keboola refuses to unshare a bucket which other projects
+//have linked, so those projects are looked up first to explain what has to be unlinked.
+func unshareStorageBucket(client *KBCClient, bucketID string, timeout time.Duration) error {
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s?include=linkedBuckets", bucketID))
+
+	if err == nil && getResponse.StatusCode == 200 {
+		var bucketSharing StorageBucketSharing
+
+		if json.NewDecoder(getResponse.Body).Decode(&bucketSharing) == nil && len(bucketSharing.LinkedBy) > 0 {
+			linkedBy := make([]string, 0, len(bucketSharing.LinkedBy))
+			for _, link := range bucketSharing.LinkedBy {
+				linkedBy = append(linkedBy, fmt.Sprintf("%s (project %s)", link.ID, link.Project.ID))
+			}
+
+			return fmt.Errorf("Unable to unshare Storage Bucket %s, as it is linked by %s. The linked buckets must be removed from those projects first",
+				bucketID, strings.Join(linkedBy, ", "))
+		}
+	}
+
+	unshareResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/buckets/%s/share", bucketID))
+
+	if hasErrors(err, unshareResponse) {
+		return extractError(err, unshareResponse)
+	}
+
+	return waitForAcceptedStorageJob(client, unshareResponse, timeout)
+}
+
+//mapBucketSharingTargets maps the projects and users that a bucket has been shared with. Users can be
+//targeted by either their ID or email, so each user is mapped to whichever of the two is in configuredUsers,
+//falling back to their email.
+func mapBucketSharingTargets(bucketSharing StorageBucketSharing, configuredUsers []string) ([]string, []string) {
+	targetProjectIDs := make([]string, 0, len(bucketSharing.SharingParameters.Projects))
+	for _, project := range bucketSharing.SharingParameters.Projects {
+		targetProjectIDs = append(targetProjectIDs, project.ID.String())
+	}
+
+	isConfigured := make(map[string]bool, len(configuredUsers))
+	for _, user := range configuredUsers {
+		isConfigured[user] = true
+	}
+
+	targetUsers := make([]string, 0, len(bucketSharing.SharingParameters.Users))
+	for _, user := range bucketSharing.SharingParameters.Users {
+		if userID := user.ID.String(); isConfigured[userID] {
+			targetUsers = append(targetUsers, userID)
+		} else {
+			targetUsers = append(targetUsers, user.Email)
+		}
+	}
+
+	return targetProjectIDs, targetUsers
+}
@@ -0,0 +1,150 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageBucketSharing_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageBucketSharingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageBucketSharingBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket_sharing.test_sharing", "sharing", "organization-project"),
+				),
+			},
+			{
+				//the bucket does not set sharing, so should not plan to undo the sharing of the bucket
+				Config:   testStorageBucketSharingBasic,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "keboola_storage_bucket_sharing.test_sharing",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestValidateBucketSharingTargets(t *testing.T) {
+	assert.NoError(t, validateBucketSharingTargets("organization", nil, nil))
+	assert.NoError(t, validateBucketSharingTargets("specific-projects", []string{"123"}, nil))
+	assert.NoError(t, validateBucketSharingTargets("specific-users", nil, []string{"someone@example.com"}))
+	assert.Error(t, validateBucketSharingTargets("specific-projects", nil, nil), "Sharing with specific projects requires target projects")
+	assert.Error(t, validateBucketSharingTargets("specific-users", nil, nil), "Sharing with specific users requires target users")
+	assert.Error(t, validateBucketSharingTargets("organization", []string{"123"}, nil), "Target projects should only be allowed when sharing with specific projects")
+}
+
+func TestMapBucketSharingTargetsKeepsConfiguredUserIDs(t *testing.T) {
+	var bucketSharing StorageBucketSharing
+
+	err := json.Unmarshal([]byte(`{
+		"sharing": "specific-users",
+		"sharingParameters": {
+			"projects": [],
+			"users": [ { "id": 12, "email": "first@example.com" }, { "id": 34, "email": "second@example.com" } ]
+		}
+	}`), &bucketSharing)
+	assert.NoError(t, err)
+
+	_, targetUsers := mapBucketSharingTargets(bucketSharing, []string{"12", "second@example.com"})
+	assert.Equal(t, []string{"12", "second@example.com"}, targetUsers, "Users should be read back as they were configured")
+
+	_, targetUsers = mapBucketSharingTargets(bucketSharing, nil)
+	assert.Equal(t, []string{"first@example.com", "second@example.com"}, targetUsers, "Users which were not configured should be read as their email")
+}
+
+func TestUnshareStorageBucketExplainsLinkedBuckets(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			assert.Equal(t, "linkedBuckets", r.URL.Query().Get("include"))
+			w.Write([]byte(`{
+				"id": "out.c-curated",
+				"sharing": "organization-project",
+				"linkedBy": [ { "id": "in.c-shared-curated", "project": { "id": 456, "name": "Reporting" } } ]
+			}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	err := unshareStorageBucket(client, "out.c-curated", time.Minute)
+
+	assert.EqualError(t, err, "Unable to unshare Storage Bucket out.c-curated, as it is linked by in.c-shared-curated (project 456). The linked buckets must be removed from those projects first")
+}
+
+func TestUnshareStorageBucket(t *testing.T) {
+	unshared := false
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			w.Write([]byte(`{ "id": "out.c-curated", "sharing": "organization", "linkedBy": [] }`))
+		case r.Method == "DELETE" && r.URL.Path == "/v2/storage/buckets/out.c-curated/share":
+			unshared = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	err := unshareStorageBucket(client, "out.c-curated", time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, unshared, "A bucket which has not been linked should be unshared")
+}
+
+func testAccCheckStorageBucketSharingDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_bucket_sharing" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", rs.Primary.ID))
+
+		if err != nil || getResp.StatusCode != 200 {
+			continue
+		}
+
+		var bucketSharing StorageBucketSharing
+
+		decoder := json.NewDecoder(getResp.Body)
+		err = decoder.Decode(&bucketSharing)
+
+		if err == nil && bucketSharing.Sharing != "" {
+			return fmt.Errorf("Storage bucket is still shared")
+		}
+	}
+
+	return nil
+}
+
+const testStorageBucketSharingBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket_sharing" "test_sharing" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		sharing = "organization-project"
+	}`
@@ -3,10 +3,14 @@ package keboola
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccStorageBucket_Basic(t *testing.T) {
@@ -29,6 +33,339 @@ func TestAccStorageBucket_Basic(t *testing.T) {
 	})
 }
 
+func TestAccStorageBucket_Import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageBucketBasic,
+			},
+			{
+				ResourceName:            "keboola_storage_bucket.test_bucket",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_delete"},
+			},
+			{
+				ResourceName:            "keboola_storage_bucket.test_bucket",
+				ImportState:             true,
+				ImportStateId:           "out.test_bucket_name",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_delete"},
+			},
+		},
+	})
+}
+
+func TestParseStorageBucketID(t *testing.T) {
+	for _, bucketID := range []string{"in.c-main", "in.main"} {
+		normalizedID, stage, name, err := parseStorageBucketID(bucketID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "in.c-main", normalizedID, "%s should be normalized to the ID used by Keboola", bucketID)
+		assert.Equal(t, "in", stage)
+		assert.Equal(t, "main", name)
+	}
+
+	for _, bucketID := range []string{"main", "in.", "in.c-", "staging.c-main"} {
+		_, _, _, err := parseStorageBucketID(bucketID)
+
+		assert.Error(t, err, "%s should not be a valid bucket ID", bucketID)
+	}
+}
+
+func TestAccStorageBucket_UpdateDescription(t *testing.T) {
+	var tableCreated string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageBucketWithTable, "test description"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreated("keboola_storage_table.test_table", &tableCreated),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testStorageBucketWithTable, "updated description"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket.test_bucket", "description", "updated description"),
+					testAccCheckStorageTableCreatedUnchanged("keboola_storage_table.test_table", &tableCreated),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageBucketUpdateSendsDescription(t *testing.T) {
+	var updateBucketForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "/v2/storage/buckets/in.c-bucket", r.URL.Path)
+			r.ParseForm()
+			updateBucketForm = r.PostForm
+			w.Write([]byte(`{ "id": "in.c-bucket" }`))
+		case "GET":
+			w.Write([]byte(`{ "id": "in.c-bucket", "name": "c-bucket", "stage": "in", "displayName": "Orders", "description": "updated description" }`))
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"name":         "bucket",
+		"stage":        "in",
+		"description":  "updated description",
+		"display_name": "Orders",
+	})
+	d.SetId("in.c-bucket")
+
+	err := resourceKeboolaStorageBucketUpdate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "updated description", updateBucketForm.Get("description"))
+	assert.Equal(t, "Orders", updateBucketForm.Get("displayName"))
+	assert.Equal(t, "in.c-bucket", d.Id(), "The bucket should be updated in place")
+}
+
+func TestStorageBucketCreateOmitsUnsetBackend(t *testing.T) {
+	var createBucketForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		createBucketForm = r.PostForm
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "error": "Bucket already exists" }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"name":  "bucket",
+		"stage": "in",
+	})
+
+	resourceKeboolaStorageBucketCreate(d, client)
+
+	assert.Equal(t, "bucket", createBucketForm.Get("name"))
+	assert.NotContains(t, createBucketForm, "backend", "The default backend of the project should be used when no backend is set")
+}
+
+func TestStorageBucketReadSetsBackend(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "in.c-bucket", "name": "c-bucket", "stage": "in", "backend": "redshift" }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{})
+	d.SetId("in.c-bucket")
+
+	err := resourceKeboolaStorageBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "redshift", d.Get("backend"))
+	assert.True(t, resourceKeboolaStorageBucket().Schema["backend"].Computed, "An unset backend should take the default backend of the project without a diff")
+}
+
+func TestStorageBucketDecodesStatistics(t *testing.T) {
+	getBucketResponse := `{
+		"uri": "https://connection.keboola.com/v2/storage/buckets/in.c-orders",
+		"id": "in.c-orders",
+		"name": "c-orders",
+		"displayName": "orders",
+		"stage": "in",
+		"description": "Orders from the shop",
+		"tables": "https://connection.keboola.com/v2/storage/buckets/in.c-orders/tables",
+		"created": "2019-07-01T09:15:00+0200",
+		"lastChangeDate": "2019-07-20T10:00:05+0200",
+		"isReadOnly": true,
+		"dataSizeBytes": 56320,
+		"rowsCount": 1234,
+		"isMaintenance": false,
+		"backend": "snowflake",
+		"sharing": null,
+		"attributes": []
+	}`
+
+	var storageBucket StorageBucket
+	err := json.Unmarshal([]byte(getBucketResponse), &storageBucket)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "snowflake", storageBucket.Backend, "backend should be decoded")
+	assert.Equal(t, true, storageBucket.IsReadOnly, "isReadOnly should be decoded")
+	assert.Equal(t, 56320, storageBucket.DataSizeBytes, "dataSizeBytes should be decoded")
+	assert.Equal(t, 1234, storageBucket.RowsCount, "rowsCount should be decoded")
+	assert.Equal(t, "2019-07-01T07:15:00Z", storageBucket.Created.RFC3339(), "created should be decoded")
+}
+
+func TestStorageBucketReadSetsStatistics(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "in.c-orders", "name": "c-orders", "stage": "in", "created": "2019-07-01T09:15:00+0200",
+			"isReadOnly": true, "dataSizeBytes": 56320, "rowsCount": 1234, "backend": "snowflake" }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{})
+	d.SetId("in.c-orders")
+
+	err := resourceKeboolaStorageBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, true, d.Get("is_read_only"))
+	assert.Equal(t, 56320, d.Get("data_size_bytes"))
+	assert.Equal(t, 1234, d.Get("rows_count"))
+	assert.Equal(t, "2019-07-01T07:15:00Z", d.Get("created"), "created should be normalised to RFC3339")
+}
+
+func TestAccStorageBucket_Sharing(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageBucketSharing, `sharing = "organization-project"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket.test_bucket", "sharing", "organization-project"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testStorageBucketSharing, `sharing = "organization"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket.test_bucket", "sharing", "organization"),
+				),
+			},
+			{
+				//without sharing, the sharing of the bucket is only read, so that it does not undo keboola_storage_bucket_sharing
+				Config: fmt.Sprintf(testStorageBucketSharing, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_bucket.test_bucket", "sharing", "organization"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageBucketReadDetectsSharing(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "out.c-curated",
+			"name": "c-curated",
+			"stage": "out",
+			"sharing": "specific-projects",
+			"sharingParameters": { "projects": [ { "id": 456 } ], "users": [] }
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{})
+	d.SetId("out.c-curated")
+
+	err := resourceKeboolaStorageBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "specific-projects", d.Get("sharing"))
+	assert.Equal(t, []interface{}{"456"}, d.Get("target_project_ids").(*schema.Set).List())
+}
+
+func TestStorageBucketReadRemovesDeletedBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{})
+	d.SetId("in.c-deleted_bucket")
+
+	err := resourceKeboolaStorageBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A bucket that no longer exists should be removed from state")
+}
+
+func TestStorageBucketDeleteReportsRemainingTables(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/v2/storage/buckets/in.c-orders":
+			assert.Equal(t, "", r.URL.Query().Get("force"), "The bucket should only be forced to delete its tables when force_delete is set")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{ "error": "Only empty buckets can be deleted", "code": "buckets.deleteNotEmpty" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/buckets/in.c-orders/tables":
+			w.Write([]byte(`[ { "id": "in.c-orders.orders" }, { "id": "in.c-orders.order_items" } ]`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{})
+	d.SetId("in.c-orders")
+
+	err := resourceKeboolaStorageBucketDelete(d, client)
+
+	assert.EqualError(t, err, "Unable to delete Storage Bucket in.c-orders, as it still contains 2 table(s). Remove the tables first, or set force_delete to delete them along with the bucket")
+	assert.Equal(t, "in.c-orders", d.Id())
+}
+
+func TestStorageBucketForceDeleteWaitsForJob(t *testing.T) {
+	jobPolled := false
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/v2/storage/buckets/in.c-orders":
+			assert.Equal(t, "1", r.URL.Query().Get("force"))
+			assert.Equal(t, "1", r.URL.Query().Get("async"))
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/jobs/12345":
+			jobPolled = true
+			w.Write([]byte(`{ "id": 12345, "status": "success" }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageBucket().Schema, map[string]interface{}{
+		"force_delete": true,
+	})
+	d.SetId("in.c-orders")
+
+	err := resourceKeboolaStorageBucketDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.True(t, jobPolled, "The delete should wait for the storage job deleting the bucket")
+	assert.Equal(t, "", d.Id())
+}
+
+func TestValidateStorageBucketStage(t *testing.T) {
+	for _, stage := range []string{"in", "out", "sys"} {
+		_, errors := validateStorageBucketStage(stage, "stage")
+		assert.Empty(t, errors, "%s should be a valid stage", stage)
+	}
+
+	_, errors := validateStorageBucketStage("staging", "stage")
+	assert.NotEmpty(t, errors)
+}
+
+func TestValidateStorageBucketBackend(t *testing.T) {
+	for _, backend := range []string{"", "snowflake", "redshift", "bigquery"} {
+		_, errors := validateStorageBucketBackend(backend, "backend")
+		assert.Empty(t, errors, "%s should be a valid backend", backend)
+	}
+
+	_, errors := validateStorageBucketBackend("postgres", "backend")
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Error(), `"backend" must be set to one of snowflake, redshift, bigquery`)
+}
+
 func testAccCheckStorageBucketExists(n string, bucket *StorageBucket) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -71,6 +408,36 @@ func testAccCheckStorageBucketExists(n string, bucket *StorageBucket) resource.T
 	}
 }
 
+func testAccCheckStorageTableCreated(n string, created *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		*created = rs.Primary.Attributes["created"]
+
+		return nil
+	}
+}
+
+func testAccCheckStorageTableCreatedUnchanged(n string, created *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.Attributes["created"] != *created {
+			return fmt.Errorf("Storage table was recreated (created changed from %s to %s)", *created, rs.Primary.Attributes["created"])
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckStorageBucketDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*KBCClient)
 
@@ -98,3 +465,26 @@ resource "keboola_storage_bucket" "test_bucket" {
 	stage = "out"
 	backend = "snowflake"
 }`
+
+const testStorageBucketWithTable = `
+resource "keboola_storage_bucket" "test_bucket" {
+	name = "test_bucket_name"
+	description = "%s"
+	stage = "out"
+	backend = "snowflake"
+}
+
+resource "keboola_storage_table" "test_table" {
+	bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+	name = "test_table"
+	columns = [ "id", "amount" ]
+}`
+
+const testStorageBucketSharing = `
+resource "keboola_storage_bucket" "test_bucket" {
+	name = "test_bucket_name"
+	description = "test description"
+	stage = "out"
+	backend = "snowflake"
+	%s
+}`
@@ -1,16 +1,25 @@
 package keboola
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
 )
@@ -20,14 +29,26 @@ import (
 //StorageTable is the data model for Storage Tables within
 //the Keboola Storage API.
 type StorageTable struct {
-	ID             string   `json:"id,omitempty"`
-	Name           string   `json:"name"`
-	Delimiter      string   `json:"delimiter"`
-	Enclosure      string   `json:"enclosure,omitempty"`
-	Transactional  bool     `json:"transactional,omitempty"`
-	Columns        []string `json:"columns"`
-	PrimaryKey     []string `json:"primaryKey"`
-	IndexedColumns []string `json:"indexedColumns"`
+	ID              string   `json:"id,omitempty"`
+	Name            string   `json:"name"`
+	Delimiter       string   `json:"delimiter"`
+	Enclosure       string   `json:"enclosure,omitempty"`
+	Transactional   bool     `json:"transactional,omitempty"`
+	Columns         []string `json:"columns"`
+	PrimaryKey      []string `json:"primaryKey"`
+	IndexedColumns  []string `json:"indexedColumns"`
+	DistributionKey []string `json:"distributionKey"`
+	RowsCount       int      `json:"rowsCount"`
+	DataSizeBytes   int      `json:"dataSizeBytes"`
+	Created         KBCTime  `json:"created"`
+	LastImportDate  KBCTime  `json:"lastImportDate"`
+	LastChangeDate  KBCTime  `json:"lastChangeDate"`
+	IsTyped         bool     `json:"isTyped"`
+	Definition      *struct {
+		Columns []TypedColumn `json:"columns"`
+	} `json:"definition,omitempty"`
+	Metadata       []MetadataEntry            `json:"metadata,omitempty"`
+	ColumnMetadata map[string][]MetadataEntry `json:"columnMetadata,omitempty"`
 }
 
 //UploadFileResult contains the id of the CSV file uploaded to AWS S3.
@@ -37,11 +58,26 @@ type UploadFileResult struct {
 
 //endregion
 
+const defaultStorageTableDelimiter = ","
+const defaultStorageTableEnclosure = "\""
+
 func resourceKeboolaStorageTable() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceKeboolaStorageTableCreate,
 		Read:   resourceKeboolaStorageTableRead,
+		Update: resourceKeboolaStorageTableUpdate,
 		Delete: resourceKeboolaStorageTableDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKeboolaStorageTableImport,
+		},
+
+		CustomizeDiff: resourceKeboolaStorageTableCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"bucket_id": {
@@ -55,21 +91,38 @@ func resourceKeboolaStorageTable() *schema.Resource {
 				ForceNew: true,
 			},
 			"delimiter": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          defaultStorageTableDelimiter,
+				ValidateFunc:     validateDelimiter,
+				StateFunc:        unescapeDelimiter,
+				DiffSuppressFunc: suppressUnsetDefault(defaultStorageTableDelimiter),
 			},
+			//an explicitly empty enclosure loads the data without any enclosure, rather than with the default
 			"enclosure": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          defaultStorageTableEnclosure,
+				ValidateFunc:     validateEnclosure,
+				DiffSuppressFunc: suppressUnsetDefault(defaultStorageTableEnclosure),
 			},
 			"transactional": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				ForceNew: true,
 			},
+			//the order of the primary key is kept, as it is the order of the columns of a composite key
 			"primary_key": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			//the order of the distribution key is kept, as is for the primary key
+			"distribution_key": {
 				Type:     schema.TypeList,
 				Optional: true,
 				ForceNew: true,
@@ -77,18 +130,77 @@ func resourceKeboolaStorageTable() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			//columns are a set, as Keboola returns them in their physical order (which differs from the
+			//declared order once columns are added), and reordering them does not change the table
 			"columns": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				ForceNew: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
-			"indexed_columns": {
-				Type:     schema.TypeList,
+			"data_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateFileExists,
+				ConflictsWith: []string{"snapshot_id"},
+			},
+			"snapshot_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"columns", "data_file"},
+			},
+			"column_definition": &columnDefinitionSchema,
+			"column_metadata":   &columnMetadataSchema,
+			"data_file_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			//when false, the table is created without waiting for its data to be loaded, so a failed
+			//load is not reported by the apply, but only noticed (and the table removed from state)
+			//when the table is next read
+			"wait_for_load": {
+				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
+				Default:  true,
+			},
+			//the ID of a load job which has not been waited for, until the job has finished
+			"load_job_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"incremental": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"rows_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"data_size_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"created": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_import_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_change_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"indexed_columns": {
+				Type:             schema.TypeList,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressReorderedList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -102,47 +214,58 @@ func resourceKeboolaStorageTableCreate(d *schema.ResourceData, meta interface{})
 	log.Println("[INFO] Creating Storage Table in Keboola.")
 
 	client := meta.(*KBCClient)
-	columns := AsStringArray(d.Get("columns").(*schema.Set).List())
-
-	uploadFileBuffer := &bytes.Buffer{}
-	uploadFileRequestWriter := multipart.NewWriter(uploadFileBuffer)
-	uploadFileRequestWriter.SetBoundary("----terraform-provider-keboola----")
-	uploadFileRequestWriter.WriteField("name", "from-text-input.csv")
-	uploadFileRequestWriter.WriteField("data", strings.Join(columns, ","))
-	uploadFileRequestWriter.Close()
 
-	uploadResponse, err := client.PostToFileImport("upload-file", uploadFileBuffer)
+	//an explicitly empty enclosure is not part of the diff (it is no different from the empty state), so
+	//it is set here to tell it apart from an enclosure which has never been set when the table is read.
+	d.Set("enclosure", d.Get("enclosure").(string))
 
-	if hasErrors(err, uploadResponse) {
-		return extractError(err, uploadResponse)
+	if d.Get("transactional").(bool) {
+		if err := checkTransactionalSupported(client, d.Get("bucket_id").(string)); err != nil {
+			return err
+		}
 	}
 
-	var uploadResult UploadFileResult
-
-	uploadResponseDecoder := json.NewDecoder(uploadResponse.Body)
-	err = uploadResponseDecoder.Decode(&uploadResult)
-
-	if err != nil {
-		return err
+	if columnDefinitions := d.Get("column_definition").([]interface{}); len(columnDefinitions) > 0 {
+		return createTypedStorageTable(d, meta, columnDefinitions)
 	}
 
-	fileID := uploadResult.ID
+	if d.Get("snapshot_id").(string) == "" && d.Get("data_file").(string) == "" {
+		return createEmptyStorageTable(d, meta)
+	}
 
 	loadTableForm := url.Values{}
 	loadTableForm.Add("name", d.Get("name").(string))
-	loadTableForm.Add("primaryKey", strings.Join(AsStringArray(d.Get("primary_key").([]interface{})), ","))
-	loadTableForm.Add("dataFileId", strconv.Itoa(fileID))
 
-	if d.Get("delimiter") != "" {
-		loadTableForm.Add("delimiter", d.Get("delimiter").(string))
+	var uploadedFileID int
+
+	if snapshotID := d.Get("snapshot_id").(string); snapshotID != "" {
+		loadTableForm.Add("snapshotId", snapshotID)
 	} else {
-		loadTableForm.Add("delimiter", ",")
+		fileID, err := uploadStorageTableData(d, client)
+
+		if err != nil {
+			return err
+		}
+
+		uploadedFileID = fileID
+
+		loadTableForm.Add("primaryKey", strings.Join(AsStringArray(d.Get("primary_key").([]interface{})), ","))
+		loadTableForm.Add("dataFileId", strconv.Itoa(fileID))
+
+		delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+		loadTableForm.Add("delimiter", delimiter)
+		loadTableForm.Add("enclosure", enclosure)
 	}
 
-	if d.Get("enclosure") != "" {
-		loadTableForm.Add("enclosure", d.Get("enclosure").(string))
-	} else {
-		loadTableForm.Add("enclosure", "\"")
+	if d.Get("transactional").(bool) {
+		loadTableForm.Add("transactional", "1")
+	}
+
+	distributionKey := AsStringArray(d.Get("distribution_key").([]interface{}))
+
+	if len(distributionKey) > 0 {
+		loadTableForm.Add("distributionKey", strings.Join(distributionKey, ","))
 	}
 
 	loadTableBuffer := buffer.FromForm(loadTableForm)
@@ -152,6 +275,10 @@ func resourceKeboolaStorageTableCreate(d *schema.ResourceData, meta interface{})
 	loadTableResponse, err := client.PostToStorage(fmt.Sprintf("storage/buckets/%s/tables-async", bucketID), loadTableBuffer)
 
 	if hasErrors(err, loadTableResponse) {
+		if err == nil && loadTableResponse.StatusCode == 400 && len(distributionKey) > 0 {
+			return distributionKeyError(client, bucketID, distributionKey, extractError(err, loadTableResponse))
+		}
+
 		return extractError(err, loadTableResponse)
 	}
 
@@ -164,100 +291,1072 @@ func resourceKeboolaStorageTableCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
-	tableLoadStatus := "waiting"
+	if !d.Get("wait_for_load").(bool) {
+		//the table is named by the load, so its ID is known before the job has created it. The
+		//uploaded file is still needed by the job, and column metadata can only be added to the
+		//table once it exists, so is added by the next apply.
+		d.SetId(fmt.Sprintf("%s.%s", bucketID, d.Get("name").(string)))
+		d.Set("load_job_id", strconv.Itoa(loadTableResult.ID))
 
-	var tableLoadStatusResult StorageJobStatus
+		return setDataFileHash(d)
+	}
 
-	for tableLoadStatus != "success" && tableLoadStatus != "error" {
-		jobStatusResponse, err := client.GetFromStorage(fmt.Sprintf("storage/jobs/%v", loadTableResult.ID))
+	tableLoadStatusResult, err := client.WaitForStorageJob(client.stopContext(), loadTableResult.ID, d.Timeout(schema.TimeoutCreate))
 
-		if hasErrors(err, jobStatusResponse) {
-			return extractError(err, jobStatusResponse)
+	if err != nil {
+		//The load job may still create the table after an interruption or timeout, so the table is
+		//kept in state (and so tainted), to be cleaned up and recreated by the next apply. A job
+		//that has failed will not create the table, so nothing is kept in state.
+		if _, failed := err.(*StorageJobError); !failed {
+			d.SetId(fmt.Sprintf("%s.%s", bucketID, d.Get("name").(string)))
 		}
 
-		jobStatusDecoder := json.NewDecoder(jobStatusResponse.Body)
-		err = jobStatusDecoder.Decode(&tableLoadStatusResult)
+		return err
+	}
 
-		if err != nil {
-			return err
-		}
+	if uploadedFileID != 0 {
+		deleteUploadedFile(client, uploadedFileID)
+	}
 
-		time.Sleep(250 * time.Millisecond)
-		tableLoadStatus = tableLoadStatusResult.Status
+	if err := setDataFileHash(d); err != nil {
+		return err
 	}
 
-	d.SetId(tableLoadStatusResult.Results.ID)
+	d.SetId(string(tableLoadStatusResult.Results.ID))
+
+	if err := updateColumnMetadata(d, client); err != nil {
+		return err
+	}
 
 	return resourceKeboolaStorageTableRead(d, meta)
 }
 
-func except(first []string, second []string) []string {
-	var result []string
+//setDataFileHash keeps the hash of the data_file a table was loaded from, if it was loaded from one.
+func setDataFileHash(d *schema.ResourceData) error {
+	dataFile := d.Get("data_file").(string)
 
-	for _, elementInFirst := range first {
-		elementFound := false
-		for _, elementInSecond := range second {
-			if elementInFirst == elementInSecond {
-				elementFound = true
-				break
-			}
+	if dataFile == "" {
+		return nil
+	}
+
+	dataFileHash, err := hashDataFile(dataFile)
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("data_file_hash", dataFileHash)
+
+	return nil
+}
+
+//createTypedStorageTable creates a table with native column datatypes from its definition,
+//rather than from the header row of a CSV file.
+func createTypedStorageTable(d *schema.ResourceData, meta interface{}, columnDefinitions []interface{}) error {
+	client := meta.(*KBCClient)
+	bucketID := d.Get("bucket_id").(string)
+
+	tableDefinition := TableDefinition{
+		Name:             d.Get("name").(string),
+		PrimaryKeysNames: AsStringArray(d.Get("primary_key").([]interface{})),
+		Columns:          mapColumnDefinitionSchemaToModel(columnDefinitions),
+	}
+
+	if distributionKey := AsStringArray(d.Get("distribution_key").([]interface{})); len(distributionKey) > 0 {
+		tableDefinition.Distribution = &TableDistribution{Type: "HASH", DistributionColumnsNames: distributionKey}
+	}
+
+	tableDefinitionJSON, err := json.Marshal(tableDefinition)
+
+	if err != nil {
+		return err
+	}
+
+	createTableResponse, err := client.PostJSONToStorage(fmt.Sprintf("storage/buckets/%s/tables-definition", bucketID), bytes.NewBuffer(tableDefinitionJSON))
+
+	if hasErrors(err, createTableResponse) {
+		if err == nil && createTableResponse.StatusCode == 400 && tableDefinition.Distribution != nil {
+			return distributionKeyError(client, bucketID, tableDefinition.Distribution.DistributionColumnsNames, extractError(err, createTableResponse))
 		}
 
-		if !elementFound {
-			result = append(result, elementInFirst)
+		return extractError(err, createTableResponse)
+	}
+
+	var createTableResult UploadFileResult
+
+	createTableDecoder := json.NewDecoder(createTableResponse.Body)
+	err = createTableDecoder.Decode(&createTableResult)
+
+	if err != nil {
+		return err
+	}
+
+	createTableStatusResult, err := client.WaitForStorageJob(client.stopContext(), createTableResult.ID, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		if _, failed := err.(*StorageJobError); !failed {
+			d.SetId(fmt.Sprintf("%s.%s", bucketID, tableDefinition.Name))
 		}
+
+		return err
 	}
 
-	return result
+	d.SetId(string(createTableStatusResult.Results.ID))
+
+	if err := updateColumnMetadata(d, client); err != nil {
+		return err
+	}
+
+	return resourceKeboolaStorageTableRead(d, meta)
 }
 
-func resourceKeboolaStorageTableRead(d *schema.ResourceData, meta interface{}) error {
-	log.Println("[INFO] Reading Storage Table from Keboola.")
+//createEmptyStorageTable creates a table with just the declared columns, sending the header row
+//directly to the synchronous table endpoint, rather than uploading it to file storage and waiting
+//for an asynchronous load job.
+func createEmptyStorageTable(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*KBCClient)
+	bucketID := d.Get("bucket_id").(string)
 
-	if d.Id() == "" {
-		return nil
+	delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+	createTableForm := url.Values{}
+	createTableForm.Add("name", d.Get("name").(string))
+	createTableForm.Add("dataString", strings.Join(AsStringArray(d.Get("columns").(*schema.Set).List()), delimiter))
+	createTableForm.Add("primaryKey", strings.Join(AsStringArray(d.Get("primary_key").([]interface{})), ","))
+	createTableForm.Add("delimiter", delimiter)
+	createTableForm.Add("enclosure", enclosure)
+
+	if d.Get("transactional").(bool) {
+		createTableForm.Add("transactional", "1")
 	}
 
-	client := meta.(*KBCClient)
-	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+	distributionKey := AsStringArray(d.Get("distribution_key").([]interface{}))
 
-	if hasErrors(err, getResponse) {
-		if getResponse.StatusCode == 404 {
-			d.SetId("")
-			return nil
+	if len(distributionKey) > 0 {
+		createTableForm.Add("distributionKey", strings.Join(distributionKey, ","))
+	}
+
+	createTableBuffer := buffer.FromForm(createTableForm)
+
+	createTableResponse, err := client.PostToStorage(fmt.Sprintf("storage/buckets/%s/tables", bucketID), createTableBuffer)
+
+	if hasErrors(err, createTableResponse) {
+		if err == nil && createTableResponse.StatusCode == 400 && len(distributionKey) > 0 {
+			return distributionKeyError(client, bucketID, distributionKey, extractError(err, createTableResponse))
 		}
 
-		return extractError(err, getResponse)
+		return extractError(err, createTableResponse)
 	}
 
 	var storageTable StorageTable
 
-	decoder := json.NewDecoder(getResponse.Body)
-	err = decoder.Decode(&storageTable)
+	createTableDecoder := json.NewDecoder(createTableResponse.Body)
+	err = createTableDecoder.Decode(&storageTable)
 
 	if err != nil {
 		return err
 	}
 
-	d.Set("name", storageTable.Name)
-	d.Set("delimiter", storageTable.Delimiter)
-	d.Set("enclosure", storageTable.Enclosure)
-	d.Set("transactional", storageTable.Transactional)
-	d.Set("primary_key", storageTable.PrimaryKey)
-	d.Set("indexed_columns", storageTable.IndexedColumns)
-	d.Set("columns", storageTable.Columns)
+	d.SetId(storageTable.ID)
 
-	return nil
+	if err := updateColumnMetadata(d, client); err != nil {
+		return err
+	}
+
+	return resourceKeboolaStorageTableRead(d, meta)
 }
 
-func resourceKeboolaStorageTableDelete(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("[INFO] Deleting Storage Table in Keboola: %s", d.Id())
+//uploadStorageTableData uploads the data_file of a new table to the File Import API, and returns
+//the ID of the file.
+func uploadStorageTableData(d *schema.ResourceData, client *KBCClient) (int, error) {
+	columns := AsStringArray(d.Get("columns").(*schema.Set).List())
 
-	client := meta.(*KBCClient)
-	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+	uploadFileBuffer := &bytes.Buffer{}
+	uploadFileRequestWriter := multipart.NewWriter(uploadFileBuffer)
+	uploadFileRequestWriter.SetBoundary("----terraform-provider-keboola----")
 
-	if hasErrors(err, destroyResponse) {
-		return extractError(err, destroyResponse)
+	dataFile := d.Get("data_file").(string)
+
+	delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+	err := validateDataFileHeader(dataFile, delimiter, enclosure, columns)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = writeDataFile(uploadFileRequestWriter, dataFile)
+
+	if err != nil {
+		return 0, err
+	}
+
+	uploadFileRequestWriter.Close()
+
+	return uploadToFileImport(client, uploadFileBuffer)
+}
+
+//uploadToFileImport uploads a multipart request containing table data to the Keboola
+//File Import API, and returns the ID of the uploaded file.
+func uploadToFileImport(client *KBCClient, uploadFileBuffer *bytes.Buffer) (int, error) {
+	uploadResponse, err := client.PostToFileImport("upload-file", uploadFileBuffer)
+
+	if hasErrors(err, uploadResponse) {
+		return 0, extractError(err, uploadResponse)
+	}
+
+	var uploadResult UploadFileResult
+
+	uploadResponseDecoder := json.NewDecoder(uploadResponse.Body)
+	err = uploadResponseDecoder.Decode(&uploadResult)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return uploadResult.ID, nil
+}
+
+//deleteUploadedFile removes a file which was uploaded to load a table from the project's file storage,
+//once the load has completed. Failing to remove it only leaves the file behind, so is not an error.
+func deleteUploadedFile(client *KBCClient, fileID int) {
+	deleteResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/files/%d", fileID))
+
+	if hasErrors(err, deleteResponse) {
+		log.Printf("[WARN] Unable to delete uploaded file %d from file storage: %v", fileID, extractError(err, deleteResponse))
+	}
+}
+
+//hashDataFile calculates a SHA-256 hash of the contents of a data file, so that
+//changes to the file contents can be detected between plans.
+func hashDataFile(dataFile string) (string, error) {
+	file, err := os.Open(dataFile)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+//writeDataFile copies the contents of a local CSV file into the multipart request
+//used to upload table data through the Keboola File Import API.
+func writeDataFile(writer *multipart.Writer, dataFile string) error {
+	file, err := os.Open(dataFile)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return writeData(writer, filepath.Base(dataFile), file)
+}
+
+//writeData copies CSV data, under the given file name, into the multipart request used to
+//upload table data through the Keboola File Import API.
+func writeData(writer *multipart.Writer, name string, data io.Reader) error {
+	writer.WriteField("name", name)
+
+	part, err := writer.CreateFormFile("data", name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, data)
+
+	return err
+}
+
+//readDataFileHeader reads the header row of a local CSV file, and parses it in to
+//column names using the given delimiter and enclosure. Column names may contain the
+//delimiter when they are enclosed.
+func readDataFileHeader(dataFile string, delimiter string, enclosure string) ([]string, error) {
+	file, err := os.Open(dataFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	header, err := bufio.NewReader(file).ReadString('\n')
+
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	header = strings.TrimRight(header, "\r\n")
+
+	if header == "" {
+		return nil, fmt.Errorf("data_file %q does not contain a header row", dataFile)
+	}
+
+	if enclosure == "" {
+		return strings.Split(header, delimiter), nil
+	}
+
+	//encoding/csv only understands double quotes as the enclosure, so any other enclosure is
+	//swapped with double quotes while parsing, and swapped back in the column names.
+	swapEnclosure := strings.NewReplacer(enclosure, "\"", "\"", enclosure)
+
+	reader := csv.NewReader(strings.NewReader(swapEnclosure.Replace(header)))
+	reader.Comma = []rune(swapEnclosure.Replace(delimiter))[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	headerColumns, err := reader.Read()
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the header row of data_file %q: %s", dataFile, err)
+	}
+
+	for i, column := range headerColumns {
+		headerColumns[i] = swapEnclosure.Replace(column)
+	}
+
+	return headerColumns, nil
+}
+
+//validateDataFileHeader checks that the header row of a data file, when read using the
+//configured delimiter and enclosure, matches the columns declared on the table.
+func validateDataFileHeader(dataFile string, delimiter string, enclosure string, columns []string) error {
+	headerColumns, err := readDataFileHeader(dataFile, delimiter, enclosure)
+
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	missingColumns := except(columns, headerColumns)
+	unexpectedColumns := except(headerColumns, columns)
+
+	if len(missingColumns) > 0 || len(unexpectedColumns) > 0 {
+		return fmt.Errorf("header of data_file %q read as %v using delimiter %q and enclosure %q, which does not match columns %v (check that the delimiter and enclosure match the file)",
+			dataFile, headerColumns, delimiter, enclosure, columns)
+	}
+
+	return nil
+}
+
+func except(first []string, second []string) []string {
+	var result []string
+
+	for _, elementInFirst := range first {
+		elementFound := false
+		for _, elementInSecond := range second {
+			if elementInFirst == elementInSecond {
+				elementFound = true
+				break
+			}
+		}
+
+		if !elementFound {
+			result = append(result, elementInFirst)
+		}
+	}
+
+	return result
+}
+
+//parseStorageTableID splits a full Storage Table ID (e.g. in.c-bucket.table) in to its bucket ID
+//and table name. Table names cannot contain dots, so the name is always the last segment.
+func parseStorageTableID(tableID string) (bucketID string, name string, err error) {
+	bucketSeparator := strings.LastIndex(tableID, ".")
+
+	if bucketSeparator < 1 || bucketSeparator == len(tableID)-1 {
+		return "", "", fmt.Errorf("%q is not a valid Storage Table ID, expected the full table ID (e.g. in.c-bucket.table)", tableID)
+	}
+
+	return tableID[:bucketSeparator], tableID[bucketSeparator+1:], nil
+}
+
+func resourceKeboolaStorageTableImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	bucketID, name, err := parseStorageTableID(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("bucket_id", bucketID)
+	d.Set("name", name)
+
+	//these only affect how data is loaded, so are not read from Keboola, and are imported with their defaults
+	d.Set("wait_for_load", true)
+	d.Set("incremental", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+//storageTableCSVSettings returns the delimiter and enclosure to send to Keboola. An unset delimiter
+//falls back to the default, whereas an empty enclosure is sent as it is, meaning no enclosure.
+func storageTableCSVSettings(delimiter string, enclosure string) (string, string) {
+	if delimiter == "" {
+		delimiter = defaultStorageTableDelimiter
+	}
+
+	return unescapeDelimiter(delimiter), enclosure
+}
+
+//readCSVSetting decides which delimiter/enclosure value to keep in state after a read. The API
+//may omit the value, in which case the current state is kept (even when empty, for no enclosure),
+//or the default when nothing is in state yet (e.g. on import, or for tables created before the
+//attribute had a default).
+func readCSVSetting(current string, isSet bool, returned string, defaultValue string) string {
+	if returned != "" {
+		return returned
+	}
+
+	if current != "" || isSet {
+		return current
+	}
+
+	return defaultValue
+}
+
+func resourceKeboolaStorageTableRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+
+	if d.Get("load_job_id").(string) != "" {
+		loaded, err := reconcileStorageTableLoad(d, client)
+
+		if err != nil || !loaded {
+			return err
+		}
+	}
+
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTable StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTable)
+
+	if err != nil {
+		return err
+	}
+
+	if bucketID, _, err := parseStorageTableID(d.Id()); err == nil {
+		d.Set("bucket_id", bucketID)
+	}
+
+	delimiter, delimiterSet := d.GetOkExists("delimiter")
+	enclosure, enclosureSet := d.GetOkExists("enclosure")
+
+	d.Set("name", storageTable.Name)
+	d.Set("delimiter", readCSVSetting(delimiter.(string), delimiterSet, storageTable.Delimiter, defaultStorageTableDelimiter))
+	d.Set("enclosure", readCSVSetting(enclosure.(string), enclosureSet, storageTable.Enclosure, defaultStorageTableEnclosure))
+	d.Set("transactional", storageTable.Transactional)
+	d.Set("primary_key", storageTable.PrimaryKey)
+	d.Set("indexed_columns", storageTable.IndexedColumns)
+	d.Set("columns", storageTable.Columns)
+	d.Set("distribution_key", storageTable.DistributionKey)
+
+	if storageTable.IsTyped && storageTable.Definition != nil {
+		d.Set("column_definition", mapColumnDefinitionModelToSchema(storageTable.Definition.Columns))
+	}
+
+	d.Set("column_metadata", mapColumnMetadataModelToSchema(storageTable.ColumnMetadata, columnMetadataColumns(d.Get("column_metadata").(*schema.Set).List())))
+
+	d.Set("rows_count", storageTable.RowsCount)
+	d.Set("data_size_bytes", storageTable.DataSizeBytes)
+	d.Set("created", storageTable.Created.RFC3339())
+	d.Set("last_import_date", storageTable.LastImportDate.RFC3339())
+	d.Set("last_change_date", storageTable.LastChangeDate.RFC3339())
+
+	return nil
+}
+
+//reconcileStorageTableLoad checks on the load job of a table which was created without waiting for it,
+//returning whether the table has been loaded. Until the job has finished the table is left as it is in
+//state, and if the job has failed the table (which it will not have created) is removed from state.
+func reconcileStorageTableLoad(d *schema.ResourceData, client *KBCClient) (bool, error) {
+	jobID, err := strconv.Atoi(d.Get("load_job_id").(string))
+
+	if err != nil {
+		return false, err
+	}
+
+	loadJob, err := getStorageJob(client, jobID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if !isTerminalJobStatus(loadJob.Status) {
+		log.Printf("[INFO] Storage Table %s is still being loaded by job %d (status: %s).", d.Id(), jobID, loadJob.Status)
+		return false, nil
+	}
+
+	if loadJob.Status != "success" {
+		log.Printf("[WARN] Storage Table %s was not loaded: %v", d.Id(), &StorageJobError{
+			JobID:       jobID,
+			Status:      loadJob.Status,
+			Message:     loadJob.Error.Message,
+			ExceptionID: loadJob.Error.ExceptionID,
+		})
+
+		d.SetId("")
+		return false, nil
+	}
+
+	d.Set("load_job_id", "")
+
+	return true, nil
+}
+
+//resourceKeboolaStorageTableCustomizeDiff only forces a new table when columns have been removed,
+//as Keboola can add columns to an existing table without losing any data, but cannot drop them.
+//All added columns are then added by a single update, and as columns are a set, reordering them
+//is not a change at all.
+func resourceKeboolaStorageTableCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("delimiter").(string) == d.Get("enclosure").(string) {
+		return fmt.Errorf("delimiter and enclosure must be different characters, both are %q", d.Get("delimiter").(string))
+	}
+
+	if err := planDataFileHash(d); err != nil {
+		return err
+	}
+
+	//columns are inferred before the key columns are checked, so that they are also checked
+	//against the header of the data file when the columns are not declared
+	if d.Id() == "" {
+		if err := inferColumnsFromDataFile(d); err != nil {
+			return err
+		}
+	}
+
+	if err := validateStorageTableKeyColumns(d); err != nil {
+		return err
+	}
+
+	if d.Id() == "" || !d.HasChange("columns") {
+		return nil
+	}
+
+	oldColumns, newColumns := d.GetChange("columns")
+	removedColumns := except(AsStringArray(oldColumns.(*schema.Set).List()), AsStringArray(newColumns.(*schema.Set).List()))
+	addedColumns := except(AsStringArray(newColumns.(*schema.Set).List()), AsStringArray(oldColumns.(*schema.Set).List()))
+
+	if len(removedColumns) > 0 {
+		log.Printf("[WARN] Storage Table %s will be recreated, and all of its data lost, as columns %v have been removed and Keboola cannot drop columns from an existing table (added columns %v will be part of the new table)", d.Id(), removedColumns, addedColumns)
+		return d.ForceNew("columns")
+	}
+
+	log.Printf("[DEBUG] Columns %v will be added to Storage Table %s in place, without recreating it", addedColumns, d.Id())
+
+	return nil
+}
+
+//validateStorageTableKeyColumns checks at plan time that the primary key, indexed columns, distribution key
+//and column metadata only refer to declared columns, rather than failing after the data has been uploaded.
+func validateStorageTableKeyColumns(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("columns") {
+		return nil
+	}
+
+	columns := AsStringArray(d.Get("columns").(*schema.Set).List())
+
+	if len(columns) == 0 && d.NewValueKnown("column_definition") {
+		for _, column := range mapColumnDefinitionSchemaToModel(d.Get("column_definition").([]interface{})) {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	//columns come from a snapshot, or from a data file which could not be read yet, so there is nothing to check against
+	if len(columns) == 0 {
+		return nil
+	}
+
+	for _, attribute := range []string{"primary_key", "indexed_columns", "distribution_key"} {
+		if !d.NewValueKnown(attribute) {
+			continue
+		}
+
+		if err := checkColumnsDeclared(attribute, AsStringArray(d.Get(attribute).([]interface{})), columns); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("column_metadata") {
+		return checkColumnsDeclared("column_metadata", columnMetadataColumns(d.Get("column_metadata").(*schema.Set).List()), columns)
+	}
+
+	return nil
+}
+
+//checkColumnsDeclared returns an error naming the first of the given columns which is not one of the
+//declared columns. Columns whose names are not yet known are skipped.
+func checkColumnsDeclared(attribute string, keyColumns []string, declaredColumns []string) error {
+	for _, column := range keyColumns {
+		if column == "" || column == config.UnknownVariableValue {
+			continue
+		}
+
+		if !containsString(declaredColumns, column) {
+			return fmt.Errorf("%s contains column %q, which is not one of the table's columns %v", attribute, column, declaredColumns)
+		}
+	}
+
+	return nil
+}
+
+//planDataFileHash hashes the contents of the data file at plan time, so that changing
+//the contents of the file (and not just its path) causes the data to be reloaded.
+func planDataFileHash(d *schema.ResourceDiff) error {
+	dataFile := d.Get("data_file").(string)
+
+	if dataFile == "" {
+		return nil
+	}
+
+	dataFileHash, err := hashDataFile(dataFile)
+
+	if err != nil {
+		return err
+	}
+
+	if dataFileHash != d.Get("data_file_hash").(string) {
+		return d.SetNew("data_file_hash", dataFileHash)
+	}
+
+	return nil
+}
+
+//inferColumnsFromDataFile plans the columns of a new table from the header row of its data
+//file, when the columns have not been explicitly declared.
+func inferColumnsFromDataFile(d *schema.ResourceDiff) error {
+	dataFile := d.Get("data_file").(string)
+
+	if dataFile == "" || d.Get("columns").(*schema.Set).Len() > 0 {
+		return nil
+	}
+
+	delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+	headerColumns, err := readDataFileHeader(dataFile, delimiter, enclosure)
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Inferred columns %v from the header of data_file %s", headerColumns, dataFile)
+
+	return d.SetNew("columns", headerColumns)
+}
+
+func resourceKeboolaStorageTableUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Storage Table in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+
+	if d.HasChange("columns") {
+		oldColumns, newColumns := d.GetChange("columns")
+		addedColumns := except(AsStringArray(newColumns.(*schema.Set).List()), AsStringArray(oldColumns.(*schema.Set).List()))
+
+		var headerColumns []string
+
+		if dataFile := d.Get("data_file").(string); dataFile != "" {
+			delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+			headerColumns, _ = readDataFileHeader(dataFile, delimiter, enclosure)
+		}
+
+		addedColumns = orderColumns(addedColumns, headerColumns)
+
+		for i, column := range addedColumns {
+			log.Printf("[DEBUG] Adding column '%s' to Storage Table %s", column, d.Id())
+
+			addColumnForm := url.Values{}
+			addColumnForm.Add("name", column)
+
+			addColumnBuffer := buffer.FromForm(addColumnForm)
+
+			addColumnResponse, err := client.PostToStorage(fmt.Sprintf("storage/tables/%s/columns", d.Id()), addColumnBuffer)
+
+			if hasErrors(err, addColumnResponse) {
+				//only the columns which were added are kept in state, for the rest to be added by the next apply
+				d.Set("columns", append(AsStringArray(oldColumns.(*schema.Set).List()), addedColumns[:i]...))
+				return extractError(err, addColumnResponse)
+			}
+		}
+	}
+
+	if d.HasChange("primary_key") {
+		oldPrimaryKey, newPrimaryKey := d.GetChange("primary_key")
+
+		err := updatePrimaryKey(client, d.Id(), AsStringArray(oldPrimaryKey.([]interface{})), AsStringArray(newPrimaryKey.([]interface{})))
+
+		if err != nil {
+			//the new primary key was not created, so it is not kept in state, to be tried again by the next apply
+			d.Set("primary_key", oldPrimaryKey)
+			return err
+		}
+	}
+
+	if d.HasChange("column_metadata") {
+		if err := updateColumnMetadata(d, client); err != nil {
+			return err
+		}
+	}
+
+	if dataFile := d.Get("data_file").(string); dataFile != "" && (d.HasChange("data_file") || d.HasChange("data_file_hash")) {
+		err := importDataFile(d, client, dataFile)
+
+		if err != nil {
+			//the data was not loaded, so the old file is kept in state, for the new one to be loaded again by the next apply
+			oldDataFile, _ := d.GetChange("data_file")
+			oldDataFileHash, _ := d.GetChange("data_file_hash")
+			d.Set("data_file", oldDataFile)
+			d.Set("data_file_hash", oldDataFileHash)
+			return err
+		}
+	}
+
+	return resourceKeboolaStorageTableRead(d, meta)
+}
+
+//orderColumns orders columns as they are in the header of the data file, so that they are added to
+//a table in the same order as the file is loaded. As columns are a set, the order they are declared in
+//is not known, so columns which are not in the header are added after those which are, in alphabetical
+//order (rather than the order of their hashes).
+func orderColumns(columns []string, headerColumns []string) []string {
+	headerPositions := make(map[string]int, len(headerColumns))
+
+	for i, column := range headerColumns {
+		headerPositions[column] = i
+	}
+
+	position := func(column string) int {
+		if i, found := headerPositions[column]; found {
+			return i
+		}
+
+		return len(headerColumns)
+	}
+
+	orderedColumns := append([]string(nil), columns...)
+	sort.Strings(orderedColumns)
+	sort.SliceStable(orderedColumns, func(i, j int) bool {
+		return position(orderedColumns[i]) < position(orderedColumns[j])
+	})
+
+	return orderedColumns
+}
+
+//updatePrimaryKey changes the primary key of an existing table, by removing the old key and creating the
+//new one. If the new key cannot be created (e.g. the existing data has duplicate values for it), the old
+//key is restored, so that the table is not left without a primary key.
+func updatePrimaryKey(client *KBCClient, tableID string, oldPrimaryKey []string, newPrimaryKey []string) error {
+	if len(oldPrimaryKey) > 0 {
+		log.Printf("[DEBUG] Removing primary key from Storage Table %s", tableID)
+
+		removePrimaryKeyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s/primary-key", tableID))
+
+		if hasErrors(err, removePrimaryKeyResponse) {
+			return fmt.Errorf("Unable to remove primary key from Storage Table %s: %v", tableID, extractError(err, removePrimaryKeyResponse))
+		}
+	}
+
+	if len(newPrimaryKey) == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Creating primary key %v on Storage Table %s", newPrimaryKey, tableID)
+
+	err := createPrimaryKey(client, tableID, newPrimaryKey)
+
+	if err == nil {
+		return nil
+	}
+
+	createErr := fmt.Errorf("Unable to create primary key %v on Storage Table %s, check that the existing data has no duplicate values for these columns: %v", newPrimaryKey, tableID, err)
+
+	if len(oldPrimaryKey) > 0 {
+		log.Printf("[DEBUG] Restoring primary key %v on Storage Table %s", oldPrimaryKey, tableID)
+
+		if err := createPrimaryKey(client, tableID, oldPrimaryKey); err != nil {
+			return fmt.Errorf("%v\nThe previous primary key %v could not be restored either, so the table has no primary key: %v", createErr, oldPrimaryKey, err)
+		}
+	}
+
+	return createErr
+}
+
+func createPrimaryKey(client *KBCClient, tableID string, primaryKey []string) error {
+	createPrimaryKeyForm := url.Values{}
+	for _, column := range primaryKey {
+		createPrimaryKeyForm.Add("columns[]", column)
+	}
+
+	createPrimaryKeyBuffer := buffer.FromForm(createPrimaryKeyForm)
+
+	createPrimaryKeyResponse, err := client.PostToStorage(fmt.Sprintf("storage/tables/%s/primary-key", tableID), createPrimaryKeyBuffer)
+
+	if hasErrors(err, createPrimaryKeyResponse) {
+		return extractError(err, createPrimaryKeyResponse)
+	}
+
+	return nil
+}
+
+//distributionKeyError adds the backend of the bucket to an error creating a table with a distribution
+//key, as only some backends (such as Synapse or Exasol) support distribution keys.
+func distributionKeyError(client *KBCClient, bucketID string, distributionKey []string, createErr error) error {
+	getBucketResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", bucketID))
+
+	if hasErrors(err, getBucketResponse) {
+		return createErr
+	}
+
+	var storageBucket StorageBucket
+
+	if err := json.NewDecoder(getBucketResponse.Body).Decode(&storageBucket); err != nil {
+		return createErr
+	}
+
+	return fmt.Errorf("Unable to create Storage Table in bucket %s with distribution_key %v, check that the %s backend of the bucket supports distribution keys: %v",
+		bucketID, distributionKey, storageBucket.Backend, createErr)
+}
+
+//checkTransactionalSupported checks that the backend of the bucket supports transactional tables, as
+//only Redshift does, and other backends would otherwise create a non-transactional table. It is checked
+//when the table is created, rather than when it is planned, as the bucket may not exist until then.
+func checkTransactionalSupported(client *KBCClient, bucketID string) error {
+	getBucketResponse, err := client.GetFromStorage(fmt.Sprintf("storage/buckets/%s", bucketID))
+
+	if hasErrors(err, getBucketResponse) {
+		return extractError(err, getBucketResponse)
+	}
+
+	var storageBucket StorageBucket
+
+	decoder := json.NewDecoder(getBucketResponse.Body)
+	err = decoder.Decode(&storageBucket)
+
+	if err != nil {
+		return err
+	}
+
+	if storageBucket.Backend != "redshift" {
+		return fmt.Errorf("transactional is only supported for Storage Tables in redshift buckets, bucket %s uses the %s backend", bucketID, storageBucket.Backend)
+	}
+
+	return nil
+}
+
+//updateColumnMetadata sets the datatype metadata declared in column_metadata on the columns of the
+//table, and removes any datatype metadata which was previously declared but no longer is.
+func updateColumnMetadata(d *schema.ResourceData, client *KBCClient) error {
+	oldColumnMetadata, newColumnMetadata := d.GetChange("column_metadata")
+
+	oldMetadata := mapColumnMetadataSchemaToModel(oldColumnMetadata.(*schema.Set).List())
+	newMetadata := mapColumnMetadataSchemaToModel(newColumnMetadata.(*schema.Set).List())
+
+	if staleKeys := staleColumnMetadataKeys(oldMetadata, newMetadata); len(staleKeys) > 0 {
+		if err := removeColumnMetadata(d.Id(), staleKeys, client); err != nil {
+			return err
+		}
+	}
+
+	if len(newMetadata) == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Setting metadata of columns %v on Storage Table %s", columnMetadataColumns(newColumnMetadata.(*schema.Set).List()), d.Id())
+
+	columnsMetadataJSON, err := json.Marshal(ColumnsMetadata{
+		Provider:        columnMetadataProvider,
+		ColumnsMetadata: newMetadata,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	updateMetadataResponse, err := client.PostJSONToStorage(fmt.Sprintf("storage/tables/%s/metadata", d.Id()), bytes.NewBuffer(columnsMetadataJSON))
+
+	if hasErrors(err, updateMetadataResponse) {
+		return fmt.Errorf("Unable to set column metadata on Storage Table %s: %v", d.Id(), extractError(err, updateMetadataResponse))
+	}
+
+	return nil
+}
+
+//removeColumnMetadata deletes the given metadata keys from the columns of the table. Keboola deletes
+//metadata by its ID, so the current metadata of the table is read first to find them.
+func removeColumnMetadata(tableID string, staleKeys map[string][]string, client *KBCClient) error {
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", tableID))
+
+	if hasErrors(err, getResponse) {
+		return extractError(err, getResponse)
+	}
+
+	var storageTable StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTable)
+
+	if err != nil {
+		return err
+	}
+
+	for column, keys := range staleKeys {
+		for _, entry := range storageTable.ColumnMetadata[column] {
+			if entry.Provider != columnMetadataProvider || !containsString(keys, entry.Key) {
+				continue
+			}
+
+			log.Printf("[DEBUG] Removing metadata %s from column %s of Storage Table %s", entry.Key, column, tableID)
+
+			deleteResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/columns/%s.%s/metadata/%s", tableID, column, entry.ID))
+
+			if hasErrors(err, deleteResponse) && (err != nil || deleteResponse.StatusCode != 404) {
+				return extractError(err, deleteResponse)
+			}
+		}
+	}
+
+	return nil
+}
+
+func columnMetadataColumns(columnMetadata []interface{}) []string {
+	columns := make([]string, 0, len(columnMetadata))
+
+	for _, item := range columnMetadata {
+		columns = append(columns, item.(map[string]interface{})["column"].(string))
+	}
+
+	return columns
+}
+
+//importDataFile loads the contents of a data file in to an existing table, either appending
+//to the existing rows (when incremental is set) or replacing them entirely. When the table has
+//a primary key, an incremental load upserts: rows whose primary key already exists are updated
+//rather than duplicated.
+func importDataFile(d *schema.ResourceData, client *KBCClient, dataFile string) error {
+	log.Printf("[DEBUG] Importing data_file %s in to Storage Table %s (incremental: %v)", dataFile, d.Id(), d.Get("incremental").(bool))
+
+	delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+	err := validateDataFileHeader(dataFile, delimiter, enclosure, AsStringArray(d.Get("columns").(*schema.Set).List()))
+
+	if err != nil {
+		return err
+	}
+
+	uploadFileBuffer := &bytes.Buffer{}
+	uploadFileRequestWriter := multipart.NewWriter(uploadFileBuffer)
+	uploadFileRequestWriter.SetBoundary("----terraform-provider-keboola----")
+
+	err = writeDataFile(uploadFileRequestWriter, dataFile)
+
+	if err != nil {
+		return err
+	}
+
+	uploadFileRequestWriter.Close()
+
+	fileID, err := uploadToFileImport(client, uploadFileBuffer)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = importUploadedFile(client, d.Id(), fileID, delimiter, enclosure, d.Get("incremental").(bool), d.Timeout(schema.TimeoutUpdate))
+
+	return err
+}
+
+//importUploadedFile loads a file uploaded to the File Import API in to an existing table, waiting for
+//the import job to finish, and then removes the file from the project's file storage.
+func importUploadedFile(client *KBCClient, tableID string, fileID int, delimiter string, enclosure string, incremental bool, timeout time.Duration) (*StorageJobStatus, error) {
+	importTableForm := url.Values{}
+	importTableForm.Add("dataFileId", strconv.Itoa(fileID))
+	importTableForm.Add("delimiter", delimiter)
+	importTableForm.Add("enclosure", enclosure)
+
+	if incremental {
+		importTableForm.Add("incremental", "1")
+	} else {
+		importTableForm.Add("incremental", "0")
+	}
+
+	importTableBuffer := buffer.FromForm(importTableForm)
+
+	importTableResponse, err := client.PostToStorage(fmt.Sprintf("storage/tables/%s/import-async", tableID), importTableBuffer)
+
+	if hasErrors(err, importTableResponse) {
+		return nil, extractError(err, importTableResponse)
+	}
+
+	var importTableResult UploadFileResult
+
+	importTableDecoder := json.NewDecoder(importTableResponse.Body)
+	err = importTableDecoder.Decode(&importTableResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	importJob, err := client.WaitForStorageJob(client.stopContext(), importTableResult.ID, timeout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deleteUploadedFile(client, fileID)
+
+	return importJob, nil
+}
+
+func resourceKeboolaStorageTableDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) {
+		//The table may already have been deleted along with its bucket
+		if err == nil && destroyResponse.StatusCode == 404 {
+			log.Printf("[INFO] Storage Table %s has already been deleted.", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, destroyResponse)
+	}
+
+	err = waitForAcceptedStorageJob(client, destroyResponse, d.Timeout(schema.TimeoutDelete))
+
+	if err != nil {
+		return err
 	}
 
 	d.SetId("")
@@ -0,0 +1,311 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//StorageTableAliasSource is the table that a Storage Table Alias points at.
+type StorageTableAliasSource struct {
+	ID string `json:"id"`
+}
+
+//StorageTableAliasFilter restricts the rows of the source table exposed by a Storage Table Alias.
+type StorageTableAliasFilter struct {
+	Column   string   `json:"column"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+//StorageTableAlias is the data model for alias tables within
+//the Keboola Storage API.
+type StorageTableAlias struct {
+	ID                   string                   `json:"id,omitempty"`
+	Name                 string                   `json:"name"`
+	IsAlias              bool                     `json:"isAlias"`
+	SourceTable          StorageTableAliasSource  `json:"sourceTable"`
+	AliasColumnsAutoSync bool                     `json:"aliasColumnsAutoSync"`
+	AliasFilter          *StorageTableAliasFilter `json:"aliasFilter,omitempty"`
+	Columns              []string                 `json:"columns"`
+	Created              KBCTime                  `json:"created"`
+}
+
+//endregion
+
+func resourceKeboolaStorageTableAlias() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageTableAliasCreate,
+		Read:   resourceKeboolaStorageTableAliasRead,
+		Update: resourceKeboolaStorageTableAliasUpdate,
+		Delete: resourceKeboolaStorageTableAliasDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKeboolaStorageTableAliasImport,
+		},
+		CustomizeDiff: resourceKeboolaStorageTableAliasCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"alias_columns_autosync": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			//Keboola returns the columns of an alias in the order of the source table, and reordering
+			//them does not change the alias, so only a change to which columns are aliased is a diff.
+			"columns": {
+				Type:             schema.TypeList,
+				Optional:         true,
+				ForceNew:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressReorderedList,
+			},
+			"alias_filter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"column": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"operator": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "eq",
+							ValidateFunc: validateStorageTableAliasFilterOperator,
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"created": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+//resourceKeboolaStorageTableAliasCustomizeDiff rejects an explicit list of columns on an alias
+//that also has its columns automatically synchronised with the source table, as the API
+//does not allow both.
+func resourceKeboolaStorageTableAliasCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return validateAliasColumns(d.Get("alias_columns_autosync").(bool), AsStringArray(d.Get("columns").([]interface{})))
+}
+
+func validateAliasColumns(autoSync bool, columns []string) error {
+	if autoSync && len(columns) > 0 {
+		return fmt.Errorf("columns cannot be set on a Storage Table Alias with alias_columns_autosync enabled, set alias_columns_autosync = false to expose a subset of columns")
+	}
+
+	return nil
+}
+
+//addAliasFilterToForm adds the alias filter (if any) to a form, using the given prefix for the field names.
+func addAliasFilterToForm(form url.Values, prefix string, aliasFilters []interface{}) {
+	if len(aliasFilters) == 0 {
+		return
+	}
+
+	aliasFilter := aliasFilters[0].(map[string]interface{})
+
+	form.Add(fmt.Sprintf(prefix, "column"), aliasFilter["column"].(string))
+	form.Add(fmt.Sprintf(prefix, "operator"), aliasFilter["operator"].(string))
+
+	for _, value := range AsStringArray(aliasFilter["values"].([]interface{})) {
+		form.Add(fmt.Sprintf(prefix, "values")+"[]", value)
+	}
+}
+
+func resourceKeboolaStorageTableAliasCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Table Alias in Keboola.")
+
+	createAliasForm := url.Values{}
+	createAliasForm.Add("name", d.Get("name").(string))
+	createAliasForm.Add("sourceTable", d.Get("source_table").(string))
+
+	for _, column := range AsStringArray(d.Get("columns").([]interface{})) {
+		createAliasForm.Add("aliasColumns[]", column)
+	}
+
+	addAliasFilterToForm(createAliasForm, "aliasFilter[%s]", d.Get("alias_filter").([]interface{}))
+
+	createAliasBuffer := buffer.FromForm(createAliasForm)
+
+	client := meta.(*KBCClient)
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/buckets/%s/table-aliases", d.Get("bucket_id").(string)), createAliasBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createAliasResult StorageTableAlias
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createAliasResult)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(createAliasResult.ID)
+
+	if !d.Get("alias_columns_autosync").(bool) {
+		disableAutoSyncResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s/alias-columns-auto-sync", d.Id()))
+
+		if hasErrors(err, disableAutoSyncResponse) {
+			return extractError(err, disableAutoSyncResponse)
+		}
+	}
+
+	return resourceKeboolaStorageTableAliasRead(d, meta)
+}
+
+func resourceKeboolaStorageTableAliasImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	bucketID, name, err := parseStorageTableID(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("bucket_id", bucketID)
+	d.Set("name", name)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKeboolaStorageTableAliasRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table Alias from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTableAlias StorageTableAlias
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTableAlias)
+
+	if err != nil {
+		return err
+	}
+
+	if !storageTableAlias.IsAlias {
+		return fmt.Errorf("Storage Table %s is not an alias", d.Id())
+	}
+
+	if bucketID, _, err := parseStorageTableID(d.Id()); err == nil {
+		d.Set("bucket_id", bucketID)
+	}
+
+	d.Set("name", storageTableAlias.Name)
+	d.Set("source_table", storageTableAlias.SourceTable.ID)
+	d.Set("alias_columns_autosync", storageTableAlias.AliasColumnsAutoSync)
+
+	if storageTableAlias.AliasColumnsAutoSync {
+		d.Set("columns", nil)
+	} else {
+		d.Set("columns", storageTableAlias.Columns)
+	}
+
+	if storageTableAlias.AliasFilter != nil && storageTableAlias.AliasFilter.Column != "" {
+		d.Set("alias_filter", []map[string]interface{}{
+			{
+				"column":   storageTableAlias.AliasFilter.Column,
+				"operator": storageTableAlias.AliasFilter.Operator,
+				"values":   storageTableAlias.AliasFilter.Values,
+			},
+		})
+	} else {
+		d.Set("alias_filter", nil)
+	}
+
+	d.Set("created", storageTableAlias.Created.RFC3339())
+
+	return nil
+}
+
+func resourceKeboolaStorageTableAliasUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Updating Storage Table Alias in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	if d.HasChange("alias_filter") {
+		aliasFilters := d.Get("alias_filter").([]interface{})
+		aliasFilterURI := fmt.Sprintf("storage/tables/%s/alias-filter", d.Id())
+
+		if len(aliasFilters) == 0 {
+			removeFilterResponse, err := client.DeleteFromStorage(aliasFilterURI)
+
+			if hasErrors(err, removeFilterResponse) {
+				return extractError(err, removeFilterResponse)
+			}
+		} else {
+			updateFilterForm := url.Values{}
+			addAliasFilterToForm(updateFilterForm, "%s", aliasFilters)
+
+			updateFilterResponse, err := client.PostToStorage(aliasFilterURI, buffer.FromForm(updateFilterForm))
+
+			if hasErrors(err, updateFilterResponse) {
+				return extractError(err, updateFilterResponse)
+			}
+		}
+	}
+
+	return resourceKeboolaStorageTableAliasRead(d, meta)
+}
+
+func resourceKeboolaStorageTableAliasDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table Alias in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableAlias_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageTableAliasDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableAliasBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "name", "test_alias"),
+					resource.TestCheckResourceAttrPair("keboola_storage_table_alias.test_alias", "source_table", "keboola_storage_table.test_table", "id"),
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "alias_columns_autosync", "true"),
+				),
+			},
+			{
+				ResourceName:      "keboola_storage_table_alias.test_alias",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTableAlias_UpdateFilter(t *testing.T) {
+	var aliasCreated string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageTableAliasDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableAliasFiltered, "first_value"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreated("keboola_storage_table_alias.test_alias", &aliasCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "columns.#", "2"),
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "alias_filter.0.column", "first"),
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "alias_filter.0.values.0", "first_value"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testStorageTableAliasFiltered, "second_value"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreatedUnchanged("keboola_storage_table_alias.test_alias", &aliasCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table_alias.test_alias", "alias_filter.0.values.0", "second_value"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStorageTableAlias_ReorderedColumns(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageTableAliasDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableAliasColumns, `"first", "third"`),
+			},
+			{
+				Config:   fmt.Sprintf(testStorageTableAliasColumns, `"third", "first"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestValidateAliasColumns(t *testing.T) {
+	assert.NoError(t, validateAliasColumns(true, nil), "Autosync without explicit columns should be valid")
+	assert.NoError(t, validateAliasColumns(false, []string{"first"}), "Explicit columns without autosync should be valid")
+	assert.Error(t, validateAliasColumns(true, []string{"first"}), "Explicit columns with autosync should be rejected")
+}
+
+func TestStorageTableAliasDecodesSourceTable(t *testing.T) {
+	getAliasResponse := `{
+		"id": "out.c-curated.orders",
+		"name": "orders",
+		"isAlias": true,
+		"aliasColumnsAutoSync": false,
+		"sourceTable": {
+			"id": "in.c-raw.orders",
+			"project": { "id": 123, "name": "Raw" }
+		},
+		"columns": ["id", "amount"],
+		"created": "2019-07-01T09:15:00+0200",
+		"aliasFilter": {
+			"column": "country",
+			"operator": "eq",
+			"values": ["CZ", "SK"]
+		}
+	}`
+
+	var storageTableAlias StorageTableAlias
+	err := json.Unmarshal([]byte(getAliasResponse), &storageTableAlias)
+
+	assert.NoError(t, err)
+	assert.True(t, storageTableAlias.IsAlias, "isAlias should be decoded")
+	assert.Equal(t, "in.c-raw.orders", storageTableAlias.SourceTable.ID, "sourceTable.id should be decoded")
+	assert.False(t, storageTableAlias.AliasColumnsAutoSync, "aliasColumnsAutoSync should be decoded")
+	assert.Equal(t, []string{"id", "amount"}, storageTableAlias.Columns, "columns should be decoded")
+	assert.Equal(t, "country", storageTableAlias.AliasFilter.Column, "aliasFilter.column should be decoded")
+	assert.Equal(t, []string{"CZ", "SK"}, storageTableAlias.AliasFilter.Values, "aliasFilter.values should be decoded")
+	assert.Equal(t, "2019-07-01T07:15:00Z", storageTableAlias.Created.RFC3339(), "created should be decoded")
+}
+
+func TestStorageTableAliasDeleteIgnoresDeletedAlias(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableAlias().Schema, map[string]interface{}{
+		"name": "orders",
+	})
+	d.SetId("out.c-curated.orders")
+
+	err := resourceKeboolaStorageTableAliasDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+}
+
+func testAccCheckStorageTableAliasDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_table_alias" {
+			continue
+		}
+
+		storageTableAliasURI := fmt.Sprintf("storage/tables/%s", rs.Primary.ID)
+		getResp, err := client.GetFromStorage(storageTableAliasURI)
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Storage table alias still exists")
+		}
+	}
+
+	return nil
+}
+
+const testStorageTableAliasBasic = `
+	resource "keboola_storage_bucket" "test_source_bucket" {
+		name = "test_source_bucket"
+		description = "test description"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket" "test_alias_bucket" {
+		name = "test_alias_bucket"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_source_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+	}
+
+	resource "keboola_storage_table_alias" "test_alias" {
+		bucket_id = "${keboola_storage_bucket.test_alias_bucket.id}"
+		name = "test_alias"
+		source_table = "${keboola_storage_table.test_table.id}"
+	}`
+
+const testStorageTableAliasFiltered = `
+	resource "keboola_storage_bucket" "test_source_bucket" {
+		name = "test_source_bucket"
+		description = "test description"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket" "test_alias_bucket" {
+		name = "test_alias_bucket"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_source_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+	}
+
+	resource "keboola_storage_table_alias" "test_alias" {
+		bucket_id = "${keboola_storage_bucket.test_alias_bucket.id}"
+		name = "test_alias"
+		source_table = "${keboola_storage_table.test_table.id}"
+		alias_columns_autosync = false
+		columns = [ "first", "second" ]
+
+		alias_filter {
+			column = "first"
+			values = [ "%s" ]
+		}
+	}`
+
+const testStorageTableAliasColumns = `
+	resource "keboola_storage_bucket" "test_source_bucket" {
+		name = "test_source_bucket"
+		description = "test description"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_bucket" "test_alias_bucket" {
+		name = "test_alias_bucket"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_source_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+	}
+
+	resource "keboola_storage_table_alias" "test_alias" {
+		bucket_id = "${keboola_storage_bucket.test_alias_bucket.id}"
+		name = "test_alias"
+		source_table = "${keboola_storage_table.test_table.id}"
+		alias_columns_autosync = false
+		columns = [ %s ]
+	}`
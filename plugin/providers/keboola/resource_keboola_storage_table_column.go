@@ -0,0 +1,132 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+func resourceKeboolaStorageTableColumn() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageTableColumnCreate,
+		Read:   resourceKeboolaStorageTableColumnRead,
+		Delete: resourceKeboolaStorageTableColumnDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"table_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+//parseStorageTableColumnID splits a column ID (e.g. in.c-bucket.table.column) in to
+//the ID of the table and the name of the column.
+func parseStorageTableColumnID(columnID string) (tableID string, name string, err error) {
+	tableSeparator := strings.LastIndex(columnID, ".")
+
+	if tableSeparator < 1 || tableSeparator == len(columnID)-1 {
+		return "", "", fmt.Errorf("%q is not a valid Storage Table Column ID, expected the table ID followed by the column name (e.g. in.c-bucket.table.column)", columnID)
+	}
+
+	return columnID[:tableSeparator], columnID[tableSeparator+1:], nil
+}
+
+func resourceKeboolaStorageTableColumnCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Table Column in Keboola.")
+
+	tableID := d.Get("table_id").(string)
+	name := d.Get("name").(string)
+
+	addColumnForm := url.Values{}
+	addColumnForm.Add("name", name)
+
+	addColumnBuffer := buffer.FromForm(addColumnForm)
+
+	client := meta.(*KBCClient)
+	addColumnResponse, err := client.PostToStorage(fmt.Sprintf("storage/tables/%s/columns", tableID), addColumnBuffer)
+
+	if hasErrors(err, addColumnResponse) {
+		return extractError(err, addColumnResponse)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", tableID, name))
+
+	return resourceKeboolaStorageTableColumnRead(d, meta)
+}
+
+func resourceKeboolaStorageTableColumnRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table Column from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	tableID, name, err := parseStorageTableColumnID(d.Id())
+
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", tableID))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTable StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTable)
+
+	if err != nil {
+		return err
+	}
+
+	if !containsString(storageTable.Columns, name) {
+		log.Printf("[WARN] Column %s no longer exists on Storage Table %s, removing from state.", name, tableID)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("table_id", tableID)
+	d.Set("name", name)
+
+	return nil
+}
+
+func resourceKeboolaStorageTableColumnDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table Column in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/tables/%s/columns/%s", d.Get("table_id").(string), d.Get("name").(string)))
+
+	if hasErrors(err, destroyResponse) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
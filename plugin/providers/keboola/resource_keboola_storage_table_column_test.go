@@ -0,0 +1,94 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableColumn_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageTableColumnDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableColumnBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table_column.test_column", "name", "fourth"),
+					resource.TestCheckResourceAttrPair("keboola_storage_table_column.test_column", "table_id", "keboola_storage_table.test_table", "id"),
+				),
+			},
+			{
+				ResourceName:      "keboola_storage_table_column.test_column",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseStorageTableColumnID(t *testing.T) {
+	tableID, name, err := parseStorageTableColumnID("in.c-bucket.table.column")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.table", tableID)
+	assert.Equal(t, "column", name)
+
+	_, _, err = parseStorageTableColumnID("column")
+	assert.Error(t, err, "Column ID without a table should be rejected")
+}
+
+func testAccCheckStorageTableColumnDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_table_column" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", rs.Primary.Attributes["table_id"]))
+
+		if err != nil || getResp.StatusCode != 200 {
+			continue
+		}
+
+		var storageTable StorageTable
+
+		decoder := json.NewDecoder(getResp.Body)
+		err = decoder.Decode(&storageTable)
+
+		if err == nil && containsString(storageTable.Columns, rs.Primary.Attributes["name"]) {
+			return fmt.Errorf("Storage table column still exists")
+		}
+	}
+
+	return nil
+}
+
+const testStorageTableColumnBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+
+		lifecycle {
+			ignore_changes = [ "columns" ]
+		}
+	}
+
+	resource "keboola_storage_table_column" "test_column" {
+		table_id = "${keboola_storage_table.test_table.id}"
+		name = "fourth"
+	}`
@@ -0,0 +1,211 @@
+package keboola
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//resourceKeboolaStorageTableLoad loads data in to an existing table, separately from the definition of
+//the table. Every attribute forces a new load, including a change to the contents of the data file,
+//and destroying a load leaves the loaded data in the table.
+func resourceKeboolaStorageTableLoad() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageTableLoadCreate,
+		Read:   resourceKeboolaStorageTableLoadRead,
+		Delete: resourceKeboolaStorageTableLoadDelete,
+
+		CustomizeDiff: resourceKeboolaStorageTableLoadCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"table_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"data_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"data"},
+			},
+			//inline CSV data, including its header row, for small tables such as lookups
+			"data": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"data_file"},
+			},
+			"incremental": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"delimiter": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      defaultStorageTableDelimiter,
+				ValidateFunc: validateDelimiter,
+				StateFunc:    unescapeDelimiter,
+			},
+			//an explicitly empty enclosure loads the data without any enclosure, rather than with the default
+			"enclosure": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      defaultStorageTableEnclosure,
+				ValidateFunc: validateEnclosure,
+			},
+			"data_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+				ForceNew: true,
+			},
+			"job_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"loaded_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKeboolaStorageTableLoadCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Table Load in Keboola.")
+
+	client := meta.(*KBCClient)
+	tableID := d.Get("table_id").(string)
+
+	uploadFileBuffer := &bytes.Buffer{}
+	uploadFileRequestWriter := multipart.NewWriter(uploadFileBuffer)
+	uploadFileRequestWriter.SetBoundary("----terraform-provider-keboola----")
+
+	var err error
+
+	if dataFile := d.Get("data_file").(string); dataFile != "" {
+		err = writeDataFile(uploadFileRequestWriter, dataFile)
+	} else {
+		err = writeData(uploadFileRequestWriter, fmt.Sprintf("%s.csv", tableID), strings.NewReader(d.Get("data").(string)))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	uploadFileRequestWriter.Close()
+
+	dataHash, err := storageTableLoadDataHash(d.Get("data_file").(string), d.Get("data").(string))
+
+	if err != nil {
+		return err
+	}
+
+	fileID, err := uploadToFileImport(client, uploadFileBuffer)
+
+	if err != nil {
+		return err
+	}
+
+	delimiter, enclosure := storageTableCSVSettings(d.Get("delimiter").(string), d.Get("enclosure").(string))
+
+	importJob, err := importUploadedFile(client, tableID, fileID, delimiter, enclosure, d.Get("incremental").(bool), d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(importJob.ID))
+	d.Set("job_id", strconv.Itoa(importJob.ID))
+	d.Set("loaded_at", time.Now().UTC().Format(time.RFC3339))
+	d.Set("data_hash", dataHash)
+
+	return resourceKeboolaStorageTableLoadRead(d, meta)
+}
+
+//storageTableLoadDataHash calculates a SHA-256 hash of the data being loaded, either from the data
+//file or of the inline data.
+func storageTableLoadDataHash(dataFile string, data string) (string, error) {
+	if dataFile != "" {
+		return hashDataFile(dataFile)
+	}
+
+	hash := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+//resourceKeboolaStorageTableLoadCustomizeDiff plans a new load whenever the contents of the data
+//file have changed since the last load, even when the path of the file has not.
+func resourceKeboolaStorageTableLoadCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("data_file") || !d.NewValueKnown("data") {
+		return nil
+	}
+
+	dataFile := d.Get("data_file").(string)
+	data := d.Get("data").(string)
+
+	if dataFile == "" && data == "" {
+		return fmt.Errorf("one of data_file or data must be set")
+	}
+
+	dataHash, err := storageTableLoadDataHash(dataFile, data)
+
+	if err != nil {
+		return err
+	}
+
+	if dataHash != d.Get("data_hash").(string) {
+		return d.SetNew("data_hash", dataHash)
+	}
+
+	return nil
+}
+
+func resourceKeboolaStorageTableLoadRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table Load from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	//the load itself cannot be read back, so only the table it was loaded in to is checked
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", d.Get("table_id").(string)))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	return nil
+}
+
+func resourceKeboolaStorageTableLoadDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table Load in Keboola: %s", d.Id())
+
+	//the loaded data is left in the table, as it may since have been added to by other loads
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package keboola
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableLoad_Basic(t *testing.T) {
+	var jobID string
+
+	dataFile := writeTestDataFile(t, "id,name\n1,first\n")
+	defer os.Remove(dataFile)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableLoadBasic, dataFile),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("keboola_storage_table_load.test_load", "loaded_at"),
+					resource.TestCheckResourceAttr("keboola_storage_table_load.test_load", "incremental", "true"),
+					testAccCheckStorageTableLoadJobID("keboola_storage_table_load.test_load", &jobID),
+				),
+			},
+			{
+				PreConfig: func() {
+					ioutil.WriteFile(dataFile, []byte("id,name\n2,second\n"), 0644)
+				},
+				Config: fmt.Sprintf(testStorageTableLoadBasic, dataFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableLoadReloaded("keboola_storage_table_load.test_load", &jobID),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageTableLoadCreateLoadsInlineData(t *testing.T) {
+	var uploadedData string
+	var importTableForm url.Values
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "import.example.com" && r.URL.Path == "/upload-file":
+			r.ParseMultipartForm(1024)
+			dataFile, _, _ := r.FormFile("data")
+			data, _ := ioutil.ReadAll(dataFile)
+			uploadedData = string(data)
+			w.Write([]byte(`{ "id": 777 }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/tables/in.c-bucket.lookup/import-async":
+			r.ParseForm()
+			importTableForm = r.PostForm
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.URL.Path == "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "success", "results": { "id": "in.c-bucket.lookup" } }`))
+		case r.Method == "DELETE" && r.URL.Path == "/v2/storage/files/777":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/tables/in.c-bucket.lookup":
+			w.Write([]byte(`{ "id": "in.c-bucket.lookup", "name": "lookup" }`))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableLoad().Schema, map[string]interface{}{
+		"table_id":    "in.c-bucket.lookup",
+		"data":        "code;label\nA;Active\n",
+		"delimiter":   ";",
+		"incremental": true,
+	})
+
+	err := resourceKeboolaStorageTableLoadCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "code;label\nA;Active\n", uploadedData)
+	assert.Equal(t, ";", importTableForm.Get("delimiter"))
+	assert.Equal(t, "\"", importTableForm.Get("enclosure"))
+	assert.Equal(t, "1", importTableForm.Get("incremental"))
+	assert.Equal(t, "12345", d.Id())
+	assert.Equal(t, "12345", d.Get("job_id"))
+	assert.NotEmpty(t, d.Get("loaded_at"))
+
+	expectedHash, _ := storageTableLoadDataHash("", "code;label\nA;Active\n")
+	assert.Equal(t, expectedHash, d.Get("data_hash"))
+}
+
+func TestStorageTableLoadDataHashMatchesForFileAndInlineData(t *testing.T) {
+	dataFile := writeTestDataFile(t, "id,name\n1,first\n")
+	defer os.Remove(dataFile)
+
+	fileHash, err := storageTableLoadDataHash(dataFile, "")
+	assert.NoError(t, err)
+
+	inlineHash, err := storageTableLoadDataHash("", "id,name\n1,first\n")
+	assert.NoError(t, err)
+
+	assert.Equal(t, fileHash, inlineHash, "The hash should only depend on the data being loaded")
+
+	changedHash, _ := storageTableLoadDataHash("", "id,name\n2,second\n")
+	assert.NotEqual(t, fileHash, changedHash)
+}
+
+func TestStorageTableLoadReadRemovesLoadOfDeletedTable(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableLoad().Schema, map[string]interface{}{
+		"table_id": "in.c-bucket.deleted",
+		"data":     "id\n1\n",
+	})
+	d.SetId("12345")
+
+	err := resourceKeboolaStorageTableLoadRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A load in to a table that no longer exists should be removed from state")
+}
+
+func testAccCheckStorageTableLoadJobID(n string, jobID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		*jobID = rs.Primary.Attributes["job_id"]
+
+		return nil
+	}
+}
+
+func testAccCheckStorageTableLoadReloaded(n string, jobID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.Attributes["job_id"] == *jobID {
+			return fmt.Errorf("Storage table was not reloaded after the data file changed (job ID is still %s)", *jobID)
+		}
+
+		return nil
+	}
+}
+
+const testStorageTableLoadBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table_name"
+		columns = [ "id", "name" ]
+		primary_key = [ "id" ]
+	}
+
+	resource "keboola_storage_table_load" "test_load" {
+		table_id = "${keboola_storage_table.test_table.id}"
+		data_file = "%s"
+		incremental = true
+	}`
@@ -0,0 +1,236 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceKeboolaStorageTableMetadata() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageTableMetadataCreate,
+		Read:   resourceKeboolaStorageTableMetadataRead,
+		Update: resourceKeboolaStorageTableMetadataUpdate,
+		Delete: resourceKeboolaStorageTableMetadataDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"table_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"metadata_provider": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  defaultMetadataProvider,
+			},
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"column_metadata": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"column": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"metadata": {
+							Type:     schema.TypeMap,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceKeboolaStorageTableMetadataCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Table Metadata in Keboola.")
+
+	tableID := d.Get("table_id").(string)
+	provider := d.Get("metadata_provider").(string)
+
+	client := meta.(*KBCClient)
+	err := setMetadata(client, fmt.Sprintf("storage/tables/%s/metadata", tableID), provider, d.Get("metadata").(map[string]interface{}))
+
+	if err != nil {
+		return err
+	}
+
+	for column, metadata := range mapTableColumnMetadataToModel(d.Get("column_metadata").(*schema.Set).List()) {
+		if err := setMetadata(client, columnMetadataEndpoint(tableID, column), provider, metadata); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(tableID)
+
+	return resourceKeboolaStorageTableMetadataRead(d, meta)
+}
+
+func resourceKeboolaStorageTableMetadataRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table Metadata from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			log.Printf("[WARN] Storage Table %s no longer exists, removing its metadata from state.", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var storageTable StorageTable
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&storageTable)
+
+	if err != nil {
+		return err
+	}
+
+	provider := d.Get("metadata_provider").(string)
+	if provider == "" {
+		provider = defaultMetadataProvider
+	}
+
+	d.Set("table_id", d.Id())
+	d.Set("metadata_provider", provider)
+	managedColumnMetadata := mapTableColumnMetadataToModel(d.Get("column_metadata").(*schema.Set).List())
+
+	d.Set("metadata", withoutUnmanagedSystemMetadata(mapMetadataEntriesToSchema(storageTable.Metadata, provider), d.Get("metadata").(map[string]interface{})))
+	d.Set("column_metadata", mapTableColumnMetadataToSchema(storageTable.ColumnMetadata, provider, managedColumnMetadata))
+
+	return nil
+}
+
+func resourceKeboolaStorageTableMetadataUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Storage Table Metadata in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	provider := d.Get("metadata_provider").(string)
+
+	if d.HasChange("metadata") {
+		endpoint := fmt.Sprintf("storage/tables/%s/metadata", d.Id())
+		oldMetadata, newMetadata := d.GetChange("metadata")
+		removedKeys := except(mapKeys(oldMetadata.(map[string]interface{})), mapKeys(newMetadata.(map[string]interface{})))
+
+		if err := deleteMetadata(client, endpoint, provider, removedKeys); err != nil {
+			return err
+		}
+
+		if err := setMetadata(client, endpoint, provider, changedMetadata(oldMetadata.(map[string]interface{}), newMetadata.(map[string]interface{}))); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("column_metadata") {
+		oldColumnMetadata, newColumnMetadata := d.GetChange("column_metadata")
+		oldMetadata := mapTableColumnMetadataToModel(oldColumnMetadata.(*schema.Set).List())
+		newMetadata := mapTableColumnMetadataToModel(newColumnMetadata.(*schema.Set).List())
+
+		for column, metadata := range oldMetadata {
+			removedKeys := except(mapKeys(metadata), mapKeys(newMetadata[column]))
+
+			if err := deleteMetadata(client, columnMetadataEndpoint(d.Id(), column), provider, removedKeys); err != nil {
+				return err
+			}
+		}
+
+		for column, metadata := range newMetadata {
+			if err := setMetadata(client, columnMetadataEndpoint(d.Id(), column), provider, changedMetadata(oldMetadata[column], metadata)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceKeboolaStorageTableMetadataRead(d, meta)
+}
+
+func resourceKeboolaStorageTableMetadataDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table Metadata in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	provider := d.Get("metadata_provider").(string)
+
+	err := deleteMetadata(client, fmt.Sprintf("storage/tables/%s/metadata", d.Id()), provider, mapKeys(d.Get("metadata").(map[string]interface{})))
+
+	if err != nil {
+		return err
+	}
+
+	for column, metadata := range mapTableColumnMetadataToModel(d.Get("column_metadata").(*schema.Set).List()) {
+		if err := deleteMetadata(client, columnMetadataEndpoint(d.Id(), column), provider, mapKeys(metadata)); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func columnMetadataEndpoint(tableID string, column string) string {
+	return fmt.Sprintf("storage/columns/%s.%s/metadata", tableID, column)
+}
+
+//mapTableColumnMetadataToModel maps the column_metadata blocks to the metadata of each column.
+func mapTableColumnMetadataToModel(columnMetadata []interface{}) map[string]map[string]interface{} {
+	mappedMetadata := make(map[string]map[string]interface{}, len(columnMetadata))
+
+	for _, item := range columnMetadata {
+		config := item.(map[string]interface{})
+		mappedMetadata[config["column"].(string)] = config["metadata"].(map[string]interface{})
+	}
+
+	return mappedMetadata
+}
+
+//mapTableColumnMetadataToSchema maps the metadata of each column set by the given provider, leaving
+//out columns which have none. KBC.* entries which are not already managed are left out too, such as the
+//KBC.datatype.* entries set by the column_metadata of keboola_storage_table, or descriptions set in the UI.
+func mapTableColumnMetadataToSchema(columnMetadata map[string][]MetadataEntry, provider string, managedColumnMetadata map[string]map[string]interface{}) []map[string]interface{} {
+	columns := make([]string, 0, len(columnMetadata))
+
+	for column := range columnMetadata {
+		columns = append(columns, column)
+	}
+
+	sort.Strings(columns)
+
+	mappedMetadata := make([]map[string]interface{}, 0, len(columns))
+
+	for _, column := range columns {
+		metadata := withoutUnmanagedSystemMetadata(mapMetadataEntriesToSchema(columnMetadata[column], provider), managedColumnMetadata[column])
+
+		if len(metadata) > 0 {
+			mappedMetadata = append(mappedMetadata, map[string]interface{}{
+				"column":   column,
+				"metadata": metadata,
+			})
+		}
+	}
+
+	return mappedMetadata
+}
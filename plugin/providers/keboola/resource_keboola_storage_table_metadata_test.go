@@ -0,0 +1,231 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableMetadata_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableMetadataDestroy,
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableMetadataBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table_metadata.test_metadata", "metadata.%", "1"),
+					resource.TestCheckResourceAttr("keboola_storage_table_metadata.test_metadata", "metadata.KBC.description", "Orders placed in the web shop"),
+					resource.TestCheckResourceAttr("keboola_storage_table_metadata.test_metadata", "column_metadata.#", "1"),
+				),
+			},
+			{
+				Config: testStorageTableMetadataUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table_metadata.test_metadata", "metadata.%", "0"),
+					resource.TestCheckResourceAttr("keboola_storage_table_metadata.test_metadata", "column_metadata.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "keboola_storage_table_metadata.test_metadata",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMappingFromTableColumnMetadataModelToSchema(t *testing.T) {
+	columnMetadata := map[string][]MetadataEntry{
+		"id": {
+			{ID: "1", Key: "KBC.description", Value: "Order ID", Provider: "user"},
+			{ID: "2", Key: "KBC.datatype.basetype", Value: "INTEGER", Provider: "keboola.ex-db-mysql"},
+		},
+		"amount": {
+			{ID: "3", Key: "KBC.datatype.basetype", Value: "NUMERIC", Provider: "keboola.ex-db-mysql"},
+		},
+		"customer": {
+			{ID: "4", Key: "KBC.description", Value: "Customer name", Provider: "user"},
+		},
+	}
+	managedColumnMetadata := map[string]map[string]interface{}{
+		"id":       {"KBC.description": "Order ID"},
+		"customer": {"KBC.description": "Customer"},
+	}
+
+	result := mapTableColumnMetadataToSchema(columnMetadata, "user", managedColumnMetadata)
+
+	assert.Equal(t, []map[string]interface{}{
+		{"column": "customer", "metadata": map[string]interface{}{"KBC.description": "Customer name"}},
+		{"column": "id", "metadata": map[string]interface{}{"KBC.description": "Order ID"}},
+	}, result, "Only columns with metadata of the provider should be mapped")
+}
+
+func TestMappingFromTableColumnMetadataModelToSchemaLeavesOutUnmanagedEntries(t *testing.T) {
+	columnMetadata := map[string][]MetadataEntry{
+		"id": {
+			{ID: "1", Key: "KBC.datatype.type", Value: "INTEGER", Provider: "user"},
+			{ID: "2", Key: "KBC.description", Value: "Order ID", Provider: "user"},
+			{ID: "3", Key: "owner", Value: "sales", Provider: "user"},
+		},
+		"amount": {
+			{ID: "4", Key: "KBC.datatype.type", Value: "NUMERIC", Provider: "user"},
+		},
+	}
+	managedColumnMetadata := map[string]map[string]interface{}{
+		"id": {"KBC.description": "Order ID"},
+	}
+
+	result := mapTableColumnMetadataToSchema(columnMetadata, "user", managedColumnMetadata)
+
+	assert.Equal(t, []map[string]interface{}{
+		{"column": "id", "metadata": map[string]interface{}{"KBC.description": "Order ID", "owner": "sales"}},
+	}, result, "KBC.* entries which are not managed, such as those set by keboola_storage_table, should be left out")
+}
+
+func TestStorageTableMetadataDeleteOnlyRemovesOwnEntries(t *testing.T) {
+	var deletedPaths []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/tables/in.c-bucket.orders/metadata":
+			w.Write([]byte(`[
+				{ "id": "1", "key": "KBC.description", "value": "Orders", "provider": "user" },
+				{ "id": "2", "key": "KBC.createdBy.component.id", "value": "keboola.ex-db-mysql", "provider": "system" }
+			]`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/columns/in.c-bucket.orders.id/metadata":
+			w.Write([]byte(`[
+				{ "id": "3", "key": "KBC.description", "value": "Order ID", "provider": "user" },
+				{ "id": "4", "key": "KBC.datatype.basetype", "value": "INTEGER", "provider": "keboola.ex-db-mysql" }
+			]`))
+		case r.Method == "DELETE":
+			deletedPaths = append(deletedPaths, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableMetadata().Schema, map[string]interface{}{
+		"table_id": "in.c-bucket.orders",
+		"metadata": map[string]interface{}{
+			"KBC.description": "Orders",
+		},
+		"column_metadata": []interface{}{
+			map[string]interface{}{"column": "id", "metadata": map[string]interface{}{"KBC.description": "Order ID"}},
+		},
+	})
+	d.SetId("in.c-bucket.orders")
+
+	err := resourceKeboolaStorageTableMetadataDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/v2/storage/tables/in.c-bucket.orders/metadata/1",
+		"/v2/storage/columns/in.c-bucket.orders.id/metadata/3",
+	}, deletedPaths, "Only managed entries of the metadata provider should be removed")
+	assert.Equal(t, "", d.Id())
+}
+
+func testAccCheckStorageTableMetadataDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_table_metadata" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/tables/%s", rs.Primary.ID))
+
+		if err != nil || getResp.StatusCode != 200 {
+			continue
+		}
+
+		var storageTable StorageTable
+
+		decoder := json.NewDecoder(getResp.Body)
+		err = decoder.Decode(&storageTable)
+
+		if err == nil && len(mapMetadataEntriesToSchema(storageTable.Metadata, rs.Primary.Attributes["metadata_provider"])) > 0 {
+			return fmt.Errorf("Storage table still has metadata")
+		}
+	}
+
+	return nil
+}
+
+const testStorageTableMetadataBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "id", "customer", "amount" ]
+	}
+
+	resource "keboola_storage_table_metadata" "test_metadata" {
+		table_id = "${keboola_storage_table.test_table.id}"
+
+		metadata {
+			"KBC.description" = "Orders placed in the web shop"
+		}
+
+		column_metadata {
+			column = "id"
+
+			metadata {
+				"KBC.description" = "Order ID"
+			}
+		}
+	}`
+
+const testStorageTableMetadataUpdate = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "id", "customer", "amount" ]
+	}
+
+	resource "keboola_storage_table_metadata" "test_metadata" {
+		table_id = "${keboola_storage_table.test_table.id}"
+
+		column_metadata {
+			column = "id"
+
+			metadata {
+				"KBC.description" = "Order ID"
+			}
+		}
+
+		column_metadata {
+			column = "customer"
+
+			metadata {
+				"KBC.description" = "Name of the customer"
+			}
+		}
+	}`
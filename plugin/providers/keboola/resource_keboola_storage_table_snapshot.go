@@ -0,0 +1,145 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/plmwong/terraform-provider-keboola/plugin/providers/keboola/buffer"
+)
+
+//region Keboola API Contracts
+
+//TableSnapshot is the data model for snapshots of a table within
+//the Keboola Storage API.
+type TableSnapshot struct {
+	ID          json.Number `json:"id"`
+	Description string      `json:"description"`
+	CreatedTime string      `json:"createdTime"`
+	Table       struct {
+		ID string `json:"id"`
+	} `json:"table"`
+}
+
+//endregion
+
+func resourceKeboolaStorageTableSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaStorageTableSnapshotCreate,
+		Read:   resourceKeboolaStorageTableSnapshotRead,
+		Delete: resourceKeboolaStorageTableSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"table_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"created_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceKeboolaStorageTableSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Storage Table Snapshot in Keboola.")
+
+	createSnapshotForm := url.Values{}
+	createSnapshotForm.Add("description", d.Get("description").(string))
+
+	createSnapshotBuffer := buffer.FromForm(createSnapshotForm)
+
+	client := meta.(*KBCClient)
+	createResponse, err := client.PostToStorage(fmt.Sprintf("storage/tables/%s/snapshots", d.Get("table_id").(string)), createSnapshotBuffer)
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createSnapshotResult UploadFileResult
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createSnapshotResult)
+
+	if err != nil {
+		return err
+	}
+
+	snapshotJobResult, err := client.WaitForStorageJob(client.stopContext(), createSnapshotResult.ID, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(string(snapshotJobResult.Results.ID))
+
+	return resourceKeboolaStorageTableSnapshotRead(d, meta)
+}
+
+func resourceKeboolaStorageTableSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Storage Table Snapshots from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/snapshots/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			log.Printf("[WARN] Storage Table Snapshot %s no longer exists, removing from state.", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var tableSnapshot TableSnapshot
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&tableSnapshot)
+
+	if err != nil {
+		return err
+	}
+
+	d.Set("table_id", tableSnapshot.Table.ID)
+	d.Set("description", tableSnapshot.Description)
+	d.Set("created_time", tableSnapshot.CreatedTime)
+
+	return nil
+}
+
+func resourceKeboolaStorageTableSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Storage Table Snapshot in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/snapshots/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccStorageTableSnapshot_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageTableSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableSnapshotBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table_snapshot.test_snapshot", "description", "before destructive change"),
+					resource.TestCheckResourceAttrSet("keboola_storage_table_snapshot.test_snapshot", "created_time"),
+				),
+			},
+			{
+				ResourceName:      "keboola_storage_table_snapshot.test_snapshot",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestStorageTableSnapshotReadsSnapshotByID(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/snapshots/12345", r.URL.Path)
+		w.Write([]byte(`{
+			"id": "12345",
+			"description": "before destructive change",
+			"createdTime": "2018-05-01T10:00:00+0200",
+			"table": { "id": "in.c-bucket.table" }
+		}`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableSnapshot().Schema, map[string]interface{}{})
+	d.SetId("12345")
+
+	err := resourceKeboolaStorageTableSnapshotRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.table", d.Get("table_id"))
+	assert.Equal(t, "before destructive change", d.Get("description"))
+	assert.Equal(t, "2018-05-01T10:00:00+0200", d.Get("created_time"))
+}
+
+func TestStorageTableSnapshotReadRemovesDeletedSnapshot(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTableSnapshot().Schema, map[string]interface{}{})
+	d.SetId("12345")
+
+	err := resourceKeboolaStorageTableSnapshotRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A snapshot that no longer exists should be removed from state")
+}
+
+func TestStorageJobDecodesNumericAndStringResultIDs(t *testing.T) {
+	var snapshotJob StorageJobStatus
+	err := json.Unmarshal([]byte(`{ "id": 1, "status": "success", "results": { "id": 12345 } }`), &snapshotJob)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StorageJobResultID("12345"), snapshotJob.Results.ID, "Numeric result IDs should be decoded")
+
+	var tableJob StorageJobStatus
+	err = json.Unmarshal([]byte(`{ "id": 2, "status": "success", "results": { "id": "in.c-bucket.table" } }`), &tableJob)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StorageJobResultID("in.c-bucket.table"), tableJob.Results.ID, "String result IDs should be decoded")
+}
+
+func testAccCheckStorageTableSnapshotDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_storage_table_snapshot" {
+			continue
+		}
+
+		getResp, err := client.GetFromStorage(fmt.Sprintf("storage/snapshots/%s", rs.Primary.ID))
+
+		if err == nil && getResp.StatusCode == 200 {
+			return fmt.Errorf("Table snapshot still exists")
+		}
+	}
+
+	return nil
+}
+
+const testStorageTableSnapshotBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+	}
+
+	resource "keboola_storage_table_snapshot" "test_snapshot" {
+		table_id = "${keboola_storage_table.test_table.id}"
+		description = "before destructive change"
+	}`
@@ -1,11 +1,21 @@
 package keboola
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccStorageTable_Basic(t *testing.T) {
@@ -27,6 +37,1009 @@ func TestAccStorageTable_Basic(t *testing.T) {
 	})
 }
 
+func TestAccStorageTable_UndeclaredPrimaryKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testStorageTableUndeclaredPrimaryKey,
+				ExpectError: regexp.MustCompile(`primary_key contains column "frist"`),
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_UndeclaredPrimaryKeyInDataFile(t *testing.T) {
+	dataFile := writeTestDataFile(t, "first,second,third\n1,2,3\n")
+	defer os.Remove(dataFile)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testStorageTableUndeclaredPrimaryKeyInDataFile, dataFile),
+				ExpectError: regexp.MustCompile(`primary_key contains column "frist", which is not one of the table's columns \[first second third\]`),
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_Import(t *testing.T) {
+	dataFile := writeTestDataFile(t, "id,name\n1,first\n2,second\n")
+	defer os.Remove(dataFile)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableDataFile, dataFile),
+			},
+			{
+				ResourceName:      "keboola_storage_table.test_table",
+				ImportState:       true,
+				ImportStateVerify: true,
+				//the data file is only known to the configuration which loaded it
+				ImportStateVerifyIgnore: []string{"data_file", "data_file_hash"},
+			},
+			{
+				Config:   fmt.Sprintf(testStorageTableDataFile, dataFile),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_AddColumns(t *testing.T) {
+	var tableCreated string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreated("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "columns.#", "3"),
+				),
+			},
+			{
+				Config: testStorageTableAddColumns,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreatedUnchanged("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "columns.#", "5"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_ReorderedColumns(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableBasic,
+			},
+			{
+				Config:   testStorageTableReorderedColumns,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_DefaultCSVSettings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "delimiter", ","),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "enclosure", "\""),
+				),
+			},
+			{
+				Config:   testStorageTableBasic,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_IncrementalLoad(t *testing.T) {
+	var tableCreated string
+
+	initialDataFile := writeTestDataFile(t, "id,name\n1,first\n2,second\n")
+	defer os.Remove(initialDataFile)
+
+	appendedDataFile := writeTestDataFile(t, "id,name\n2,updated\n3,third\n")
+	defer os.Remove(appendedDataFile)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableIncremental, initialDataFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreated("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "rows_count", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testStorageTableIncremental, appendedDataFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreatedUnchanged("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "rows_count", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_Typed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableTyped,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "columns.#", "2"),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "column_definition.0.base_type", "INTEGER"),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "column_definition.1.base_type", "STRING"),
+				),
+			},
+			{
+				Config:   testStorageTableTyped,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_ColumnMetadata(t *testing.T) {
+	var tableCreated string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testStorageTableColumnMetadata, "VARCHAR", "255"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreated("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "column_metadata.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testStorageTableColumnMetadata, "INTEGER", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageTableCreatedUnchanged("keboola_storage_table.test_table", &tableCreated),
+					resource.TestCheckResourceAttr("keboola_storage_table.test_table", "column_metadata.#", "2"),
+				),
+			},
+			{
+				Config:   fmt.Sprintf(testStorageTableColumnMetadata, "INTEGER", ""),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_UndeclaredColumnMetadata(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testStorageTableUndeclaredColumnMetadata,
+				ExpectError: regexp.MustCompile(`column_metadata contains column "fourth"`),
+			},
+		},
+	})
+}
+
+func TestAccStorageTable_FromSnapshot(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageTableSnapshotDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testStorageTableFromSnapshot,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_storage_table.restored_table", "name", "restored_table"),
+					resource.TestCheckResourceAttrPair("keboola_storage_table.restored_table", "columns.#", "keboola_storage_table.test_table", "columns.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestStorageTableReadRemovesDeletedTable(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{})
+	d.SetId("in.c-bucket.deleted_table")
+
+	err := resourceKeboolaStorageTableRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A table that no longer exists should be removed from state")
+}
+
+func TestStorageTableDeleteToleratesDeletedTable(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{})
+	d.SetId("in.c-deleted_bucket.table")
+
+	err := resourceKeboolaStorageTableDelete(d, client)
+
+	assert.NoError(t, err, "Deleting a table that has already been deleted should succeed")
+	assert.Equal(t, "", d.Id())
+}
+
+func TestStorageTableDeleteWaitsForAsyncJob(t *testing.T) {
+	jobPolled := false
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/storage/tables/in.c-bucket.table":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case "/v2/storage/jobs/12345":
+			jobPolled = true
+			w.Write([]byte(`{ "id": 12345, "status": "success" }`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{})
+	d.SetId("in.c-bucket.table")
+
+	err := resourceKeboolaStorageTableDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.True(t, jobPolled, "An asynchronous delete should wait for its job to finish")
+	assert.Equal(t, "", d.Id())
+}
+
+func TestStorageTableCreateFailedLoadJobKeepsNoState(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/storage/buckets/in.c-bucket/tables-async":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "error", "error": { "message": "Snapshot 999 not found" } }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id":   "in.c-bucket",
+		"name":        "orders",
+		"columns":     []interface{}{"id", "amount"},
+		"snapshot_id": "999",
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.EqualError(t, err, "Storage job 12345 failed: Snapshot 999 not found")
+	assert.Equal(t, "", d.Id(), "A table whose load job failed should not be kept in state")
+}
+
+func TestStorageTableCreateEmptyTableSkipsFileUpload(t *testing.T) {
+	var createTableForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/buckets/in.c-bucket/tables":
+			r.ParseForm()
+			createTableForm = r.PostForm
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id", "amount"], "primaryKey": ["id"] }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/tables/in.c-bucket.orders":
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id", "amount"], "primaryKey": ["id"] }`))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id":   "in.c-bucket",
+		"name":        "orders",
+		"columns":     []interface{}{"id", "amount"},
+		"primary_key": []interface{}{"id"},
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.orders", d.Id())
+	assert.Equal(t, "orders", createTableForm.Get("name"))
+	assert.ElementsMatch(t, []string{"id", "amount"}, strings.Split(createTableForm.Get("dataString"), ","), "The header row should be sent as the data of the table")
+	assert.Equal(t, "id", createTableForm.Get("primaryKey"))
+}
+
+func TestStorageTableCreateSendsDefaultEnclosureWhenUnset(t *testing.T) {
+	var createTableForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			r.ParseForm()
+			createTableForm = r.PostForm
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id", "amount"] }`))
+		case "GET":
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id", "amount"] }`))
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "orders",
+		"columns":   []interface{}{"id", "amount"},
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\"", createTableForm.Get("enclosure"))
+	assert.Equal(t, "\"", d.Get("enclosure"))
+}
+
+func TestStorageTableCreateLoadsPipeDelimitedDataWithoutEnclosure(t *testing.T) {
+	dataFile := writeTestDataFile(t, "id|name\n1|first \"quoted\" name\n")
+	defer os.Remove(dataFile)
+
+	var loadTableForm url.Values
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "import.example.com" && r.URL.Path == "/upload-file":
+			w.Write([]byte(`{ "id": 777 }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/buckets/in.c-bucket/tables-async":
+			r.ParseForm()
+			loadTableForm = r.PostForm
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.URL.Path == "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "success", "results": { "id": "in.c-bucket.people" } }`))
+		case r.Method == "DELETE" && r.URL.Path == "/v2/storage/files/777":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/tables/in.c-bucket.people":
+			w.Write([]byte(`{ "id": "in.c-bucket.people", "name": "people", "columns": ["id", "name"] }`))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "people",
+		"columns":   []interface{}{"id", "name"},
+		"data_file": dataFile,
+		"delimiter": "|",
+		"enclosure": "",
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.people", d.Id())
+	assert.Equal(t, "|", loadTableForm.Get("delimiter"))
+	assert.Contains(t, loadTableForm, "enclosure")
+	assert.Equal(t, "", loadTableForm.Get("enclosure"), "An explicitly empty enclosure should be sent as no enclosure")
+	assert.Equal(t, "", d.Get("enclosure"), "An explicitly empty enclosure should be kept in state after the table is read")
+}
+
+func TestStorageTableCreateWithoutWaitingForLoad(t *testing.T) {
+	dataFile := writeTestDataFile(t, "id,name\n1,first\n")
+	defer os.Remove(dataFile)
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "import.example.com" && r.URL.Path == "/upload-file":
+			w.Write([]byte(`{ "id": 777 }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/buckets/in.c-bucket/tables-async":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id":     "in.c-bucket",
+		"name":          "people",
+		"columns":       []interface{}{"id", "name"},
+		"data_file":     dataFile,
+		"wait_for_load": false,
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket.people", d.Id())
+	assert.Equal(t, "12345", d.Get("load_job_id"))
+	assert.NotEmpty(t, d.Get("data_file_hash"))
+}
+
+func TestStorageTableReadReconcilesLoadNotWaitedFor(t *testing.T) {
+	cases := []struct {
+		jobStatus         string
+		expectedID        string
+		expectedLoadJobID string
+		expectTableRead   bool
+	}{
+		{"processing", "in.c-bucket.people", "12345", false},
+		{"success", "in.c-bucket.people", "", true},
+		{"error", "", "12345", false},
+	}
+
+	for _, c := range cases {
+		tableRead := false
+
+		server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/storage/jobs/12345":
+				w.Write([]byte(fmt.Sprintf(`{ "id": 12345, "status": %q, "error": { "message": "Invalid CSV" } }`, c.jobStatus)))
+			case "/v2/storage/tables/in.c-bucket.people":
+				tableRead = true
+				w.Write([]byte(`{ "id": "in.c-bucket.people", "name": "people", "columns": ["id", "name"] }`))
+			default:
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		})
+
+		d := resourceKeboolaStorageTable().Data(&terraform.InstanceState{
+			ID: "in.c-bucket.people",
+			Attributes: map[string]string{
+				"bucket_id":     "in.c-bucket",
+				"name":          "people",
+				"wait_for_load": "false",
+				"load_job_id":   "12345",
+			},
+		})
+
+		err := resourceKeboolaStorageTableRead(d, client)
+		server.Close()
+
+		assert.NoError(t, err, c.jobStatus)
+		assert.Equal(t, c.expectedID, d.Id(), c.jobStatus)
+		assert.Equal(t, c.expectedLoadJobID, d.Get("load_job_id"), c.jobStatus)
+		assert.Equal(t, c.expectTableRead, tableRead, c.jobStatus)
+	}
+}
+
+func TestUpdatePrimaryKeyReplacesKey(t *testing.T) {
+	var requests []string
+	var createdPrimaryKey []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == "POST" {
+			r.ParseForm()
+			createdPrimaryKey = r.PostForm["columns[]"]
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	err := updatePrimaryKey(client, "in.c-bucket.orders", []string{"id"}, []string{"id", "line"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /v2/storage/tables/in.c-bucket.orders/primary-key", "POST /v2/storage/tables/in.c-bucket.orders/primary-key"}, requests)
+	assert.Equal(t, []string{"id", "line"}, createdPrimaryKey)
+}
+
+func TestUpdatePrimaryKeyRestoresOldKeyWhenNewKeyIsRejected(t *testing.T) {
+	var createdPrimaryKeys [][]string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		r.ParseForm()
+		createdPrimaryKeys = append(createdPrimaryKeys, r.PostForm["columns[]"])
+
+		if len(createdPrimaryKeys) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{ "error": "Primary key cannot be created, there are duplicate values in column customer_id" }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	err := updatePrimaryKey(client, "in.c-bucket.orders", []string{"id"}, []string{"customer_id"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to create primary key [customer_id] on Storage Table in.c-bucket.orders")
+	assert.Contains(t, err.Error(), "there are duplicate values in column customer_id", "The error from the API should be included")
+	assert.Equal(t, [][]string{{"customer_id"}, {"id"}}, createdPrimaryKeys, "The old primary key should be restored")
+}
+
+func TestImportDataFileDeletesUploadedFile(t *testing.T) {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(dataFile.Name())
+
+	dataFile.WriteString("id,amount\n1,100\n")
+	dataFile.Close()
+
+	var deletedPaths []string
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "import.example.com" && r.URL.Path == "/upload-file":
+			w.Write([]byte(`{ "id": 777 }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/tables/in.c-bucket.orders/import-async":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.URL.Path == "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "success" }`))
+		case r.Method == "DELETE":
+			deletedPaths = append(deletedPaths, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "orders",
+		"columns":   []interface{}{"id", "amount"},
+		"data_file": dataFile.Name(),
+	})
+	d.SetId("in.c-bucket.orders")
+
+	err = importDataFile(d, client, dataFile.Name())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/v2/storage/files/777"}, deletedPaths, "The uploaded file should be deleted once it has been loaded")
+}
+
+func TestStorageTableUpdateKeepsOldDataFileWhenLoadFails(t *testing.T) {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(dataFile.Name())
+
+	dataFile.WriteString("id,amount\n1,100\n")
+	dataFile.Close()
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "import.example.com" && r.URL.Path == "/upload-file":
+			w.Write([]byte(`{ "id": 777 }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/tables/in.c-bucket.orders/import-async":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.URL.Path == "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "error", "error": { "message": "Duplicate values in primary key" } }`))
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "orders",
+		"data_file": dataFile.Name(),
+	})
+	d.SetId("in.c-bucket.orders")
+
+	err = resourceKeboolaStorageTableUpdate(d, client)
+
+	assert.EqualError(t, err, "Storage job 12345 failed: Duplicate values in primary key")
+	assert.Equal(t, "", d.Get("data_file"), "The data file which failed to load should not be kept in state")
+	assert.Equal(t, "", d.Get("data_file_hash"), "The data file should be loaded again by the next apply")
+}
+
+func TestStorageTableUpdateKeepsAddedColumnsWhenAddingColumnFails(t *testing.T) {
+	var addedColumns []string
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v2/storage/tables/in.c-bucket.orders/columns" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+
+		r.ParseForm()
+		addedColumns = append(addedColumns, r.PostForm.Get("name"))
+
+		if len(addedColumns) > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{ "error": "Invalid column name" }`))
+			return
+		}
+
+		w.Write([]byte(`{ "id": 12345 }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id": "in.c-bucket",
+		"name":      "orders",
+		"columns":   []interface{}{"id", "customer", "amount"},
+	})
+	d.SetId("in.c-bucket.orders")
+
+	err := resourceKeboolaStorageTableUpdate(d, client)
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"amount", "customer"}, addedColumns, "Columns should be added in a stable order, stopping at the first which fails")
+	assert.Equal(t, []string{"amount"}, AsStringArray(d.Get("columns").(*schema.Set).List()), "Only the columns which were added should be kept in state")
+}
+
+func TestOrderColumns(t *testing.T) {
+	assert.Equal(t, []string{"id", "customer", "amount"}, orderColumns([]string{"amount", "id", "customer"}, []string{"id", "customer", "amount"}), "Columns should be ordered as in the header of the data file")
+	assert.Equal(t, []string{"id", "amount", "customer"}, orderColumns([]string{"customer", "id", "amount"}, []string{"id"}), "Columns not in the header should follow in alphabetical order")
+	assert.Equal(t, []string{"amount", "customer"}, orderColumns([]string{"customer", "amount"}, nil))
+}
+
+func TestDistributionKeyErrorNamesBackend(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/storage/buckets/in.c-bucket":
+			w.Write([]byte(`{ "id": "in.c-bucket", "backend": "snowflake" }`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	err := distributionKeyError(client, "in.c-bucket", []string{"id"}, fmt.Errorf("400 distributionKey is not supported"))
+
+	assert.EqualError(t, err, "Unable to create Storage Table in bucket in.c-bucket with distribution_key [id], check that the snowflake backend of the bucket supports distribution keys: 400 distributionKey is not supported")
+
+	err = distributionKeyError(client, "in.c-deleted_bucket", []string{"id"}, fmt.Errorf("400 distributionKey is not supported"))
+
+	assert.EqualError(t, err, "400 distributionKey is not supported", "The original error should be kept when the bucket cannot be read")
+}
+
+func TestStorageTableCreateEmptyTableSendsTransactional(t *testing.T) {
+	var createTableForm url.Values
+
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/buckets/in.c-bucket":
+			w.Write([]byte(`{ "id": "in.c-bucket", "backend": "redshift" }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/buckets/in.c-bucket/tables":
+			r.ParseForm()
+			createTableForm = r.PostForm
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id"], "transactional": true }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/tables/in.c-bucket.orders":
+			w.Write([]byte(`{ "id": "in.c-bucket.orders", "name": "orders", "columns": ["id"], "transactional": true }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id":     "in.c-bucket",
+		"name":          "orders",
+		"columns":       []interface{}{"id"},
+		"transactional": true,
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", createTableForm.Get("transactional"), "A transactional table should be created as transactional")
+	assert.Equal(t, true, d.Get("transactional"))
+}
+
+func TestCheckTransactionalSupported(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/storage/buckets/in.c-redshift":
+			w.Write([]byte(`{ "id": "in.c-redshift", "backend": "redshift" }`))
+		case "/v2/storage/buckets/in.c-snowflake":
+			w.Write([]byte(`{ "id": "in.c-snowflake", "backend": "snowflake" }`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	assert.NoError(t, checkTransactionalSupported(client, "in.c-redshift"))
+	assert.EqualError(t, checkTransactionalSupported(client, "in.c-snowflake"), "transactional is only supported for Storage Tables in redshift buckets, bucket in.c-snowflake uses the snowflake backend")
+	assert.Error(t, checkTransactionalSupported(client, "in.c-missing"), "A bucket which cannot be read should fail the create")
+}
+
+func TestStorageTableCreateRejectsUnsupportedTransactional(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/buckets/in.c-bucket":
+			w.Write([]byte(`{ "id": "in.c-bucket", "backend": "snowflake" }`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaStorageTable().Schema, map[string]interface{}{
+		"bucket_id":     "in.c-bucket",
+		"name":          "orders",
+		"columns":       []interface{}{"id"},
+		"transactional": true,
+	})
+
+	err := resourceKeboolaStorageTableCreate(d, client)
+
+	assert.EqualError(t, err, "transactional is only supported for Storage Tables in redshift buckets, bucket in.c-bucket uses the snowflake backend")
+	assert.Equal(t, "", d.Id(), "The table should not be created")
+}
+
+func TestStorageTableDecodesStatistics(t *testing.T) {
+	getTableResponse := `{
+		"id": "in.c-test.orders",
+		"name": "orders",
+		"primaryKey": ["id"],
+		"distributionKey": ["id"],
+		"columns": ["id", "amount"],
+		"rowsCount": 1234,
+		"dataSizeBytes": 56320,
+		"created": "2019-07-01T09:15:00+0200",
+		"lastImportDate": "2019-07-20T10:00:00+0200",
+		"lastChangeDate": "2019-07-20T10:00:05+0200"
+	}`
+
+	var storageTable StorageTable
+	err := json.Unmarshal([]byte(getTableResponse), &storageTable)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, storageTable.DistributionKey, "distributionKey should be decoded")
+	assert.Equal(t, 1234, storageTable.RowsCount, "rowsCount should be decoded")
+	assert.Equal(t, 56320, storageTable.DataSizeBytes, "dataSizeBytes should be decoded")
+	assert.Equal(t, "2019-07-01T07:15:00Z", storageTable.Created.RFC3339(), "created should be decoded")
+	assert.Equal(t, "2019-07-20T08:00:00Z", storageTable.LastImportDate.RFC3339(), "lastImportDate should be decoded")
+	assert.Equal(t, "2019-07-20T08:00:05Z", storageTable.LastChangeDate.RFC3339(), "lastChangeDate should be decoded")
+}
+
+func TestStorageTableImportSetsDefaults(t *testing.T) {
+	d := resourceKeboolaStorageTable().Data(&terraform.InstanceState{ID: "in.c-bucket.orders"})
+
+	imported, err := resourceKeboolaStorageTableImport(d, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, "in.c-bucket", d.Get("bucket_id"))
+	assert.Equal(t, "orders", d.Get("name"))
+	assert.Equal(t, true, d.Get("wait_for_load"), "Settings which are not read from Keboola should have their defaults")
+	assert.Equal(t, false, d.Get("incremental"))
+}
+
+func TestParseStorageTableID(t *testing.T) {
+	bucketID, name, err := parseStorageTableID("in.c-bucket.table")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in.c-bucket", bucketID)
+	assert.Equal(t, "table", name)
+
+	bucketID, name, err = parseStorageTableID("out.c-bucket.with.dots.table")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "out.c-bucket.with.dots", bucketID, "Bucket ID should include everything before the last dot")
+	assert.Equal(t, "table", name, "Table name should be the last segment of the ID")
+
+	_, _, err = parseStorageTableID("table")
+	assert.Error(t, err, "Table ID without a bucket should be rejected")
+
+	_, _, err = parseStorageTableID("in.c-bucket.")
+	assert.Error(t, err, "Table ID without a table name should be rejected")
+}
+
+func TestValidateDataFileHeader(t *testing.T) {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(dataFile.Name())
+
+	dataFile.WriteString("\"first\";\"second\";\"third\"\r\n\"1\";\"2\";\"3\"\r\n")
+	dataFile.Close()
+
+	columns := []string{"third", "second", "first"}
+
+	assert.NoError(t, validateDataFileHeader(dataFile.Name(), ";", "\"", columns), "Header should match columns when read with the correct delimiter")
+	assert.Error(t, validateDataFileHeader(dataFile.Name(), ",", "\"", columns), "Header should not match columns when read with the wrong delimiter")
+	assert.NoError(t, validateDataFileHeader(dataFile.Name(), ",", "\"", nil), "Header should not be checked when no columns are declared")
+}
+
+func TestReadDataFileHeaderHonoursEnclosure(t *testing.T) {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(dataFile.Name())
+
+	dataFile.WriteString("\"id\",\"last, first\",\"say \"\"hi\"\"\"\n\"1\",\"2\",\"3\"\n")
+	dataFile.Close()
+
+	headerColumns, err := readDataFileHeader(dataFile.Name(), ",", "\"")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "last, first", "say \"hi\""}, headerColumns, "Enclosed column names should keep the delimiter and escaped enclosures")
+
+	singleQuoted, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(singleQuoted.Name())
+
+	singleQuoted.WriteString("'id';'a;b';'say \"hi\"'\n")
+	singleQuoted.Close()
+
+	headerColumns, err = readDataFileHeader(singleQuoted.Name(), ";", "'")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "a;b", "say \"hi\""}, headerColumns, "An enclosure other than double quotes should be honoured")
+
+	headerColumns, err = readDataFileHeader(singleQuoted.Name(), ";", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"'id'", "'a", "b'", "'say \"hi\"'"}, headerColumns, "Without an enclosure the header should only be split on the delimiter")
+
+	empty, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(empty.Name())
+	empty.Close()
+
+	_, err = readDataFileHeader(empty.Name(), ",", "\"")
+	assert.Error(t, err, "A file without a header row should be rejected")
+}
+
+func TestValidateDelimiterAndEnclosure(t *testing.T) {
+	validDelimiters := []string{",", ";", "\t", "|", "\\t", "\\"}
+	for _, delimiter := range validDelimiters {
+		_, errors := validateDelimiter(delimiter, "delimiter")
+		assert.Empty(t, errors, "Delimiter %q should be valid", delimiter)
+	}
+
+	invalidDelimiters := []string{"", ";;", "\",\"", "\\t\\t", "\\x"}
+	for _, delimiter := range invalidDelimiters {
+		_, errors := validateDelimiter(delimiter, "delimiter")
+		assert.NotEmpty(t, errors, "Delimiter %q should be invalid", delimiter)
+	}
+
+	_, errors := validateEnclosure("", "enclosure")
+	assert.Empty(t, errors, "An empty enclosure should be valid")
+
+	_, errors = validateEnclosure("\"\"", "enclosure")
+	assert.NotEmpty(t, errors, "A multi-character enclosure should be invalid")
+}
+
+func TestCheckColumnsDeclared(t *testing.T) {
+	columns := []string{"id", "name", "created"}
+
+	assert.NoError(t, checkColumnsDeclared("primary_key", []string{"id", "created"}, columns), "Declared columns should be accepted")
+	assert.NoError(t, checkColumnsDeclared("primary_key", nil, columns), "An empty primary key should be accepted")
+	assert.NoError(t, checkColumnsDeclared("primary_key", []string{"id", config.UnknownVariableValue, ""}, columns), "Unknown and empty column names should be skipped")
+
+	err := checkColumnsDeclared("indexed_columns", []string{"id", "nmae"}, columns)
+	assert.EqualError(t, err, `indexed_columns contains column "nmae", which is not one of the table's columns [id name created]`)
+}
+
+func TestHashDataFileChangesWithContents(t *testing.T) {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+	defer os.Remove(dataFile.Name())
+
+	dataFile.WriteString("first,second\n1,2\n")
+	dataFile.Close()
+
+	originalHash, err := hashDataFile(dataFile.Name())
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(dataFile.Name(), []byte("first,second\n1,2\n3,4\n"), 0644)
+	assert.NoError(t, err)
+
+	updatedHash, err := hashDataFile(dataFile.Name())
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, originalHash, updatedHash, "Hash should change when the data file contents change")
+}
+
+func TestReadCSVSettingKeepsDefaults(t *testing.T) {
+	assert.Equal(t, ",", readCSVSetting("", false, "", defaultStorageTableDelimiter), "Empty value from the API should fall back to the default for an unset delimiter")
+	assert.Equal(t, ",", readCSVSetting(",", true, "", defaultStorageTableDelimiter), "Empty value from the API should keep the default delimiter")
+	assert.Equal(t, ";", readCSVSetting(";", true, "", defaultStorageTableDelimiter), "Empty value from the API should keep the configured delimiter")
+	assert.Equal(t, "|", readCSVSetting(";", true, "|", defaultStorageTableDelimiter), "Changed value from the API should be detected as drift")
+	assert.Equal(t, "", readCSVSetting("", true, "", defaultStorageTableEnclosure), "An explicitly empty enclosure should be kept")
+	assert.Equal(t, "\"", readCSVSetting("", false, "", defaultStorageTableEnclosure), "An enclosure that was never set should fall back to the default")
+}
+
+func TestStorageTableCSVSettingsFallBackToDefaults(t *testing.T) {
+	delimiter, enclosure := storageTableCSVSettings("", "")
+	assert.Equal(t, ",", delimiter, "An unset delimiter should be sent as the default")
+	assert.Equal(t, "", enclosure, "An empty enclosure should be sent as no enclosure")
+
+	delimiter, enclosure = storageTableCSVSettings(";", "'")
+	assert.Equal(t, ";", delimiter)
+	assert.Equal(t, "'", enclosure)
+}
+
+func TestUnescapeDelimiter(t *testing.T) {
+	assert.Equal(t, "\t", unescapeDelimiter("\\t"), "An escaped tab should be interpreted as a tab")
+	assert.Equal(t, "\t", unescapeDelimiter("\t"))
+	assert.Equal(t, ";", unescapeDelimiter(";"))
+	assert.Equal(t, "\"", unescapeDelimiter("\""), "A quote should not be treated as the end of an escaped value")
+	assert.Equal(t, "\\", unescapeDelimiter("\\"), "A lone backslash is not an escape sequence, so should be kept")
+
+	delimiter, _ := storageTableCSVSettings("\\t", "")
+	assert.Equal(t, "\t", delimiter, "An escaped tab should be sent to Keboola as a tab")
+}
+
+func writeTestDataFile(t *testing.T, contents string) string {
+	dataFile, err := ioutil.TempFile("", "keboola-table-*.csv")
+	assert.NoError(t, err)
+
+	dataFile.WriteString(contents)
+	dataFile.Close()
+
+	return dataFile.Name()
+}
+
 func testAccCheckStorageTableDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*KBCClient)
 
@@ -60,3 +1073,191 @@ const testStorageTableBasic = `
   	name = "test_table"
   	columns = [ "first", "second", "third" ]
 	}`
+
+const testStorageTableDataFile = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "id" ]
+		data_file = "%s"
+	}`
+
+const testStorageTableUndeclaredPrimaryKey = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "frist" ]
+		columns = [ "first", "second", "third" ]
+	}`
+
+const testStorageTableUndeclaredPrimaryKeyInDataFile = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "frist" ]
+		data_file = "%s"
+	}`
+
+const testStorageTableColumnMetadata = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+
+		column_metadata {
+			column = "first"
+			type = "INTEGER"
+			nullable = false
+		}
+
+		column_metadata {
+			column = "second"
+			type = "%s"
+			length = "%s"
+		}
+	}`
+
+const testStorageTableUndeclaredColumnMetadata = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+
+		column_metadata {
+			column = "fourth"
+			type = "INTEGER"
+		}
+	}`
+
+const testStorageTableAddColumns = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+  	name = "test_table"
+  	columns = [ "first", "second", "third", "fourth", "fifth" ]
+	}`
+
+const testStorageTableReorderedColumns = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+  	name = "test_table"
+  	columns = [ "third", "first", "second" ]
+	}`
+
+const testStorageTableFromSnapshot = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "first", "second", "third" ]
+	}
+
+	resource "keboola_storage_table_snapshot" "test_snapshot" {
+		table_id = "${keboola_storage_table.test_table.id}"
+	}
+
+	resource "keboola_storage_table" "restored_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "restored_table"
+		snapshot_id = "${keboola_storage_table_snapshot.test_snapshot.id}"
+	}`
+
+const testStorageTableIncremental = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "id" ]
+		data_file = "%s"
+		incremental = true
+
+		timeouts {
+			create = "30m"
+			update = "30m"
+		}
+	}`
+
+const testStorageTableTyped = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "out"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		primary_key = [ "id" ]
+
+		column_definition {
+			name = "id"
+			base_type = "INTEGER"
+			nullable = false
+		}
+
+		column_definition {
+			name = "name"
+			base_type = "STRING"
+		}
+	}`
@@ -106,7 +106,7 @@ func resourceKeboolaTransformation() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
-			"output": &outputSchema,
+			"output": &transformationOutputSchema,
 			"input":  &inputSchema,
 		},
 	}
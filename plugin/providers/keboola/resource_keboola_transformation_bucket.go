@@ -78,15 +78,15 @@ func resourceKeboolaTransformBucketCreate(d *schema.ResourceData, meta interface
 func resourceKeboolaTransformBucketRead(d *schema.ResourceData, meta interface{}) error {
 	log.Println("[INFO] Reading Transformation Buckets from Keboola.")
 
-	client := meta.(*KBCClient)
-	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/transformation/configs/%s", d.Id()))
-
 	if d.Id() == "" {
 		return nil
 	}
 
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromStorage(fmt.Sprintf("storage/components/transformation/configs/%s", d.Id()))
+
 	if hasErrors(err, getResponse) {
-		if getResponse.StatusCode == 404 {
+		if err == nil && getResponse.StatusCode == 404 {
 			d.SetId("")
 			return nil
 		}
@@ -111,7 +111,7 @@ func resourceKeboolaTransformBucketRead(d *schema.ResourceData, meta interface{}
 }
 
 func resourceKeboolaTransformBucketUpdate(d *schema.ResourceData, meta interface{}) error {
-	log.Println("[INFO] Updating Transformation Bucket in Keboola.")
+	log.Printf("[INFO] Updating Transformation Bucket in Keboola: %s", d.Id())
 
 	updateBucketForm := url.Values{}
 	updateBucketForm.Add("name", d.Get("name").(string))
@@ -135,7 +135,7 @@ func resourceKeboolaTransformBucketDelete(d *schema.ResourceData, meta interface
 	client := meta.(*KBCClient)
 	destroyResponse, err := client.DeleteFromStorage(fmt.Sprintf("storage/components/transformation/configs/%s", d.Id()))
 
-	if hasErrors(err, destroyResponse) {
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
 		return extractError(err, destroyResponse)
 	}
 
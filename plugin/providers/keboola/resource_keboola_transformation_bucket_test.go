@@ -3,10 +3,13 @@ package keboola
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccTransformationBucket_Basic(t *testing.T) {
@@ -57,6 +60,42 @@ func TestAccTransformationBucket_Update(t *testing.T) {
 	})
 }
 
+func TestTransformationBucketReadRemovesDeletedBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/storage/components/transformation/configs/1234", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaTransformationBucket().Schema, map[string]interface{}{
+		"name": "test name",
+	})
+	d.SetId("1234")
+
+	err := resourceKeboolaTransformBucketRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "A transformation bucket that no longer exists should be removed from state")
+}
+
+func TestTransformationBucketDeleteIgnoresDeletedBucket(t *testing.T) {
+	server, client := newTestStorageServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaTransformationBucket().Schema, map[string]interface{}{
+		"name": "test name",
+	})
+	d.SetId("1234")
+
+	err := resourceKeboolaTransformBucketDelete(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+}
+
 func testAccCheckTransformationBucketExists(n string, bucket *TransformationBucket) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccTransformation_Basic(t *testing.T) {
@@ -30,6 +32,23 @@ func TestAccTransformation_Basic(t *testing.T) {
 	})
 }
 
+func TestTransformationOutputDestinationMustBeOutputTable(t *testing.T) {
+	outputDestination := func(r *schema.Resource) *schema.Schema {
+		return r.Schema["output"].Elem.(*schema.Resource).Schema["destination"]
+	}
+
+	validateDestination := outputDestination(resourceKeboolaTransformation()).ValidateFunc
+
+	_, errors := validateDestination("out.c-sales.totals", "destination")
+	assert.Empty(t, errors)
+
+	_, errors = validateDestination("in.c-sales.totals", "destination")
+	assert.NotEmpty(t, errors, "Transformations should only write to output buckets")
+
+	assert.Nil(t, outputDestination(resourceKeboolaGoodDataUserManagement()).ValidateFunc, "Other output mappings should not be limited to output buckets")
+	assert.Nil(t, outputDestination(resourceKeboolaPythonTransformation()).ValidateFunc)
+}
+
 func testAccCheckTransformationDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*KBCClient)
 
@@ -0,0 +1,281 @@
+package keboola
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//region Keboola API Contracts
+
+//Workspace is the data model for a workspace (sandbox) within the Keboola Sandboxes API.
+type Workspace struct {
+	ID                   string           `json:"id,omitempty"`
+	Type                 string           `json:"type"`
+	Size                 string           `json:"size,omitempty"`
+	ExpirationAfterHours int              `json:"expirationAfterHours,omitempty"`
+	Active               bool             `json:"active,omitempty"`
+	PhysicalID           string           `json:"physicalId,omitempty"`
+	Host                 string           `json:"host,omitempty"`
+	URL                  string           `json:"url,omitempty"`
+	User                 string           `json:"user,omitempty"`
+	Password             string           `json:"password,omitempty"`
+	DeletedTimestamp     string           `json:"deletedTimestamp,omitempty"`
+	WorkspaceDetails     WorkspaceDetails `json:"workspaceDetails,omitempty"`
+}
+
+//WorkspaceDetails holds the connection details of the storage behind a workspace.
+type WorkspaceDetails struct {
+	Connection struct {
+		Database  string `json:"database"`
+		Schema    string `json:"schema"`
+		Warehouse string `json:"warehouse"`
+	} `json:"connection"`
+}
+
+//WorkspaceLoad loads tables from Storage in to the storage behind a workspace.
+type WorkspaceLoad struct {
+	Input []WorkspaceLoadInput `json:"input"`
+}
+
+type WorkspaceLoadInput struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+//endregion
+
+func resourceKeboolaWorkspace() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKeboolaWorkspaceCreate,
+		Read:   resourceKeboolaWorkspaceRead,
+		Delete: resourceKeboolaWorkspaceDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateWorkspaceType,
+			},
+			"size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "small",
+				ValidateFunc: validateWorkspaceSize,
+			},
+			//the workspace is removed by Keboola once it expires, after which it is recreated by the next apply
+			"expiration_after_hours": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"input": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"destination": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"schema": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"warehouse": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceKeboolaWorkspaceCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Creating Workspace in Keboola.")
+
+	client := meta.(*KBCClient)
+
+	workspace := Workspace{
+		Type:                 d.Get("type").(string),
+		Size:                 d.Get("size").(string),
+		ExpirationAfterHours: d.Get("expiration_after_hours").(int),
+	}
+
+	workspaceJSON, err := json.Marshal(workspace)
+
+	if err != nil {
+		return err
+	}
+
+	createResponse, err := client.PostToSandboxes("sandboxes", bytes.NewBuffer(workspaceJSON))
+
+	if hasErrors(err, createResponse) {
+		return extractError(err, createResponse)
+	}
+
+	var createdWorkspace Workspace
+
+	decoder := json.NewDecoder(createResponse.Body)
+	err = decoder.Decode(&createdWorkspace)
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(createdWorkspace.ID)
+
+	//the password is only returned when the workspace is created
+	d.Set("password", createdWorkspace.Password)
+
+	if err := loadWorkspaceInput(d, client, createdWorkspace.PhysicalID); err != nil {
+		return err
+	}
+
+	return resourceKeboolaWorkspaceRead(d, meta)
+}
+
+//loadWorkspaceInput loads the input tables in to the storage behind the workspace.
+func loadWorkspaceInput(d *schema.ResourceData, client *KBCClient, physicalID string) error {
+	inputs := d.Get("input").([]interface{})
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	workspaceLoad := WorkspaceLoad{Input: make([]WorkspaceLoadInput, 0, len(inputs))}
+
+	for _, input := range inputs {
+		config := input.(map[string]interface{})
+
+		workspaceLoad.Input = append(workspaceLoad.Input, WorkspaceLoadInput{
+			Source:      config["source"].(string),
+			Destination: config["destination"].(string),
+		})
+	}
+
+	workspaceLoadJSON, err := json.Marshal(workspaceLoad)
+
+	if err != nil {
+		return err
+	}
+
+	loadResponse, err := client.PostJSONToStorage(fmt.Sprintf("storage/workspaces/%s/load", physicalID), bytes.NewBuffer(workspaceLoadJSON))
+
+	if hasErrors(err, loadResponse) {
+		return fmt.Errorf("Unable to load input in to Workspace %s: %v", d.Id(), extractError(err, loadResponse))
+	}
+
+	return waitForAcceptedStorageJob(client, loadResponse, d.Timeout(schema.TimeoutCreate))
+}
+
+func resourceKeboolaWorkspaceRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Reading Workspace from Keboola.")
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*KBCClient)
+	getResponse, err := client.GetFromSandboxes(fmt.Sprintf("sandboxes/%s", d.Id()))
+
+	if hasErrors(err, getResponse) {
+		if err == nil && getResponse.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return extractError(err, getResponse)
+	}
+
+	var workspace Workspace
+
+	decoder := json.NewDecoder(getResponse.Body)
+	err = decoder.Decode(&workspace)
+
+	if err != nil {
+		return err
+	}
+
+	if workspace.DeletedTimestamp != "" {
+		log.Printf("[WARN] Workspace %s has expired or been deleted, removing it from state.", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("type", workspace.Type)
+
+	//only python and r workspaces are sized, so a snowflake workspace keeps the configured size
+	if workspace.Size != "" {
+		d.Set("size", workspace.Size)
+	}
+
+	d.Set("active", workspace.Active)
+	d.Set("host", workspace.Host)
+	d.Set("url", workspace.URL)
+	d.Set("database", workspace.WorkspaceDetails.Connection.Database)
+	d.Set("schema", workspace.WorkspaceDetails.Connection.Schema)
+	d.Set("warehouse", workspace.WorkspaceDetails.Connection.Warehouse)
+	d.Set("user", workspace.User)
+
+	return nil
+}
+
+func resourceKeboolaWorkspaceDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Workspace in Keboola: %s", d.Id())
+
+	client := meta.(*KBCClient)
+	destroyResponse, err := client.DeleteFromSandboxes(fmt.Sprintf("sandboxes/%s", d.Id()))
+
+	if hasErrors(err, destroyResponse) && (err != nil || destroyResponse.StatusCode != 404) {
+		return extractError(err, destroyResponse)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package keboola
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccWorkspace_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckWorkspaceDestroy,
+			testAccCheckStorageTableDestroy,
+			testAccCheckStorageBucketDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testWorkspaceBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("keboola_workspace.test_workspace", "type", "snowflake"),
+					resource.TestCheckResourceAttrSet("keboola_workspace.test_workspace", "host"),
+					resource.TestCheckResourceAttrSet("keboola_workspace.test_workspace", "user"),
+					resource.TestCheckResourceAttrSet("keboola_workspace.test_workspace", "password"),
+				),
+			},
+		},
+	})
+}
+
+func TestWorkspaceCreateLoadsInputTables(t *testing.T) {
+	var workspaceLoad WorkspaceLoad
+
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.Host == "sandboxes.example.com" && r.URL.Path == "/sandboxes":
+			w.Write([]byte(`{ "id": "1234", "type": "snowflake", "physicalId": "5678", "user": "SANDBOX_1234", "password": "secret" }`))
+		case r.Method == "POST" && r.URL.Path == "/v2/storage/workspaces/5678/load":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &workspaceLoad)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{ "id": 12345, "status": "waiting" }`))
+		case r.Method == "GET" && r.URL.Path == "/v2/storage/jobs/12345":
+			w.Write([]byte(`{ "id": 12345, "status": "success" }`))
+		case r.Method == "GET" && r.Host == "sandboxes.example.com" && r.URL.Path == "/sandboxes/1234":
+			w.Write([]byte(`{
+				"id": "1234",
+				"type": "snowflake",
+				"active": true,
+				"host": "keboola.snowflakecomputing.com",
+				"user": "SANDBOX_1234",
+				"workspaceDetails": { "connection": { "database": "KEBOOLA_1", "schema": "WORKSPACE_5678", "warehouse": "KEBOOLA_PROD" } }
+			}`))
+		default:
+			t.Errorf("Unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaWorkspace().Schema, map[string]interface{}{
+		"type": "snowflake",
+		"input": []interface{}{
+			map[string]interface{}{"source": "in.c-bucket.orders", "destination": "orders"},
+		},
+	})
+
+	err := resourceKeboolaWorkspaceCreate(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []WorkspaceLoadInput{{Source: "in.c-bucket.orders", Destination: "orders"}}, workspaceLoad.Input, "Input tables should be loaded in to the workspace")
+	assert.Equal(t, "1234", d.Id())
+	assert.Equal(t, "secret", d.Get("password"), "The password returned on creation should be kept")
+	assert.Equal(t, "small", d.Get("size"), "An unsized snowflake workspace should keep the configured size")
+	assert.Equal(t, "WORKSPACE_5678", d.Get("schema"))
+	assert.Equal(t, true, d.Get("active"))
+}
+
+func TestWorkspaceReadRemovesExpiredWorkspace(t *testing.T) {
+	server, client := newTestKeboolaServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "id": "1234", "type": "python", "size": "small", "deletedTimestamp": "2020-01-01T00:00:00+01:00" }`))
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceKeboolaWorkspace().Schema, map[string]interface{}{})
+	d.SetId("1234")
+
+	err := resourceKeboolaWorkspaceRead(d, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id(), "An expired workspace should be removed from state")
+}
+
+func TestValidateWorkspaceType(t *testing.T) {
+	_, errors := validateWorkspaceType("python", "type")
+	assert.Empty(t, errors)
+
+	_, errors = validateWorkspaceType("julia", "type")
+	assert.NotEmpty(t, errors)
+}
+
+func testAccCheckWorkspaceDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KBCClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "keboola_workspace" {
+			continue
+		}
+
+		getResp, err := client.GetFromSandboxes(fmt.Sprintf("sandboxes/%s", rs.Primary.ID))
+
+		if err != nil || getResp.StatusCode != 200 {
+			continue
+		}
+
+		var workspace Workspace
+
+		decoder := json.NewDecoder(getResp.Body)
+		err = decoder.Decode(&workspace)
+
+		if err == nil && workspace.DeletedTimestamp == "" {
+			return fmt.Errorf("Workspace still exists")
+		}
+	}
+
+	return nil
+}
+
+const testWorkspaceBasic = `
+	resource "keboola_storage_bucket" "test_bucket" {
+		name = "test_bucket_name"
+		description = "test description"
+		stage = "in"
+		backend = "snowflake"
+	}
+
+	resource "keboola_storage_table" "test_table" {
+		bucket_id = "${keboola_storage_bucket.test_bucket.id}"
+		name = "test_table"
+		columns = [ "id", "amount" ]
+	}
+
+	resource "keboola_workspace" "test_workspace" {
+		type = "snowflake"
+		expiration_after_hours = 1
+
+		input {
+			source = "${keboola_storage_table.test_table.id}"
+			destination = "test_table"
+		}
+	}`
@@ -0,0 +1,131 @@
+package keboola
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//region Keboola API Contracts
+
+//ColumnDefinition is the native datatype of a column in a typed Storage Table.
+type ColumnDefinition struct {
+	Type     string `json:"type"`
+	Length   string `json:"length,omitempty"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+}
+
+//TypedColumn is a column of a typed Storage Table.
+type TypedColumn struct {
+	Name       string           `json:"name"`
+	Definition ColumnDefinition `json:"definition"`
+	BaseType   string           `json:"basetype"`
+}
+
+//TableDefinition is the definition used to create a typed Storage Table.
+type TableDefinition struct {
+	Name             string             `json:"name"`
+	PrimaryKeysNames []string           `json:"primaryKeysNames"`
+	Columns          []TypedColumn      `json:"columns"`
+	Distribution     *TableDistribution `json:"distribution,omitempty"`
+}
+
+//TableDistribution is how the rows of a typed table are distributed, on backends which support it.
+type TableDistribution struct {
+	Type                     string   `json:"type"`
+	DistributionColumnsNames []string `json:"distributionColumnsNames"`
+}
+
+//endregion
+
+var columnDefinitionSchema = schema.Schema{
+	Type:          schema.TypeList,
+	Optional:      true,
+	ForceNew:      true,
+	ConflictsWith: []string{"columns", "data_file", "snapshot_id"},
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"base_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateColumnBaseType,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"length": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"nullable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			"default": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	},
+}
+
+func mapColumnDefinitionSchemaToModel(columnDefinitions []interface{}) []TypedColumn {
+	mappedColumns := make([]TypedColumn, 0, len(columnDefinitions))
+
+	for _, columnDefinition := range columnDefinitions {
+		config := columnDefinition.(map[string]interface{})
+
+		//the native type defaults to the base type, which every backend accepts as a native type too
+		nativeType, _ := config["type"].(string)
+		if nativeType == "" {
+			nativeType = config["base_type"].(string)
+		}
+
+		mappedColumn := TypedColumn{
+			Name:     config["name"].(string),
+			BaseType: config["base_type"].(string),
+			Definition: ColumnDefinition{
+				Type:     nativeType,
+				Length:   config["length"].(string),
+				Nullable: config["nullable"].(bool),
+				Default:  config["default"].(string),
+			},
+		}
+
+		mappedColumns = append(mappedColumns, mappedColumn)
+	}
+
+	return mappedColumns
+}
+
+func mapColumnDefinitionModelToSchema(columns []TypedColumn) []map[string]interface{} {
+	var columnDefinitions []map[string]interface{}
+
+	for _, column := range columns {
+		columnDefinition := map[string]interface{}{
+			"name":      column.Name,
+			"base_type": column.BaseType,
+			"type":      column.Definition.Type,
+			"length":    column.Definition.Length,
+			"nullable":  column.Definition.Nullable,
+			"default":   column.Definition.Default,
+		}
+
+		columnDefinitions = append(columnDefinitions, columnDefinition)
+	}
+
+	return columnDefinitions
+}
@@ -0,0 +1,63 @@
+package keboola
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappingFromColumnDefinitionToModel(t *testing.T) {
+	columnDefinitions := []interface{}{
+		map[string]interface{}{
+			"name":      "id",
+			"base_type": "INTEGER",
+			"length":    "",
+			"nullable":  false,
+			"default":   "",
+		},
+		map[string]interface{}{
+			"name":      "name",
+			"base_type": "STRING",
+			"length":    "255",
+			"nullable":  true,
+			"default":   "unknown",
+		},
+	}
+
+	result := mapColumnDefinitionSchemaToModel(columnDefinitions)
+
+	assert.Equal(t, "id", result[0].Name, "Original name and mapped name should match")
+	assert.Equal(t, "INTEGER", result[0].BaseType, "Original base_type and mapped basetype should match")
+	assert.Equal(t, "INTEGER", result[0].Definition.Type, "Native type should default to the base type")
+	assert.False(t, result[0].Definition.Nullable, "Original nullable and mapped nullable should match")
+	assert.Equal(t, "255", result[1].Definition.Length, "Original length and mapped length should match")
+	assert.Equal(t, "unknown", result[1].Definition.Default, "Original default and mapped default should match")
+
+	tableDefinitionJSON, err := json.Marshal(TableDefinition{Name: "typed", PrimaryKeysNames: []string{"id"}, Columns: result[:1]})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "typed",
+		"primaryKeysNames": ["id"],
+		"columns": [{ "name": "id", "basetype": "INTEGER", "definition": { "type": "INTEGER", "nullable": false } }]
+	}`, string(tableDefinitionJSON))
+}
+
+func TestMappingFromColumnDefinitionModelToSchema(t *testing.T) {
+	columns := []TypedColumn{
+		{
+			Name:       "created_at",
+			BaseType:   "TIMESTAMP",
+			Definition: ColumnDefinition{Type: "TIMESTAMP_NTZ", Length: "9", Nullable: true},
+		},
+	}
+
+	result := mapColumnDefinitionModelToSchema(columns)
+
+	assert.Equal(t, "created_at", result[0]["name"], "Original name and mapped name should match")
+	assert.Equal(t, "TIMESTAMP", result[0]["base_type"], "Base type should be read from basetype, not the native type")
+	assert.Equal(t, "TIMESTAMP_NTZ", result[0]["type"], "Native type should be read from the definition")
+	assert.Equal(t, "9", result[0]["length"], "Original length and mapped length should match")
+	assert.Equal(t, true, result[0]["nullable"], "Original nullable and mapped nullable should match")
+}
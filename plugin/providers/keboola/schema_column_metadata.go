@@ -0,0 +1,149 @@
+package keboola
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const (
+	columnMetadataProvider = "user"
+
+	datatypeTypeMetadataKey     = "KBC.datatype.type"
+	datatypeLengthMetadataKey   = "KBC.datatype.length"
+	datatypeNullableMetadataKey = "KBC.datatype.nullable"
+)
+
+//region Keboola API Contracts
+
+//MetadataEntry is a single item of metadata attached to a table or column.
+type MetadataEntry struct {
+	ID       json.Number `json:"id,omitempty"`
+	Key      string      `json:"key"`
+	Value    string      `json:"value"`
+	Provider string      `json:"provider,omitempty"`
+}
+
+//ColumnsMetadata is the payload used to set the metadata of columns of a Storage Table.
+type ColumnsMetadata struct {
+	Provider        string                     `json:"provider"`
+	ColumnsMetadata map[string][]MetadataEntry `json:"columnsMetadata"`
+}
+
+//endregion
+
+var columnMetadataSchema = schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"column": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"length": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"nullable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	},
+}
+
+func mapColumnMetadataSchemaToModel(columnMetadata []interface{}) map[string][]MetadataEntry {
+	mappedMetadata := make(map[string][]MetadataEntry, len(columnMetadata))
+
+	for _, item := range columnMetadata {
+		config := item.(map[string]interface{})
+
+		entries := []MetadataEntry{
+			{Key: datatypeTypeMetadataKey, Value: config["type"].(string)},
+			{Key: datatypeNullableMetadataKey, Value: formatMetadataBoolean(config["nullable"].(bool))},
+		}
+
+		if length := config["length"].(string); length != "" {
+			entries = append(entries, MetadataEntry{Key: datatypeLengthMetadataKey, Value: length})
+		}
+
+		mappedMetadata[config["column"].(string)] = entries
+	}
+
+	return mappedMetadata
+}
+
+//mapColumnMetadataModelToSchema maps the datatype metadata of the given columns, ignoring any columns
+//without a datatype and any metadata which was not set by a user (e.g. by a component).
+func mapColumnMetadataModelToSchema(columnMetadata map[string][]MetadataEntry, columns []string) []map[string]interface{} {
+	mappedMetadata := make([]map[string]interface{}, 0, len(columns))
+
+	for _, column := range columns {
+		item := map[string]interface{}{
+			"column":   column,
+			"length":   "",
+			"nullable": true,
+		}
+
+		for _, entry := range columnMetadata[column] {
+			if entry.Provider != columnMetadataProvider {
+				continue
+			}
+
+			switch entry.Key {
+			case datatypeTypeMetadataKey:
+				item["type"] = entry.Value
+			case datatypeLengthMetadataKey:
+				item["length"] = entry.Value
+			case datatypeNullableMetadataKey:
+				item["nullable"] = entry.Value == "1" || entry.Value == "true"
+			}
+		}
+
+		if _, ok := item["type"]; ok {
+			mappedMetadata = append(mappedMetadata, item)
+		}
+	}
+
+	return mappedMetadata
+}
+
+//staleColumnMetadataKeys returns, for each column, the metadata keys which were previously set but
+//are no longer declared, and so need to be removed from the column.
+func staleColumnMetadataKeys(oldMetadata map[string][]MetadataEntry, newMetadata map[string][]MetadataEntry) map[string][]string {
+	staleKeys := make(map[string][]string)
+
+	for column, oldEntries := range oldMetadata {
+		for _, oldEntry := range oldEntries {
+			if !containsMetadataKey(newMetadata[column], oldEntry.Key) {
+				staleKeys[column] = append(staleKeys[column], oldEntry.Key)
+			}
+		}
+	}
+
+	return staleKeys
+}
+
+func containsMetadataKey(entries []MetadataEntry, key string) bool {
+	for _, entry := range entries {
+		if entry.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatMetadataBoolean(value bool) string {
+	if value {
+		return "1"
+	}
+
+	return "0"
+}
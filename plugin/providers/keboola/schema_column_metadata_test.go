@@ -0,0 +1,97 @@
+package keboola
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappingFromColumnMetadataToModel(t *testing.T) {
+	columnMetadata := []interface{}{
+		map[string]interface{}{
+			"column":   "id",
+			"type":     "INTEGER",
+			"length":   "",
+			"nullable": false,
+		},
+		map[string]interface{}{
+			"column":   "name",
+			"type":     "VARCHAR",
+			"length":   "255",
+			"nullable": true,
+		},
+	}
+
+	result := mapColumnMetadataSchemaToModel(columnMetadata)
+
+	columnsMetadataJSON, err := json.Marshal(ColumnsMetadata{Provider: columnMetadataProvider, ColumnsMetadata: result})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"provider": "user",
+		"columnsMetadata": {
+			"id": [
+				{ "key": "KBC.datatype.type", "value": "INTEGER" },
+				{ "key": "KBC.datatype.nullable", "value": "0" }
+			],
+			"name": [
+				{ "key": "KBC.datatype.type", "value": "VARCHAR" },
+				{ "key": "KBC.datatype.nullable", "value": "1" },
+				{ "key": "KBC.datatype.length", "value": "255" }
+			]
+		}
+	}`, string(columnsMetadataJSON))
+}
+
+func TestMappingFromColumnMetadataModelToSchema(t *testing.T) {
+	var columnMetadata map[string][]MetadataEntry
+	err := json.Unmarshal([]byte(`{
+		"id": [
+			{ "id": "1", "key": "KBC.datatype.type", "value": "INTEGER", "provider": "user" },
+			{ "id": "2", "key": "KBC.datatype.nullable", "value": "0", "provider": "user" }
+		],
+		"name": [
+			{ "id": "3", "key": "KBC.datatype.type", "value": "VARCHAR", "provider": "user" },
+			{ "id": "4", "key": "KBC.datatype.length", "value": "255", "provider": "user" }
+		],
+		"created": [
+			{ "id": "5", "key": "KBC.datatype.type", "value": "TIMESTAMP", "provider": "keboola.ex-db-snowflake" }
+		]
+	}`), &columnMetadata)
+
+	assert.NoError(t, err)
+
+	result := mapColumnMetadataModelToSchema(columnMetadata, []string{"id", "name", "created"})
+
+	assert.Equal(t, []map[string]interface{}{
+		{"column": "id", "type": "INTEGER", "length": "", "nullable": false},
+		{"column": "name", "type": "VARCHAR", "length": "255", "nullable": true},
+	}, result, "Only datatype metadata set by a user should be mapped")
+
+	assert.Empty(t, mapColumnMetadataModelToSchema(columnMetadata, nil), "Metadata of columns which are not declared should be ignored")
+}
+
+func TestStaleColumnMetadataKeys(t *testing.T) {
+	oldMetadata := map[string][]MetadataEntry{
+		"id": {
+			{Key: datatypeTypeMetadataKey, Value: "INTEGER"},
+			{Key: datatypeNullableMetadataKey, Value: "0"},
+		},
+		"name": {
+			{Key: datatypeTypeMetadataKey, Value: "VARCHAR"},
+			{Key: datatypeLengthMetadataKey, Value: "255"},
+		},
+	}
+
+	newMetadata := map[string][]MetadataEntry{
+		"name": {
+			{Key: datatypeTypeMetadataKey, Value: "STRING"},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"id":   {datatypeTypeMetadataKey, datatypeNullableMetadataKey},
+		"name": {datatypeLengthMetadataKey},
+	}, staleColumnMetadataKeys(oldMetadata, newMetadata))
+}
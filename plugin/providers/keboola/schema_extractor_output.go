@@ -0,0 +1,76 @@
+package keboola
+
+import "github.com/hashicorp/terraform/helper/schema"
+
+//ExtractorOutputTable is the output mapping of the table written by a file based extractor.
+type ExtractorOutputTable struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Incremental bool     `json:"incremental"`
+	PrimaryKey  []string `json:"primary_key"`
+}
+
+//extractorOutputSchema is the table which the files downloaded by a file based extractor
+//(e.g. from S3 or GCS) are written to.
+var extractorOutputSchema = schema.Schema{
+	Type:     schema.TypeList,
+	Required: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"destination": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"incremental": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"primary_key": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	},
+}
+
+//mapExtractorOutputToModel maps the output of a file based extractor to its output mapping, along with
+//the name the extractor saves the downloaded files as, which is the name of the destination table.
+func mapExtractorOutputToModel(output map[string]interface{}) ([]ExtractorOutputTable, string, error) {
+	destination := output["destination"].(string)
+
+	_, saveAs, err := parseStorageTableID(destination)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	outputTables := []ExtractorOutputTable{
+		{
+			Source:      saveAs,
+			Destination: destination,
+			Incremental: output["incremental"].(bool),
+			PrimaryKey:  AsStringArray(output["primary_key"].([]interface{})),
+		},
+	}
+
+	return outputTables, saveAs, nil
+}
+
+func mapExtractorOutputToSchema(outputTables []ExtractorOutputTable) []map[string]interface{} {
+	outputs := make([]map[string]interface{}, 0, len(outputTables))
+
+	for _, outputTable := range outputTables {
+		outputs = append(outputs, map[string]interface{}{
+			"destination": outputTable.Destination,
+			"incremental": outputTable.Incremental,
+			"primary_key": outputTable.PrimaryKey,
+		})
+	}
+
+	return outputs
+}
@@ -2,47 +2,56 @@ package keboola
 
 import "github.com/hashicorp/terraform/helper/schema"
 
-var outputSchema = schema.Schema{
-	Type:     schema.TypeList,
-	Optional: true,
-	Elem: &schema.Resource{
-		Schema: map[string]*schema.Schema{
-			"source": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"destination": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"delete_where_column": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"delete_where_values": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+var outputSchema = newOutputSchema(nil)
+
+//transformationOutputSchema is the output mapping of keboola_transformation, which can only write to
+//tables in output buckets.
+var transformationOutputSchema = newOutputSchema(validateOutputTableDestination)
+
+func newOutputSchema(validateDestination schema.SchemaValidateFunc) schema.Schema {
+	return schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"source": {
+					Type:     schema.TypeString,
+					Required: true,
 				},
-			},
-			"delete_where_operator": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"primary_key": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+				"destination": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateDestination,
+				},
+				"delete_where_column": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"delete_where_values": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"delete_where_operator": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"primary_key": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"incremental": {
+					Type:     schema.TypeBool,
+					Optional: true,
 				},
-			},
-			"incremental": {
-				Type:     schema.TypeBool,
-				Optional: true,
 			},
 		},
-	},
+	}
 }
 
 func mapOutputSchemaToModel(outputs []interface{}) []Output {
@@ -1,6 +1,8 @@
 package keboola
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -16,9 +18,19 @@ type SnowflakeDatabaseParameters struct {
 	Warehouse         string `json:"warehouse"`
 }
 
+//snowflakePasswordKey is the key of a plain text password within the Snowflake credentials. Only the ciphertext of
+//the password is kept in state, in place of the password.
+const snowflakePasswordKey = "snowflake_db_parameters.password"
+
+var snowflakeSecrets = []secretAttribute{
+	{key: snowflakePasswordKey},
+}
+
 var snowflakeDBParametersSchema = schema.Schema{
-	Type:     schema.TypeMap,
-	Optional: true,
+	Type:      schema.TypeMap,
+	Optional:  true,
+	Computed:  true,
+	Sensitive: true,
 	Elem: &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"hostname": {
@@ -48,10 +60,15 @@ var snowflakeDBParametersSchema = schema.Schema{
 			},
 			"hashed_password": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateKBCEncryptedValue,
 			},
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
 		},
 	},
 }
@@ -85,3 +102,126 @@ func mapSnowflakeCredentialsToConfiguration(source map[string]interface{}) Snowf
 
 	return databaseParameters
 }
+
+//encryptSnowflakeCredentials replaces a plain text password in the credentials with a value encrypted
+//for the given component, so that the password is never saved to the configuration in plain text.
+func encryptSnowflakeCredentials(source map[string]interface{}, componentID string, client *KBCClient) (map[string]interface{}, error) {
+	credentials := make(map[string]interface{}, len(source))
+
+	for key, value := range source {
+		credentials[key] = value
+	}
+
+	password, ok := credentials["password"].(string)
+	delete(credentials, "password")
+
+	if !ok || password == "" {
+		return credentials, nil
+	}
+
+	encryptedPassword, err := client.EncryptValue(componentID, password)
+
+	if err != nil {
+		return nil, fmt.Errorf("Unable to encrypt Snowflake password: %v", err)
+	}
+
+	credentials["hashed_password"] = encryptedPassword
+
+	return credentials, nil
+}
+
+//mapSnowflakeCredentialsToSchema maps the credentials read back from Keboola. When the password was set in plain
+//text, its ciphertext is kept in place of the password, rather than as the hashed_password.
+func mapSnowflakeCredentialsToSchema(databaseCredentials SnowflakeDatabaseParameters, d *schema.ResourceData) map[string]interface{} {
+	dbParameters := make(map[string]interface{})
+
+	if databaseCredentials.HostName != "" {
+		dbParameters["hostname"] = databaseCredentials.HostName
+	}
+	if databaseCredentials.Port != "" {
+		dbParameters["port"] = databaseCredentials.Port
+	}
+	if databaseCredentials.Database != "" {
+		dbParameters["database"] = databaseCredentials.Database
+	}
+	if databaseCredentials.Schema != "" {
+		dbParameters["schema"] = databaseCredentials.Schema
+	}
+	if databaseCredentials.Warehouse != "" {
+		dbParameters["warehouse"] = databaseCredentials.Warehouse
+	}
+	if databaseCredentials.Username != "" {
+		dbParameters["username"] = databaseCredentials.Username
+	}
+
+	if databaseCredentials.EncryptedPassword != "" {
+		if password, _ := d.Get(snowflakePasswordKey).(string); password != "" {
+			dbParameters["password"] = databaseCredentials.EncryptedPassword
+		} else {
+			dbParameters["hashed_password"] = databaseCredentials.EncryptedPassword
+		}
+	}
+
+	return dbParameters
+}
+
+//snowflakeCredentialsToSave gets the configured credentials, with a password which has not changed since it was
+//last encrypted replaced by its ciphertext from state, so that it is not encrypted again on every update.
+func snowflakeCredentialsToSave(d *schema.ResourceData, componentID string, client *KBCClient) (map[string]interface{}, error) {
+	credentials := d.Get("snowflake_db_parameters").(map[string]interface{})
+	previous, _ := d.GetChange("snowflake_db_parameters")
+	secretHashes, _ := d.Get("secret_hashes").(map[string]interface{})
+
+	ciphertext, _ := previous.(map[string]interface{})["password"].(string)
+	password, _ := credentials["password"].(string)
+
+	if client.secretUnchanged(d.Id(), snowflakePasswordKey, ciphertext, password, secretHashes) {
+		unchanged := make(map[string]interface{}, len(credentials))
+
+		for key, value := range credentials {
+			unchanged[key] = value
+		}
+
+		unchanged["password"] = ciphertext
+		credentials = unchanged
+	}
+
+	return encryptSnowflakeCredentials(credentials, componentID, client)
+}
+
+//snowflakeCredentialsUnchanged checks whether the configured credentials only differ from those in state by a
+//plain text password which is the one last encrypted as the ciphertext kept in its place.
+func snowflakeCredentialsUnchanged(client *KBCClient, resourceID string, previous map[string]interface{}, configured map[string]interface{}, secretHashes map[string]interface{}) bool {
+	if len(previous) != len(configured) {
+		return false
+	}
+
+	for key, value := range configured {
+		if key != "password" && previous[key] != value {
+			return false
+		}
+	}
+
+	ciphertext, _ := previous["password"].(string)
+	password, _ := configured["password"].(string)
+
+	return client.secretUnchanged(resourceID, snowflakePasswordKey, ciphertext, password, secretHashes)
+}
+
+//customizeSnowflakeCredentialsDiff removes the diff between a plain text password and the ciphertext kept in its
+//place in state, unless the password has changed since it was encrypted. As the credentials are a map, the diff
+//can only be removed as a whole, so it is kept when any of the other credentials have changed too.
+func customizeSnowflakeCredentialsDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("snowflake_db_parameters") || !d.NewValueKnown("snowflake_db_parameters") {
+		return nil
+	}
+
+	previous, configured := d.GetChange("snowflake_db_parameters")
+	secretHashes, _ := d.Get("secret_hashes").(map[string]interface{})
+
+	if snowflakeCredentialsUnchanged(meta.(*KBCClient), d.Id(), previous.(map[string]interface{}), configured.(map[string]interface{}), secretHashes) {
+		return d.Clear("snowflake_db_parameters")
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package keboola
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const hashedContentPrefix = "sha256:"
+
+//hashContent is used as the StateFunc of attributes holding content which is not worth keeping in state
+//(e.g. code), so that only a hash of it is kept instead. Values which have already been hashed are kept
+//as they are.
+func hashContent(v interface{}) string {
+	content, _ := v.(string)
+
+	if strings.HasPrefix(content, hashedContentPrefix) {
+		return content
+	}
+
+	hash := sha256.Sum256([]byte(content))
+
+	return hashedContentPrefix + hex.EncodeToString(hash[:])
+}
+
+//unescapeDelimiter is used as the StateFunc of CSV delimiters, interpreting escape sequences (e.g. a \t which
+//reached the configuration unescaped, such as from a variable file) as the character they stand for, as the
+//Storage API only accepts the character itself. Values which are not valid escape sequences are kept as they are.
+func unescapeDelimiter(v interface{}) string {
+	delimiter, _ := v.(string)
+
+	if !strings.Contains(delimiter, "\\") {
+		return delimiter
+	}
+
+	unescaped, err := strconv.Unquote(`"` + delimiter + `"`)
+
+	if err != nil {
+		return delimiter
+	}
+
+	return unescaped
+}
@@ -1,6 +1,8 @@
 package keboola
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"unicode"
 
@@ -17,7 +19,46 @@ func stripWhitespace(str string) string {
 	}, str)
 }
 
+//suppressEquivalentJSON suppresses the diff between two JSON documents which only differ in their
+//formatting or in the order of their keys. Values which are not valid JSON are compared ignoring whitespace.
 //noinspection GoUnusedParameter
 func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
-	return stripWhitespace(old) == stripWhitespace(new)
+	var oldValue, newValue interface{}
+
+	if json.Unmarshal([]byte(old), &oldValue) != nil || json.Unmarshal([]byte(new), &newValue) != nil {
+		return stripWhitespace(old) == stripWhitespace(new)
+	}
+
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+//suppressReorderedList suppresses the diff of a list of strings which holds the same values as in state,
+//only in a different order, for lists where Keboola gives no meaning to the order (e.g. returning
+//columns in their physical order rather than in the order they were declared).
+func suppressReorderedList(k, old, new string, d *schema.ResourceData) bool {
+	attribute := k
+	if separator := strings.LastIndex(k, "."); separator > 0 {
+		attribute = k[:separator]
+	}
+
+	oldList, newList := d.GetChange(attribute)
+
+	return sameStringSet(AsStringArray(oldList.([]interface{})), AsStringArray(newList.([]interface{})))
+}
+
+//suppressUnsetDefault suppresses the diff between an attribute of an existing resource that was never
+//set in state (e.g. created before the attribute had a default) and the attribute's default value.
+//New resources keep the diff, so that the default can be told apart from an explicitly empty value.
+func suppressUnsetDefault(defaultValue string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return d.Id() != "" && old == "" && new == defaultValue
+	}
+}
+
+//suppressEquivalentScript suppresses the diff between two scripts which are the same once normalised, i.e. which
+//only differ in formatting that the language of the script ignores.
+func suppressEquivalentScript(normalise func(string) string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return normalise(old) == normalise(new)
+	}
 }
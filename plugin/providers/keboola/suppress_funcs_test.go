@@ -0,0 +1,14 @@
+package keboola
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressEquivalentJSON(t *testing.T) {
+	assert.True(t, suppressEquivalentJSON("configuration", `{"a":1,"b":[1,2]}`, "{ \"b\": [ 1, 2 ],\n  \"a\": 1 }", nil), "Reformatted and reordered keys should not be a diff")
+	assert.False(t, suppressEquivalentJSON("configuration", `{"b":[1,2]}`, `{"b":[2,1]}`, nil), "Reordered arrays should be a diff")
+	assert.False(t, suppressEquivalentJSON("configuration", `{"a":1}`, `{"a":"1"}`, nil))
+	assert.True(t, suppressEquivalentJSON("configuration", `not json`, `not  json`, nil), "Invalid JSON should be compared ignoring whitespace")
+}
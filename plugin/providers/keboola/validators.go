@@ -1,8 +1,13 @@
 package keboola
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 func validateAccessTokenBucketPermissions(v interface{}, k string) (ws []string, errors []error) {
@@ -29,13 +34,75 @@ func validateStorageBucketStage(v interface{}, k string) (ws []string, errors []
 	return
 }
 
+//storageBucketBackends are the backends a bucket can be created on, depending on those enabled for the project.
+var storageBucketBackends = []string{"snowflake", "redshift", "bigquery", "synapse", "exasol", "teradata", "mysql"}
+
 func validateStorageBucketBackend(v interface{}, k string) (ws []string, errors []error) {
 	if value := v.(string); value != "" {
-		if value != "snowflake" && value != "mysql" && value != "redshift" {
-			errors = append(errors, fmt.Errorf(
-				"%q must be set to one of %s, %s or %s, got %q",
-				k, "snowflake", "mysql", "redshift", value))
+		for _, backend := range storageBucketBackends {
+			if value == backend {
+				return
+			}
 		}
+
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, got %q",
+			k, strings.Join(storageBucketBackends, ", "), value))
+	}
+
+	return
+}
+
+func validateStorageBucketSharing(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "organization" && value != "organization-project" && value != "specific-projects" && value != "specific-users" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s, %s or %s, got %q",
+			k, "organization", "organization-project", "specific-projects", "specific-users", value))
+	}
+
+	return
+}
+
+func validateColumnBaseType(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	switch value {
+	case "STRING", "INTEGER", "NUMERIC", "FLOAT", "BOOLEAN", "DATE", "TIMESTAMP":
+	default:
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of STRING, INTEGER, NUMERIC, FLOAT, BOOLEAN, DATE or TIMESTAMP, got %q",
+			k, value))
+	}
+
+	return
+}
+
+func validateDelimiter(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if utf8.RuneCountInString(unescapeDelimiter(value)) != 1 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a single character (use \"\\t\" for a tab), got %q", k, value))
+	}
+
+	return
+}
+
+func validateEnclosure(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if utf8.RuneCountInString(value) > 1 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a single character, or empty for no enclosure, got %q", k, value))
+	}
+
+	return
+}
+
+func validateStorageTableAliasFilterOperator(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "eq" && value != "ne" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s or %s, got %q",
+			k, "eq", "ne", value))
 	}
 
 	return
@@ -72,3 +139,165 @@ func validateFTPConnectionType(v interface{}, k string) (ws []string, errors []e
 
 	return
 }
+
+func validateFileExists(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if info, err := os.Stat(value); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q must be the path to an existing file, got %q: %v", k, value, err))
+	} else if info.IsDir() {
+		errors = append(errors, fmt.Errorf(
+			"%q must be the path to a file, got directory %q", k, value))
+	}
+
+	return
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := time.ParseDuration(value); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a valid duration (e.g. 30m, 1h), got %q", k, value))
+	}
+
+	return
+}
+
+func validatePositiveInt(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+	if value <= 0 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be greater than 0, got %d", k, value))
+	}
+
+	return
+}
+
+var outputTableDestinationPattern = regexp.MustCompile(`^out\.c-.+\.[^.]+$`)
+
+func validateOutputTableDestination(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !outputTableDestinationPattern.MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a table in an output bucket (e.g. out.c-bucket.table), got %q", k, value))
+	}
+
+	return
+}
+
+func validateCronSchedule(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if len(strings.Fields(value)) != 5 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a crontab schedule with five fields (e.g. 0 6 * * *), got %q", k, value))
+	}
+
+	return
+}
+
+func validateWorkspaceType(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "snowflake" && value != "python" && value != "r" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s or %s, got %q",
+			k, "snowflake", "python", "r", value))
+	}
+
+	return
+}
+
+func validateWorkspaceSize(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "small" && value != "medium" && value != "large" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s or %s, got %q",
+			k, "small", "medium", "large", value))
+	}
+
+	return
+}
+
+func validateCodeTransformationBackendSize(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "xsmall" && value != "small" && value != "medium" && value != "large" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s, %s or %s, got %q",
+			k, "xsmall", "small", "medium", "large", value))
+	}
+
+	return
+}
+
+func validateGenericExtractorAuthenticationType(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "basic" && value != "api_key" && value != "oauth" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s or %s, got %q",
+			k, "basic", "api_key", "oauth", value))
+	}
+
+	return
+}
+
+func validateGenericExtractorAPIKeyLocation(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "query" && value != "header" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s or %s, got %q",
+			k, "query", "header", value))
+	}
+
+	return
+}
+
+func validateGenericExtractorPaginationMethod(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "offset" && value != "pagenum" && value != "response.url" && value != "response.param" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be set to one of %s, %s, %s or %s, got %q",
+			k, "offset", "pagenum", "response.url", "response.param", value))
+	}
+
+	return
+}
+
+func validateJSON(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !json.Valid([]byte(value)) {
+		errors = append(errors, fmt.Errorf(
+			"%q must be valid JSON, got %q", k, value))
+	}
+
+	return
+}
+
+//validateGCPServiceAccountKey checks that a service account key is the JSON key file of a Google Cloud
+//service account. The value is a secret, so is never included in the error. A key which has already
+//been encrypted by Keboola (starting with KBC::) cannot be checked, so is accepted as it is.
+func validateGCPServiceAccountKey(v interface{}, k string) (ws []string, errors []error) {
+	if isEncryptedValue(v.(string)) {
+		return
+	}
+
+	var serviceAccountKey map[string]interface{}
+
+	if err := json.Unmarshal([]byte(v.(string)), &serviceAccountKey); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q must be the JSON key file of a service account, but is not valid JSON: %v", k, err))
+		return
+	}
+
+	if serviceAccountKey["type"] != "service_account" {
+		errors = append(errors, fmt.Errorf(
+			"%q must be the JSON key file of a service account, but its type is not \"service_account\"", k))
+	}
+
+	for _, field := range []string{"project_id", "private_key", "client_email"} {
+		if value, ok := serviceAccountKey[field].(string); !ok || value == "" {
+			errors = append(errors, fmt.Errorf(
+				"%q must be the JSON key file of a service account, but is missing %q", k, field))
+		}
+	}
+
+	return
+}